@@ -0,0 +1,5 @@
+package constants
+
+// AgentVersion is set during build
+// using "-ldflags" (see ".goreleaser.yaml").
+var AgentVersion = "dev"
@@ -1,6 +1,12 @@
 package constants
 
+import "time"
+
 const (
+	// Name of the development environment used when
+	// no name is passed (instances may host multiple ones)
+	DevEnvDefaultName = "default"
+
 	DevEnvRecodeUserName                      = "recode"
 	DevEnvRecodeUserAuthorizedSSHKeysFilePath = "/home/recode/.ssh/authorized_keys"
 
@@ -8,16 +14,92 @@ const (
 	DevEnvDockerImageName                   = "recode-dev-env-image"
 	DevEnvDockerContainerName               = "recode-dev-env-container"
 	DevEnvDockerContainerEntrypointFilePath = "/recode_entrypoint.sh"
+	DevEnvDockerContainerStopTimeout        = 10 * time.Second
 
 	DevEnvWorkspaceDirPath = "/home/recode/workspace"
+	// Repositories removed from the workspace during
+	// incremental syncs may be archived here
+	DevEnvWorkspaceArchiveDirPath = "/home/recode/.workspace-archive"
+
+	DevEnvWorkspaceConfigDirPath      = "/home/recode/.workspace-config"
+	DevEnvWorkspaceConfigHooksDirPath = DevEnvWorkspaceConfigDirPath + "/hooks"
+	// The result of the last run of each hook is written here
+	// (readable from the container given that the dir is mounted)
+	DevEnvWorkspaceConfigHooksResultsDirPath = DevEnvWorkspaceConfigDirPath + "/hooks-results"
+	DevEnvWorkspaceConfigFilePath            = DevEnvWorkspaceConfigDirPath + "/recode.workspace"
+	DevEnvVSCodeWorkspaceConfigFilePath      = DevEnvWorkspaceConfigDirPath + "/recode.code-workspace"
 
-	DevEnvWorkspaceConfigDirPath        = "/home/recode/.workspace-config"
-	DevEnvWorkspaceConfigHooksDirPath   = DevEnvWorkspaceConfigDirPath + "/hooks"
-	DevEnvWorkspaceConfigFilePath       = DevEnvWorkspaceConfigDirPath + "/recode.workspace"
-	DevEnvVSCodeWorkspaceConfigFilePath = DevEnvWorkspaceConfigDirPath + "/recode.code-workspace"
+	// The "init" hook file name is set in "entities"
+	DevEnvRepositoryStartHookFileName  = "start.sh"
+	DevEnvRepositoryAttachHookFileName = "attach.sh"
+	DevEnvRepositoryStopHookFileName   = "stop.sh"
+	// Written in the ".git" dir of the repositories once
+	// their "init" hook has succeeded (run once per clone)
+	DevEnvInitHookMarkerFileName = "recode-init-hook-run"
+
+	// Set in the "dev_env.Dockerfile" files to override the
+	// timeout of a hook (eg: "sh.recode.hooks.init.timeout=20m")
+	DevEnvDockerfilesHookTimeoutLabelKeyFormat = "sh.recode.hooks.%s.timeout"
+	// Same exit code than the "timeout" command
+	DevEnvHookTimeoutExitCode = 124
+	// Size of the end of the hook output kept in the results
+	DevEnvHookResultOutputTailSize = 4 * 1024 // 4KB
+	// Set in the "dev_env.Dockerfile" files to run the hooks of a
+	// repository after the ones of other repositories of the workspace
+	// (eg: "sh.recode.hooks.depends_on=shared, acme/lib")
+	DevEnvDockerfilesHooksDependOnLabelKey = "sh.recode.hooks.depends_on"
+	// Max number of hooks run concurrently when not set in requests
+	DevEnvHooksDefaultParallelism = 4
 
 	DevEnvGitHubPublicSSHKeyFilePath = "/home/recode/.ssh/recode_github.pub"
 	DevEnvGitHubPublicGPGKeyFilePath = "/home/recode/.gnupg/recode_github_gpg_public.pgp"
+
+	// Completed with the hosts of the git
+	// providers used in the development environments
+	DevEnvSSHConfigFilePath     = "/home/recode/.ssh/config"
+	DevEnvSSHKnownHostsFilePath = "/home/recode/.ssh/known_hosts"
+
+	// Default retry policy used for clones, image builds
+	// and container starts (intervals are randomized
+	// by +/- the jitter factor)
+	DevEnvRetryMaxAttempts     = 5
+	DevEnvRetryInitialInterval = 2 * time.Second
+	DevEnvRetryMaxInterval     = 30 * time.Second
+	DevEnvRetryMaxElapsedTime  = 3 * time.Minute
+	DevEnvRetryMultiplier      = 2
+	DevEnvRetryJitterFactor    = 0.2
+
+	// Start times of the last builds of the instance (all development
+	// environments share the same Docker daemon and build cache)
+	DevEnvBuildHistoryFilePath = "/home/recode/.build-history.json"
+	// The images of the last successful builds of each development
+	// environment ("<name>.json"). Kept outside of the workspace
+	// config dir given that it is cleared during full syncs.
+	DevEnvDockerImagesHistoryDirPath = "/home/recode/.images-history"
+	// The dangling images and build cache not used since
+	// the oldest of the last N builds are removed
+	DevEnvBuildCacheDefaultKeepBuilds = 5
+	// The images of the last N successful builds of each
+	// development environment are tagged and kept for rollbacks
+	DevEnvDockerImagesDefaultKeepImages = 3
+
+	// One file per secret named after the secret ID.
+	// Exposed to BuildKit builds as secret mounts.
+	DevEnvBuildSecretsDirPath = "/home/recode/.build-secrets"
+	// Replaces the secrets found in the replies
+	DevEnvBuildSecretsRedactedValue = "[REDACTED]"
+
+	// Set in the user config Dockerfile to pass build args to
+	// all the builds (eg: "sh.recode.build_args=NODE_VERSION=18, GO_VERSION=1.18")
+	DevEnvDockerfilesBuildArgsLabelKey = "sh.recode.build_args"
+	// Looked up next to each built Dockerfile ("KEY=value" lines)
+	DevEnvBuildArgsFileName = "build_args.env"
+	// Build args set by the agent (cannot be overridden)
+	DevEnvReservedBuildArgsPrefix = "RECODE_"
+
+	// A warning is sent for build contexts larger than this size
+	// (".git" or "node_modules" directories not in ".dockerignore")
+	DevEnvBuildContextSizeWarningThreshold = 100 * 1024 * 1024
 )
 
 var (
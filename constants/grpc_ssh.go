@@ -1,12 +1,36 @@
 package constants
 
+import "time"
+
 const (
 	GRPCServerAddrProtocol = "unix"
 	GRPCServerAddr         = "/tmp/recode_grpc.sock"
 
+	// Max duration a "queued" RPC waits for the operation in progress
+	GRPCServerOperationQueueTimeout = 30 * time.Minute
+	// Sent as header metadata by the RPCs that start an operation
+	GRPCServerOperationIDMetadataKey = "recode-operation-id"
+
+	GRPCServerOperationsJournalDirPath      = "/home/recode/.recode-agent/operations"
+	GRPCServerOperationsJournalMaxAge       = 7 * 24 * time.Hour
+	GRPCServerOperationsJournalMaxTotalSize = 100 * 1024 * 1024 // 100MB
+
+	// Max size of the chunks sent during workspace snapshots
+	// (must be less than the 4MB gRPC max message size)
+	GRPCServerWorkspaceSnapshotChunkSize = 1024 * 1024 // 1MB
+
+	// Min duration between two clone progress replies
+	// (phase changes are always sent)
+	GRPCServerGitCloneProgressInterval = 500 * time.Millisecond
+
 	SSHServerListenPort      = "2200"
 	SSHServerListenAddr      = ":" + SSHServerListenPort
 	SSHServerHostKeyFilePath = "/home/recode/.ssh/recode_ssh_server_host_key"
 
+	// The development environment may be chosen using the
+	// user name (eg: "recode+api") or an environment variable
+	SSHServerDevEnvUserNameSeparator = "+"
+	SSHServerDevEnvNameEnvVar        = "RECODE_DEV_ENV"
+
 	InitInstanceScriptRepoPath = "recode-sh/agent/internal/grpcserver/init_instance.sh"
 )
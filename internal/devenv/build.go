@@ -6,11 +6,13 @@ import (
 	"io"
 	"path/filepath"
 	"runtime"
+	"time"
 
 	"github.com/docker/docker/api/types"
-	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/client"
 	"github.com/docker/docker/pkg/archive"
+	"github.com/docker/go-units"
+	"github.com/moby/buildkit/session/secrets/secretsprovider"
 	"github.com/recode-sh/agent/constants"
 	"github.com/recode-sh/agent/internal/docker"
 	"github.com/recode-sh/agent/proto"
@@ -18,16 +20,116 @@ import (
 )
 
 func Build(
+	ctx context.Context,
 	dockerClient *client.Client,
 	stream proto.Agent_BuildAndStartDevEnvServer,
+	devEnv *DevEnv,
 	userConfigRepoOwner string,
 	userConfigRepoName string,
 	repoOwner string,
 	repoName string,
 	preparedWorkspaceMetadata *PreparedWorkspaceMetadata,
+	retryPolicy RetryPolicy,
+	imageBuildOptions ImageBuildOptions,
 ) error {
 
-	defer removeDanglingDockerImages(dockerClient)
+	buildsStartedAt, err := recordBuildInHistory(
+		imageBuildOptions.BuildHistoryFilePath,
+		time.Now(),
+		imageBuildOptions.CacheKeepBuilds,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	defer pruneDockerBuildCache(
+		dockerClient,
+		imageBuildOptions,
+		buildsStartedAt,
+	)
+
+	userConfigDockerfileIsFinalImage := !preparedWorkspaceMetadata.DevEnvRepoHasDockerfile
+
+	err = runBuildStep(
+		stream,
+		buildStep{
+			ID:   buildStepIDBuildUserConfigImage,
+			Type: proto.StepType_STEP_TYPE_BUILD_USER_CONFIG_IMAGE,
+			Description: fmt.Sprintf(
+				"Building %s/%s/%s",
+				userConfigRepoOwner,
+				userConfigRepoName,
+				entities.DevEnvUserConfigDockerfileFileName,
+			),
+			Repository: userConfigRepoOwner + "/" + userConfigRepoName,
+		},
+		func() error {
+			return buildUserConfigDockerImage(
+				ctx,
+				dockerClient,
+				stream,
+				devEnv,
+				userConfigRepoOwner,
+				userConfigRepoName,
+				preparedWorkspaceMetadata,
+				userConfigDockerfileIsFinalImage,
+				retryPolicy,
+				imageBuildOptions,
+			)
+		},
+	)
+
+	if err != nil {
+		return err
+	}
+
+	if userConfigDockerfileIsFinalImage {
+		return nil
+	}
+
+	return runBuildStep(
+		stream,
+		buildStep{
+			ID:   buildStepIDBuildDevEnvRepoImage,
+			Type: proto.StepType_STEP_TYPE_BUILD_DEV_ENV_REPO_IMAGE,
+			Description: fmt.Sprintf(
+				"Building %s/%s/%s/%s",
+				repoOwner,
+				repoName,
+				entities.DevEnvRepositoryConfigDirectory,
+				entities.DevEnvRepositoryDockerfileFileName,
+			),
+			Repository: repoOwner + "/" + repoName,
+		},
+		func() error {
+			return buildDevEnvRepoDockerImage(
+				ctx,
+				dockerClient,
+				stream,
+				devEnv,
+				repoOwner,
+				repoName,
+				preparedWorkspaceMetadata,
+				retryPolicy,
+				imageBuildOptions,
+			)
+		},
+	)
+}
+
+func buildUserConfigDockerImage(
+	ctx context.Context,
+	dockerClient *client.Client,
+	stream proto.Agent_BuildAndStartDevEnvServer,
+	devEnv *DevEnv,
+	userConfigRepoOwner string,
+	userConfigRepoName string,
+	preparedWorkspaceMetadata *PreparedWorkspaceMetadata,
+	isFinalImage bool,
+	retryPolicy RetryPolicy,
+	imageBuildOptions ImageBuildOptions,
+) error {
 
 	err := stream.Send(&proto.BuildAndStartDevEnvReply{
 		LogLineHeader: fmt.Sprintf(
@@ -42,11 +144,24 @@ func Build(
 		return err
 	}
 
+	userConfigDockerfilePath := filepath.Join(
+		preparedWorkspaceMetadata.TmpUserConfigRepoDirPath,
+		entities.DevEnvUserConfigDockerfileFileName,
+	)
+
 	err = ensureUserConfigDockerfileDerivesFromBaseDevEnv(
-		filepath.Join(
-			preparedWorkspaceMetadata.TmpUserConfigRepoDirPath,
-			entities.DevEnvUserConfigDockerfileFileName,
-		),
+		userConfigDockerfilePath,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	userBuildArgs, err := resolveUserBuildArgs(
+		stream,
+		userConfigDockerfilePath,
+		userConfigDockerfilePath,
+		imageBuildOptions.BuildArgs,
 	)
 
 	if err != nil {
@@ -55,7 +170,7 @@ func Build(
 
 	includeRecodeBuildArgs := true // User base derives from "recodesh/base-dev-env"
 	dockerBuildArgs, err := resolveDockerBuildArgs(
-		map[string]string{},
+		userBuildArgs,
 		includeRecodeBuildArgs,
 	)
 
@@ -63,27 +178,34 @@ func Build(
 		return err
 	}
 
-	dockerBuildContext := preparedWorkspaceMetadata.TmpUserConfigRepoDirPath
-	userConfigDockerfileIsFinalImage := !preparedWorkspaceMetadata.DevEnvRepoHasDockerfile
-
-	err = buildDockerImage(
+	return buildDockerImage(
+		ctx,
 		dockerClient,
 		stream,
-		dockerBuildContext,
+		devEnv,
+		buildStepIDBuildUserConfigImage,
+		preparedWorkspaceMetadata.TmpUserConfigRepoDirPath,
 		dockerBuildArgs,
 		entities.DevEnvUserConfigDockerfileFileName,
-		userConfigDockerfileIsFinalImage,
+		isFinalImage,
+		retryPolicy,
+		imageBuildOptions,
 	)
+}
 
-	if err != nil {
-		return err
-	}
-
-	if userConfigDockerfileIsFinalImage {
-		return nil
-	}
+func buildDevEnvRepoDockerImage(
+	ctx context.Context,
+	dockerClient *client.Client,
+	stream proto.Agent_BuildAndStartDevEnvServer,
+	devEnv *DevEnv,
+	repoOwner string,
+	repoName string,
+	preparedWorkspaceMetadata *PreparedWorkspaceMetadata,
+	retryPolicy RetryPolicy,
+	imageBuildOptions ImageBuildOptions,
+) error {
 
-	err = stream.Send(&proto.BuildAndStartDevEnvReply{
+	err := stream.Send(&proto.BuildAndStartDevEnvReply{
 		LogLineHeader: fmt.Sprintf(
 			"Building %s/%s/%s/%s",
 			repoOwner,
@@ -105,11 +227,23 @@ func Build(
 		return err
 	}
 
-	dockerBuildContext = preparedWorkspaceMetadata.TmpDevEnvRepoConfigDirPath
+	userBuildArgs, err := resolveUserBuildArgs(
+		stream,
+		preparedWorkspaceMetadata.TmpDevEnvRepoDockerfilePath,
+		filepath.Join(
+			preparedWorkspaceMetadata.TmpUserConfigRepoDirPath,
+			entities.DevEnvUserConfigDockerfileFileName,
+		),
+		imageBuildOptions.BuildArgs,
+	)
+
+	if err != nil {
+		return err
+	}
 
-	includeRecodeBuildArgs = false
-	dockerBuildArgs, err = resolveDockerBuildArgs(
-		map[string]string{},
+	includeRecodeBuildArgs := false
+	dockerBuildArgs, err := resolveDockerBuildArgs(
+		userBuildArgs,
 		includeRecodeBuildArgs,
 	)
 
@@ -120,46 +254,135 @@ func Build(
 	isFinalImage := true
 
 	return buildDockerImage(
+		ctx,
 		dockerClient,
 		stream,
-		dockerBuildContext,
+		devEnv,
+		buildStepIDBuildDevEnvRepoImage,
+		preparedWorkspaceMetadata.TmpDevEnvRepoConfigDirPath,
 		dockerBuildArgs,
 		entities.DevEnvRepositoryDockerfileFileName,
 		isFinalImage,
+		retryPolicy,
+		imageBuildOptions,
 	)
 }
 
 func buildDockerImage(
+	ctx context.Context,
 	dockerClient *client.Client,
 	stream proto.Agent_BuildAndStartDevEnvServer,
+	devEnv *DevEnv,
+	buildStepID string,
 	dockerBuildContext string,
 	dockerBuildArgs map[string]*string,
 	dockerfilePath string,
 	isFinalImage bool,
+	retryPolicy RetryPolicy,
+	imageBuildOptions ImageBuildOptions,
 ) error {
 
+	imageTag := entities.DevEnvUserConfigDockerfileImageName
+	if isFinalImage {
+		imageTag = devEnv.DockerImageName
+	}
+
+	buildOptions := types.ImageBuildOptions{
+		Dockerfile: dockerfilePath,
+		Tags:       []string{imageTag},
+		Remove:     true,
+		BuildArgs:  dockerBuildArgs,
+	}
+
+	if imageBuildOptions.UseBuildKit {
+		err := addBuildKitImageBuildOptions(
+			dockerClient,
+			&buildOptions,
+			imageTag,
+		)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	buildContextExcludePatterns, err := reportDockerBuildContext(
+		stream,
+		buildStepID,
+		dockerBuildContext,
+		dockerfilePath,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	// Transient errors (registry 5xx, network...)
+	// are retried from scratch
+	return retryWithBackoff(
+		ctx,
+		retryPolicy,
+		func() error {
+			return runDockerImageBuild(
+				ctx,
+				dockerClient,
+				stream,
+				buildStepID,
+				dockerBuildContext,
+				buildContextExcludePatterns,
+				buildOptions,
+				imageBuildOptions.Secrets,
+			)
+		},
+		newRetryLogLineSender(stream, retryPolicy),
+	)
+}
+
+func runDockerImageBuild(
+	ctx context.Context,
+	dockerClient *client.Client,
+	stream proto.Agent_BuildAndStartDevEnvServer,
+	buildStepID string,
+	dockerBuildContext string,
+	buildContextExcludePatterns []string,
+	buildOptions types.ImageBuildOptions,
+	buildSecrets BuildSecrets,
+) error {
+
+	// The build context is consumed by each build
 	buildContextAsTAR, err := getDockerBuildContextAsTAR(
 		dockerBuildContext,
+		buildContextExcludePatterns,
 	)
 
 	if err != nil {
 		return err
 	}
 
-	imageTag := entities.DevEnvUserConfigDockerfileImageName
-	if isFinalImage {
-		imageTag = constants.DevEnvDockerImageName
+	// BuildKit builds require a session (one session per build)
+	// that also exposes the secrets (eg: "RUN --mount=type=secret,id=npm_token")
+	if buildOptions.Version == types.BuilderBuildKit {
+		buildSession, err := docker.NewBuildKitSession(
+			ctx,
+			dockerClient,
+			secretsprovider.FromMap(buildSecrets),
+		)
+
+		if err != nil {
+			return err
+		}
+
+		defer buildSession.Close()
+
+		buildOptions.SessionID = buildSession.ID()
 	}
 
+	// The build is canceled by the Docker daemon
+	// if the context is canceled
 	buildImageResp, err := dockerClient.ImageBuild(
-		context.TODO(),
+		ctx,
 		buildContextAsTAR,
-		types.ImageBuildOptions{
-			Dockerfile: dockerfilePath,
-			Tags:       []string{imageTag},
-			Remove:     true,
-			BuildArgs:  dockerBuildArgs,
-		},
+		buildOptions,
 	)
 
 	if err != nil {
@@ -175,14 +398,107 @@ func buildDockerImage(
 				LogLine: logLine,
 			})
 		},
+		func(step docker.BuildStep) error {
+			return stream.Send(&proto.BuildAndStartDevEnvReply{
+				DockerBuildProgress: &proto.DockerBuildProgress{
+					StepId:      buildStepID,
+					CurrentStep: int32(step.Current),
+					TotalSteps:  int32(step.Total),
+					Instruction: step.Instruction,
+				},
+			})
+		},
 	)
 }
 
-func getDockerBuildContextAsTAR(buildContext string) (io.Reader, error) {
+func getDockerBuildContextAsTAR(
+	buildContext string,
+	excludePatterns []string,
+) (io.Reader, error) {
+
 	return archive.TarWithOptions(
 		buildContext,
-		&archive.TarOptions{},
+		&archive.TarOptions{
+			ExcludePatterns: excludePatterns,
+		},
+	)
+}
+
+// reportDockerBuildContext sends the size of the build context
+// (without the files excluded by the ".dockerignore" file)
+// and returns the exclusion patterns to use in the build
+func reportDockerBuildContext(
+	stream proto.Agent_BuildAndStartDevEnvServer,
+	buildStepID string,
+	dockerBuildContext string,
+	dockerfilePath string,
+) ([]string, error) {
+
+	excludePatterns, err := docker.ReadDockerignorePatterns(
+		dockerBuildContext,
+		dockerfilePath,
 	)
+
+	if err != nil {
+		return nil, newInvalidDockerfileError(fmt.Errorf(
+			"invalid \".dockerignore\" file: %v",
+			err,
+		))
+	}
+
+	buildContextSize, err := docker.ComputeBuildContextSize(
+		dockerBuildContext,
+		excludePatterns,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Empty ".dockerignore" files are ignored
+	hasDockerignore := len(excludePatterns) > 0
+
+	err = stream.Send(&proto.BuildAndStartDevEnvReply{
+		DockerBuildContext: &proto.DockerBuildContext{
+			StepId:          buildStepID,
+			SizeBytes:       buildContextSize,
+			HasDockerignore: hasDockerignore,
+		},
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = stream.Send(&proto.BuildAndStartDevEnvReply{
+		LogLine: fmt.Sprintf(
+			"Sending build context to Docker daemon  %s\n",
+			units.HumanSize(float64(buildContextSize)),
+		),
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if buildContextSize <= constants.DevEnvBuildContextSizeWarningThreshold {
+		return excludePatterns, nil
+	}
+
+	err = stream.Send(&proto.BuildAndStartDevEnvReply{
+		LogLine: fmt.Sprintf(
+			"Warning: the build context is larger than %s, "+
+				"consider excluding unused files (eg: \".git\", \"node_modules\") "+
+				"in a \".dockerignore\" file\n",
+			units.HumanSize(constants.DevEnvBuildContextSizeWarningThreshold),
+		),
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return excludePatterns, nil
 }
 
 func resolveDockerBuildArgs(
@@ -221,14 +537,29 @@ func resolveDockerBuildArgs(
 	return buildArgs, nil
 }
 
-func removeDanglingDockerImages(
+// addBuildKitImageBuildOptions enables BuildKit and its inline
+// cache. The previous image (if any) is used as cache source.
+func addBuildKitImageBuildOptions(
 	dockerClient *client.Client,
+	buildOptions *types.ImageBuildOptions,
+	imageTag string,
 ) error {
 
-	_, err := dockerClient.ImagesPrune(
-		context.TODO(),
-		filters.Args{},
-	)
+	buildOptions.Version = types.BuilderBuildKit
+
+	// Embeds the cache metadata in the built image
+	inlineCache := "1"
+	buildOptions.BuildArgs["BUILDKIT_INLINE_CACHE"] = &inlineCache
+
+	previousImage, err := docker.LookupImage(dockerClient, imageTag)
+
+	if err != nil {
+		return err
+	}
+
+	if previousImage != nil {
+		buildOptions.CacheFrom = []string{imageTag}
+	}
 
-	return err
+	return nil
 }
@@ -0,0 +1,198 @@
+package devenv
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/recode-sh/agent/constants"
+	"github.com/recode-sh/agent/internal/docker"
+	"github.com/recode-sh/agent/proto"
+)
+
+var buildArgNameRegExp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Build args that Docker accepts without "ARG" instruction
+var predefinedDockerBuildArgNames = map[string]bool{
+	"HTTP_PROXY":  true,
+	"http_proxy":  true,
+	"HTTPS_PROXY": true,
+	"https_proxy": true,
+	"FTP_PROXY":   true,
+	"ftp_proxy":   true,
+	"NO_PROXY":    true,
+	"no_proxy":    true,
+	"ALL_PROXY":   true,
+	"all_proxy":   true,
+}
+
+// ValidateBuildArgs ensures that the names of the passed
+// build args are valid and not reserved by the agent
+func ValidateBuildArgs(buildArgs map[string]string) error {
+	for buildArgName := range buildArgs {
+		if !buildArgNameRegExp.MatchString(buildArgName) {
+			return fmt.Errorf(
+				"invalid build arg name \"%s\" (expected: %s)",
+				buildArgName,
+				buildArgNameRegExp.String(),
+			)
+		}
+
+		if strings.HasPrefix(buildArgName, constants.DevEnvReservedBuildArgsPrefix) {
+			return fmt.Errorf(
+				"build arg \"%s\" is reserved (the \"%s\" prefix is used by the agent)",
+				buildArgName,
+				constants.DevEnvReservedBuildArgsPrefix,
+			)
+		}
+	}
+
+	return nil
+}
+
+// resolveUserBuildArgs merges the build args (from lowest to highest
+// precedence) set in the label of the user config Dockerfile, in the
+// "build_args.env" file next to the built Dockerfile and in the request.
+// The args not declared in the built Dockerfile are ignored (with a warning).
+func resolveUserBuildArgs(
+	stream proto.Agent_BuildAndStartDevEnvServer,
+	dockerfilePath string,
+	userConfigDockerfilePath string,
+	requestBuildArgs map[string]string,
+) (map[string]string, error) {
+
+	labelBuildArgs, err := lookupBuildArgsInDockerfileLabels(
+		userConfigDockerfilePath,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	fileBuildArgs, err := loadBuildArgsFile(
+		filepath.Join(
+			filepath.Dir(dockerfilePath),
+			constants.DevEnvBuildArgsFileName,
+		),
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	buildArgs := map[string]string{}
+
+	for _, buildArgsSource := range []map[string]string{
+		labelBuildArgs,
+		fileBuildArgs,
+		requestBuildArgs,
+	} {
+
+		for buildArgName, buildArgValue := range buildArgsSource {
+			buildArgs[buildArgName] = buildArgValue
+		}
+	}
+
+	err = ValidateBuildArgs(buildArgs)
+
+	if err != nil {
+		return nil, newInvalidDockerfileError(err)
+	}
+
+	declaredBuildArgNames, err := docker.LookupDockerfileArgNames(dockerfilePath)
+
+	if err != nil {
+		return nil, newInvalidDockerfileError(err)
+	}
+
+	declaredBuildArgs := map[string]bool{}
+
+	for _, declaredBuildArgName := range declaredBuildArgNames {
+		declaredBuildArgs[declaredBuildArgName] = true
+	}
+
+	unusedBuildArgNames := []string{}
+
+	for buildArgName := range buildArgs {
+		if declaredBuildArgs[buildArgName] ||
+			predefinedDockerBuildArgNames[buildArgName] {
+
+			continue
+		}
+
+		unusedBuildArgNames = append(unusedBuildArgNames, buildArgName)
+		delete(buildArgs, buildArgName)
+	}
+
+	if len(unusedBuildArgNames) > 0 {
+		sort.Strings(unusedBuildArgNames)
+
+		err = stream.Send(&proto.BuildAndStartDevEnvReply{
+			LogLine: fmt.Sprintf(
+				"Warning: build args %s are not declared in \"%s\" and were ignored\n",
+				strings.Join(unusedBuildArgNames, ", "),
+				filepath.Base(dockerfilePath),
+			),
+		})
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return buildArgs, nil
+}
+
+// loadBuildArgsFile parses the "KEY=value" lines of the passed
+// file (blank lines and lines starting with "#" are ignored)
+func loadBuildArgsFile(buildArgsFilePath string) (map[string]string, error) {
+	buildArgs := map[string]string{}
+
+	buildArgsFile, err := os.Open(buildArgsFilePath)
+
+	if err != nil && os.IsNotExist(err) {
+		return buildArgs, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer buildArgsFile.Close()
+
+	scanner := bufio.NewScanner(buildArgsFile)
+	lineNumber := 0
+
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		buildArgNameAndValue := strings.SplitN(line, "=", 2)
+
+		if len(buildArgNameAndValue) != 2 {
+			return nil, newInvalidDockerfileError(fmt.Errorf(
+				"invalid line %d in \"%s\" (expected \"KEY=value\")",
+				lineNumber,
+				constants.DevEnvBuildArgsFileName,
+			))
+		}
+
+		buildArgs[strings.TrimSpace(buildArgNameAndValue[0])] = strings.TrimSpace(
+			buildArgNameAndValue[1],
+		)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return buildArgs, nil
+}
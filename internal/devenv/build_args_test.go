@@ -0,0 +1,96 @@
+package devenv
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResolveUserBuildArgs(t *testing.T) {
+	userConfigDirPath := t.TempDir()
+	repoConfigDirPath := t.TempDir()
+
+	userConfigDockerfilePath := filepath.Join(userConfigDirPath, "Dockerfile")
+	repoDockerfilePath := filepath.Join(repoConfigDirPath, "dev_env.Dockerfile")
+
+	testFiles := map[string]string{
+		userConfigDockerfilePath: "FROM recodesh/base-dev-env:latest\n" +
+			"LABEL sh.recode.build_args=\"NODE_VERSION=16, GO_VERSION=1.17, UNUSED_ARG=1\"\n",
+
+		repoDockerfilePath: "FROM recode-user-config-image\n" +
+			"ARG NODE_VERSION\n" +
+			"ARG GO_VERSION=1.16\n" +
+			"ARG PYTHON_VERSION\n",
+
+		filepath.Join(repoConfigDirPath, "build_args.env"): "# Versions\n" +
+			"NODE_VERSION=18\n" +
+			"\n" +
+			"PYTHON_VERSION=3.10\n",
+	}
+
+	for filePath, fileContent := range testFiles {
+		err := os.WriteFile(filePath, []byte(fileContent), os.FileMode(0644))
+
+		if err != nil {
+			t.Fatalf("expected no error, got '%+v'", err)
+		}
+	}
+
+	stream := &testBuildAndStartDevEnvStream{}
+
+	buildArgs, err := resolveUserBuildArgs(
+		stream,
+		repoDockerfilePath,
+		userConfigDockerfilePath,
+		map[string]string{
+			"PYTHON_VERSION": "3.11",
+			"HTTP_PROXY":     "http://proxy:3128",
+		},
+	)
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	expectedBuildArgs := map[string]string{
+		"NODE_VERSION":   "18",   // File overrides label
+		"GO_VERSION":     "1.17", // Label
+		"PYTHON_VERSION": "3.11", // Request overrides file
+		"HTTP_PROXY":     "http://proxy:3128",
+	}
+
+	if len(buildArgs) != len(expectedBuildArgs) {
+		t.Fatalf("expected build args to equal '%v', got '%v'", expectedBuildArgs, buildArgs)
+	}
+
+	for buildArgName, expectedBuildArgValue := range expectedBuildArgs {
+		if buildArgs[buildArgName] != expectedBuildArgValue {
+			t.Fatalf(
+				"expected build arg '%s' to equal '%s', got '%s'",
+				buildArgName,
+				expectedBuildArgValue,
+				buildArgs[buildArgName],
+			)
+		}
+	}
+
+	if len(stream.replies) != 1 ||
+		!strings.Contains(stream.replies[0].LogLine, "UNUSED_ARG") {
+
+		t.Fatalf("expected unused build arg warning, got '%+v'", stream.replies)
+	}
+
+	_, err = resolveUserBuildArgs(
+		stream,
+		repoDockerfilePath,
+		userConfigDockerfilePath,
+		map[string]string{
+			"RECODE_USER_ID": "0",
+		},
+	)
+
+	if err == nil {
+		t.Fatalf("expected error, got nothing")
+	}
+}
@@ -0,0 +1,197 @@
+package devenv
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+	"github.com/recode-sh/agent/constants"
+)
+
+// ImageBuildOptions controls how the images of
+// the development environments are built and
+// how the build cache is pruned after each build
+type ImageBuildOptions struct {
+	// Build through BuildKit (enables "RUN --mount=type=cache"
+	// and reuses the inline cache of the previous image)
+	UseBuildKit bool
+	// The dangling images and build cache not used during
+	// the last N builds of the instance are removed
+	CacheKeepBuilds int
+	// The images of the last N successful builds of
+	// the development environment are kept for rollbacks
+	KeepImages int
+	// Start times of the last builds of the instance
+	BuildHistoryFilePath string
+	// Only exposed to BuildKit builds
+	Secrets BuildSecrets
+	// Override the build args set in the
+	// labels and files (see "resolveUserBuildArgs")
+	BuildArgs map[string]string
+}
+
+func DefaultImageBuildOptions() ImageBuildOptions {
+	return ImageBuildOptions{
+		UseBuildKit:          false,
+		CacheKeepBuilds:      constants.DevEnvBuildCacheDefaultKeepBuilds,
+		KeepImages:           constants.DevEnvDockerImagesDefaultKeepImages,
+		BuildHistoryFilePath: constants.DevEnvBuildHistoryFilePath,
+	}
+}
+
+// NewImageBuildOptions returns the default options
+// overridden by the passed values (zero values are ignored)
+func NewImageBuildOptions(
+	useBuildKit bool,
+	cacheKeepBuilds int,
+	keepImages int,
+) (ImageBuildOptions, error) {
+
+	if cacheKeepBuilds < 0 {
+		return ImageBuildOptions{}, fmt.Errorf(
+			"invalid build cache keep builds \"%d\"",
+			cacheKeepBuilds,
+		)
+	}
+
+	if keepImages < 0 {
+		return ImageBuildOptions{}, fmt.Errorf(
+			"invalid keep images \"%d\"",
+			keepImages,
+		)
+	}
+
+	imageBuildOptions := DefaultImageBuildOptions()
+	imageBuildOptions.UseBuildKit = useBuildKit
+
+	if cacheKeepBuilds > 0 {
+		imageBuildOptions.CacheKeepBuilds = cacheKeepBuilds
+	}
+
+	if keepImages > 0 {
+		imageBuildOptions.KeepImages = keepImages
+	}
+
+	return imageBuildOptions, nil
+}
+
+type buildHistory struct {
+	BuildsStartedAt []time.Time `json:"builds_started_at"`
+}
+
+// recordBuildInHistory adds the passed build to the history (only the
+// last "keepBuilds" builds are kept) and returns the updated history.
+// The history is shared by all the development environments but
+// is not locked: builds are run one at a time (the operations
+// manager runs only one operation at a time on the instance).
+func recordBuildInHistory(
+	buildHistoryFilePath string,
+	buildStartedAt time.Time,
+	keepBuilds int,
+) ([]time.Time, error) {
+
+	history := buildHistory{}
+	historyAsJSON, err := os.ReadFile(buildHistoryFilePath)
+
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	// A corrupted history is reset
+	if err == nil && json.Unmarshal(historyAsJSON, &history) != nil {
+		history = buildHistory{}
+	}
+
+	history.BuildsStartedAt = append(history.BuildsStartedAt, buildStartedAt)
+
+	sort.Slice(history.BuildsStartedAt, func(i, j int) bool {
+		return history.BuildsStartedAt[i].Before(history.BuildsStartedAt[j])
+	})
+
+	if len(history.BuildsStartedAt) > keepBuilds {
+		history.BuildsStartedAt = history.BuildsStartedAt[len(history.BuildsStartedAt)-keepBuilds:]
+	}
+
+	historyAsJSON, err = json.Marshal(history)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = os.WriteFile(
+		buildHistoryFilePath,
+		historyAsJSON,
+		os.FileMode(0644),
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return history.BuildsStartedAt, nil
+}
+
+// buildCachePruneUntil returns the duration passed to the "until"
+// prune filter (ie: the time elapsed since the start of the oldest
+// build to keep). Nothing is pruned until "keepBuilds" builds
+// were recorded given that older builds are unknown.
+func buildCachePruneUntil(
+	buildsStartedAt []time.Time,
+	keepBuilds int,
+	now time.Time,
+) (time.Duration, bool) {
+
+	if len(buildsStartedAt) < keepBuilds || len(buildsStartedAt) == 0 {
+		return 0, false
+	}
+
+	return now.Sub(buildsStartedAt[0]).Round(time.Second) + time.Second, true
+}
+
+// pruneDockerBuildCache removes the dangling images and the
+// build cache that were not used during the last builds
+// (replaces the previous prune of all dangling images)
+func pruneDockerBuildCache(
+	dockerClient *client.Client,
+	imageBuildOptions ImageBuildOptions,
+	buildsStartedAt []time.Time,
+) error {
+
+	pruneUntil, shouldPrune := buildCachePruneUntil(
+		buildsStartedAt,
+		imageBuildOptions.CacheKeepBuilds,
+		time.Now(),
+	)
+
+	if !shouldPrune {
+		return nil
+	}
+
+	pruneFilters := filters.NewArgs(
+		filters.Arg("until", pruneUntil.String()),
+	)
+
+	_, err := dockerClient.ImagesPrune(
+		context.TODO(),
+		pruneFilters,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = dockerClient.BuildCachePrune(
+		context.TODO(),
+		types.BuildCachePruneOptions{
+			Filters: pruneFilters,
+		},
+	)
+
+	return err
+}
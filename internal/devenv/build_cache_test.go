@@ -0,0 +1,105 @@
+package devenv
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRecordBuildInHistory(t *testing.T) {
+	buildHistoryFilePath := filepath.Join(t.TempDir(), "build-history.json")
+	firstBuildStartedAt := time.Now().Add(-time.Hour)
+
+	for buildIndex := 0; buildIndex < 4; buildIndex++ {
+		_, err := recordBuildInHistory(
+			buildHistoryFilePath,
+			firstBuildStartedAt.Add(time.Duration(buildIndex)*time.Minute),
+			3,
+		)
+
+		if err != nil {
+			t.Fatalf("expected no error, got '%+v'", err)
+		}
+	}
+
+	buildsStartedAt, err := recordBuildInHistory(
+		buildHistoryFilePath,
+		firstBuildStartedAt.Add(4*time.Minute),
+		3,
+	)
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	if len(buildsStartedAt) != 3 {
+		t.Fatalf("expected '3' builds, got '%d'", len(buildsStartedAt))
+	}
+
+	expectedOldestBuild := firstBuildStartedAt.Add(2 * time.Minute)
+
+	if !buildsStartedAt[0].Equal(expectedOldestBuild) {
+		t.Fatalf(
+			"expected oldest build to equal '%s', got '%s'",
+			expectedOldestBuild,
+			buildsStartedAt[0],
+		)
+	}
+}
+
+func TestBuildCachePruneUntil(t *testing.T) {
+	now := time.Now()
+
+	testCases := []struct {
+		test                string
+		buildsStartedAt     []time.Time
+		keepBuilds          int
+		expectedShouldPrune bool
+		expectedPruneUntil  time.Duration
+	}{
+		{
+			test:                "not_enough_builds",
+			buildsStartedAt:     []time.Time{now.Add(-time.Minute)},
+			keepBuilds:          2,
+			expectedShouldPrune: false,
+		},
+
+		{
+			test: "enough_builds",
+			buildsStartedAt: []time.Time{
+				now.Add(-10 * time.Minute),
+				now.Add(-time.Minute),
+			},
+			keepBuilds:          2,
+			expectedShouldPrune: true,
+			// One second of margin
+			expectedPruneUntil: 10*time.Minute + time.Second,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.test, func(t *testing.T) {
+			pruneUntil, shouldPrune := buildCachePruneUntil(
+				tc.buildsStartedAt,
+				tc.keepBuilds,
+				now,
+			)
+
+			if shouldPrune != tc.expectedShouldPrune {
+				t.Fatalf(
+					"expected should prune to equal '%t', got '%t'",
+					tc.expectedShouldPrune,
+					shouldPrune,
+				)
+			}
+
+			if pruneUntil != tc.expectedPruneUntil {
+				t.Fatalf(
+					"expected prune until to equal '%s', got '%s'",
+					tc.expectedPruneUntil,
+					pruneUntil,
+				)
+			}
+		})
+	}
+}
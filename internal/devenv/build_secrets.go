@@ -0,0 +1,302 @@
+package devenv
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/recode-sh/agent/constants"
+	"github.com/recode-sh/agent/proto"
+	"google.golang.org/grpc/status"
+	protobuf "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// BuildSecrets are exposed to BuildKit builds as secret mounts
+// (indexed by ID). They are never passed as build args
+// to prevent them from being written in the image layers.
+type BuildSecrets map[string][]byte
+
+var buildSecretIDRegExp = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,128}$`)
+
+// LoadBuildSecrets returns the secrets stored on the instance
+// (one file per secret named after the secret ID)
+// overridden by the passed ones.
+func LoadBuildSecrets(
+	storedSecretsDirPath string,
+	secretsOverrides map[string]string,
+) (BuildSecrets, error) {
+
+	buildSecrets := BuildSecrets{}
+
+	storedSecretsFiles, err := os.ReadDir(storedSecretsDirPath)
+
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	for _, storedSecretFile := range storedSecretsFiles {
+		if storedSecretFile.IsDir() ||
+			!buildSecretIDRegExp.MatchString(storedSecretFile.Name()) {
+
+			continue
+		}
+
+		storedSecretValue, err := os.ReadFile(
+			filepath.Join(storedSecretsDirPath, storedSecretFile.Name()),
+		)
+
+		if err != nil {
+			return nil, err
+		}
+
+		buildSecrets[storedSecretFile.Name()] = storedSecretValue
+	}
+
+	for secretID, secretValue := range secretsOverrides {
+		if !buildSecretIDRegExp.MatchString(secretID) {
+			return nil, fmt.Errorf(
+				"invalid build secret ID \"%s\" (expected: %s)",
+				secretID,
+				buildSecretIDRegExp.String(),
+			)
+		}
+
+		buildSecrets[secretID] = []byte(secretValue)
+	}
+
+	return buildSecrets, nil
+}
+
+// values returns the values of the secrets
+// (longest first in case of secrets that contain others)
+func (b BuildSecrets) values() []string {
+	secretsValues := []string{}
+
+	for _, secretValue := range b {
+		// Secrets files usually end with a newline
+		trimmedSecretValue := strings.TrimSpace(string(secretValue))
+
+		if len(trimmedSecretValue) > 0 {
+			secretsValues = append(secretsValues, trimmedSecretValue)
+		}
+	}
+
+	sort.Slice(secretsValues, func(i, j int) bool {
+		return len(secretsValues[i]) > len(secretsValues[j])
+	})
+
+	return secretsValues
+}
+
+// Redact replaces the secrets found in the passed string
+func (b BuildSecrets) Redact(str string) string {
+	for _, secretValue := range b.values() {
+		str = strings.ReplaceAll(
+			str,
+			secretValue,
+			constants.DevEnvBuildSecretsRedactedValue,
+		)
+	}
+
+	return str
+}
+
+// partialSecretLen returns the length of the longest end of the
+// passed string that is also the beginning of a secret (0 if none)
+func (b BuildSecrets) partialSecretLen(str string) int {
+	partialSecretLen := 0
+
+	for _, secretValue := range b.values() {
+		maxPartialSecretLen := len(secretValue) - 1
+
+		if maxPartialSecretLen > len(str) {
+			maxPartialSecretLen = len(str)
+		}
+
+		for l := maxPartialSecretLen; l > partialSecretLen; l-- {
+			if strings.HasSuffix(str, secretValue[:l]) {
+				partialSecretLen = l
+				break
+			}
+		}
+	}
+
+	return partialSecretLen
+}
+
+// RedactError returns an error whose message doesn't contain
+// the secrets. The build step error codes and the gRPC status
+// codes are kept.
+func (b BuildSecrets) RedactError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	redactedErrMessage := b.Redact(err.Error())
+
+	if redactedErrMessage == err.Error() {
+		return err
+	}
+
+	var buildStepErr *BuildStepError
+
+	if errors.As(err, &buildStepErr) {
+		return newBuildStepError(
+			buildStepErr.Code,
+			errors.New(redactedErrMessage),
+		)
+	}
+
+	if errStatus, ok := status.FromError(err); ok {
+		return status.Error(errStatus.Code(), b.Redact(errStatus.Message()))
+	}
+
+	return errors.New(redactedErrMessage)
+}
+
+// SecretsRedactingBuildAndStartDevEnvStream redacts the build
+// secrets found in the replies before they are sent (and journaled).
+// Log lines are sent in chunks so the end of a log line that may be
+// the beginning of a secret is kept until the next log line (or
+// until the next reply of another kind or "Flush").
+type SecretsRedactingBuildAndStartDevEnvStream struct {
+	proto.Agent_BuildAndStartDevEnvServer
+	secrets BuildSecrets
+
+	mutex          sync.Mutex
+	pendingLogLine string
+}
+
+func NewSecretsRedactingBuildAndStartDevEnvStream(
+	stream proto.Agent_BuildAndStartDevEnvServer,
+	secrets BuildSecrets,
+) *SecretsRedactingBuildAndStartDevEnvStream {
+
+	return &SecretsRedactingBuildAndStartDevEnvStream{
+		Agent_BuildAndStartDevEnvServer: stream,
+		secrets:                         secrets,
+	}
+}
+
+func (s *SecretsRedactingBuildAndStartDevEnvStream) Send(
+	reply *proto.BuildAndStartDevEnvReply,
+) error {
+
+	if len(s.secrets) == 0 {
+		return s.Agent_BuildAndStartDevEnvServer.Send(reply)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	isLogLineOnly := len(reply.LogLine) > 0 &&
+		protobuf.Size(reply) == protobuf.Size(&proto.BuildAndStartDevEnvReply{
+			LogLine: reply.LogLine,
+		})
+
+	if !isLogLineOnly {
+		err := s.flushPendingLogLine()
+
+		if err != nil {
+			return err
+		}
+
+		// Replies may be kept by callers
+		// so they are not modified
+		redactedReply := protobuf.Clone(reply).(*proto.BuildAndStartDevEnvReply)
+		redactStringFields(redactedReply.ProtoReflect(), s.secrets.Redact)
+
+		return s.Agent_BuildAndStartDevEnvServer.Send(redactedReply)
+	}
+
+	logLine := s.secrets.Redact(s.pendingLogLine + reply.LogLine)
+	sentLogLineLen := len(logLine) - s.secrets.partialSecretLen(logLine)
+
+	s.pendingLogLine = logLine[sentLogLineLen:]
+
+	if sentLogLineLen == 0 {
+		return nil
+	}
+
+	return s.Agent_BuildAndStartDevEnvServer.Send(&proto.BuildAndStartDevEnvReply{
+		LogLine: logLine[:sentLogLineLen],
+	})
+}
+
+// Flush sends the end of the last log line
+// kept in case it was the beginning of a secret
+func (s *SecretsRedactingBuildAndStartDevEnvStream) Flush() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.flushPendingLogLine()
+}
+
+func (s *SecretsRedactingBuildAndStartDevEnvStream) flushPendingLogLine() error {
+	if len(s.pendingLogLine) == 0 {
+		return nil
+	}
+
+	pendingLogLine := s.pendingLogLine
+	s.pendingLogLine = ""
+
+	return s.Agent_BuildAndStartDevEnvServer.Send(&proto.BuildAndStartDevEnvReply{
+		LogLine: pendingLogLine,
+	})
+}
+
+// redactStringFields redacts all the string fields of the
+// passed message (including the ones of nested messages)
+func redactStringFields(
+	message protoreflect.Message,
+	redact func(string) string,
+) {
+
+	populatedFields := []protoreflect.FieldDescriptor{}
+
+	// The message must not be modified during "Range"
+	message.Range(func(field protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+		populatedFields = append(populatedFields, field)
+		return true
+	})
+
+	for _, field := range populatedFields {
+		if field.IsMap() {
+			continue
+		}
+
+		fieldValue := message.Get(field)
+
+		if field.IsList() {
+			fieldList := fieldValue.List()
+
+			for itemIndex := 0; itemIndex < fieldList.Len(); itemIndex++ {
+				switch field.Kind() {
+				case protoreflect.StringKind:
+					fieldList.Set(itemIndex, protoreflect.ValueOfString(
+						redact(fieldList.Get(itemIndex).String()),
+					))
+				case protoreflect.MessageKind:
+					redactStringFields(fieldList.Get(itemIndex).Message(), redact)
+				}
+			}
+
+			continue
+		}
+
+		switch field.Kind() {
+		case protoreflect.StringKind:
+			message.Set(field, protoreflect.ValueOfString(
+				redact(fieldValue.String()),
+			))
+		case protoreflect.MessageKind:
+			redactStringFields(fieldValue.Message(), redact)
+		}
+	}
+}
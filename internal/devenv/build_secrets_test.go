@@ -0,0 +1,214 @@
+package devenv
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/recode-sh/agent/proto"
+)
+
+func TestLoadBuildSecrets(t *testing.T) {
+	storedSecretsDirPath := t.TempDir()
+
+	for secretID, secretValue := range map[string]string{
+		"npm_token":  "stored-npm-token\n",
+		"pypi_token": "stored-pypi-token\n",
+	} {
+
+		err := os.WriteFile(
+			filepath.Join(storedSecretsDirPath, secretID),
+			[]byte(secretValue),
+			os.FileMode(0600),
+		)
+
+		if err != nil {
+			t.Fatalf("expected no error, got '%+v'", err)
+		}
+	}
+
+	buildSecrets, err := LoadBuildSecrets(
+		storedSecretsDirPath,
+		map[string]string{
+			"npm_token": "request-npm-token",
+		},
+	)
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	expectedSecrets := map[string]string{
+		"npm_token":  "request-npm-token",
+		"pypi_token": "stored-pypi-token\n",
+	}
+
+	if len(buildSecrets) != len(expectedSecrets) {
+		t.Fatalf("expected '%d' secrets, got '%d'", len(expectedSecrets), len(buildSecrets))
+	}
+
+	for secretID, expectedSecretValue := range expectedSecrets {
+		if string(buildSecrets[secretID]) != expectedSecretValue {
+			t.Fatalf(
+				"expected secret '%s' to equal '%s', got '%s'",
+				secretID,
+				expectedSecretValue,
+				buildSecrets[secretID],
+			)
+		}
+	}
+
+	_, err = LoadBuildSecrets(
+		filepath.Join(storedSecretsDirPath, "not_found"),
+		map[string]string{
+			"invalid/id": "value",
+		},
+	)
+
+	if err == nil {
+		t.Fatalf("expected error, got nothing")
+	}
+}
+
+func TestBuildSecretsRedaction(t *testing.T) {
+	buildSecrets := BuildSecrets{
+		"npm_token":  []byte("npm-secret\n"),
+		"pypi_token": []byte("pypi-secret"),
+	}
+
+	redactedStr := buildSecrets.Redact("//registry.npmjs.org/:_authToken=npm-secret pypi-secret")
+	expectedRedactedStr := "//registry.npmjs.org/:_authToken=[REDACTED] [REDACTED]"
+
+	if redactedStr != expectedRedactedStr {
+		t.Fatalf("expected '%s', got '%s'", expectedRedactedStr, redactedStr)
+	}
+
+	redactedErr := buildSecrets.RedactError(newBuildStepError(
+		proto.ErrorCode_ERROR_CODE_IMAGE_BUILD_FAILED,
+		errors.New("invalid token npm-secret"),
+	))
+
+	if redactedErr.Error() != "invalid token [REDACTED]" {
+		t.Fatalf("expected redacted error, got '%s'", redactedErr.Error())
+	}
+
+	if BuildStepErrorCode(redactedErr) != proto.ErrorCode_ERROR_CODE_IMAGE_BUILD_FAILED {
+		t.Fatalf(
+			"expected error code '%s', got '%s'",
+			proto.ErrorCode_ERROR_CODE_IMAGE_BUILD_FAILED,
+			BuildStepErrorCode(redactedErr),
+		)
+	}
+}
+
+func TestSecretsRedactingBuildAndStartDevEnvStream(t *testing.T) {
+	testStream := &testBuildAndStartDevEnvStream{}
+
+	stream := NewSecretsRedactingBuildAndStartDevEnvStream(
+		testStream,
+		BuildSecrets{"npm_token": []byte("npm-secret")},
+	)
+
+	reply := &proto.BuildAndStartDevEnvReply{
+		LogLine: "npm ERR! token npm-secret is invalid\n",
+		Result: &proto.BuildAndStartDevEnvResult{
+			Steps: []*proto.StepEvent{
+				{ErrorMessage: "token npm-secret is invalid"},
+			},
+		},
+	}
+
+	err := stream.Send(reply)
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	sentReply := testStream.replies[0]
+
+	if sentReply.LogLine != "npm ERR! token [REDACTED] is invalid\n" {
+		t.Fatalf("expected redacted log line, got '%s'", sentReply.LogLine)
+	}
+
+	if sentReply.Result.Steps[0].ErrorMessage != "token [REDACTED] is invalid" {
+		t.Fatalf("expected redacted step error, got '%s'", sentReply.Result.Steps[0].ErrorMessage)
+	}
+
+	// The passed reply must not be modified
+	if reply.LogLine != "npm ERR! token npm-secret is invalid\n" {
+		t.Fatalf("expected reply to be left unchanged, got '%s'", reply.LogLine)
+	}
+}
+
+func TestSecretsRedactingBuildAndStartDevEnvStreamWithSplitSecrets(t *testing.T) {
+	testStream := &testBuildAndStartDevEnvStream{}
+
+	stream := NewSecretsRedactingBuildAndStartDevEnvStream(
+		testStream,
+		BuildSecrets{"npm_token": []byte("npm-secret\n")},
+	)
+
+	logLines := []string{
+		"npm ERR! token npm-",
+		"sec",
+		"ret is invalid\n",
+		"npm ERR! retrying with npm",
+	}
+
+	for _, logLine := range logLines {
+		err := stream.Send(&proto.BuildAndStartDevEnvReply{
+			LogLine: logLine,
+		})
+
+		if err != nil {
+			t.Fatalf("expected no error, got '%+v'", err)
+		}
+	}
+
+	// The end of the last log line is
+	// sent before the replies of other kinds
+	err := stream.Send(&proto.BuildAndStartDevEnvReply{
+		StepEvent: &proto.StepEvent{},
+	})
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	err = stream.Send(&proto.BuildAndStartDevEnvReply{
+		LogLine: "npm-se",
+	})
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	err = stream.Flush()
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	sentLogLines := ""
+
+	for _, sentReply := range testStream.replies {
+		if strings.Contains(sentReply.LogLine, "npm-secret") {
+			t.Fatalf("expected redacted log line, got '%s'", sentReply.LogLine)
+		}
+
+		if sentReply.StepEvent != nil {
+			sentLogLines += "<step event>"
+		}
+
+		sentLogLines += sentReply.LogLine
+	}
+
+	expectedSentLogLines := "npm ERR! token [REDACTED] is invalid\n" +
+		"npm ERR! retrying with npm<step event>npm-se"
+
+	if sentLogLines != expectedSentLogLines {
+		t.Fatalf("expected '%s', got '%s'", expectedSentLogLines, sentLogLines)
+	}
+}
@@ -0,0 +1,326 @@
+package devenv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/recode-sh/agent/proto"
+)
+
+const (
+	buildStepIDCloneUserConfigRepo  = "clone_user_config_repo"
+	buildStepIDCloneDevEnvRepo      = "clone_dev_env_repo"
+	buildStepIDBuildUserConfigImage = "build_user_config_image"
+	buildStepIDBuildDevEnvRepoImage = "build_dev_env_repo_image"
+	buildStepIDStartContainer       = "start_container"
+)
+
+func buildStepIDCloneRepo(repoOwner, repoName string) string {
+	return fmt.Sprintf("clone_repo:%s/%s", repoOwner, repoName)
+}
+
+func buildStepIDSyncRepo(repoOwner, repoName string) string {
+	return fmt.Sprintf("sync_repo:%s/%s", repoOwner, repoName)
+}
+
+func buildStepIDInitSubmodule(repoOwner, repoName, submodulePath string) string {
+	return fmt.Sprintf("init_submodule:%s/%s:%s", repoOwner, repoName, submodulePath)
+}
+
+func buildStepIDPullLFSObjects(repoOwner, repoName string) string {
+	return fmt.Sprintf("pull_lfs_objects:%s/%s", repoOwner, repoName)
+}
+
+func buildStepIDRunHook(repoOwner, repoName, hookName string) string {
+	return fmt.Sprintf("run_hook:%s/%s:%s", repoOwner, repoName, hookName)
+}
+
+type buildStep struct {
+	ID          string
+	Type        proto.StepType
+	Description string
+	// Set only for steps related to a repository (eg: "owner/name")
+	Repository string
+}
+
+// BuildStepError wraps the error returned by a
+// build step with a machine-readable error code.
+type BuildStepError struct {
+	Code proto.ErrorCode
+	Err  error
+}
+
+func newBuildStepError(code proto.ErrorCode, err error) *BuildStepError {
+	return &BuildStepError{
+		Code: code,
+		Err:  err,
+	}
+}
+
+func (b *BuildStepError) Error() string {
+	return b.Err.Error()
+}
+
+func (b *BuildStepError) Unwrap() error {
+	return b.Err
+}
+
+func runBuildStep(
+	stream proto.Agent_BuildAndStartDevEnvServer,
+	step buildStep,
+	stepFunc func() error,
+) error {
+
+	stepStartedAt := time.Now()
+
+	err := stream.Send(&proto.BuildAndStartDevEnvReply{
+		StepEvent: buildStepEvent(step, proto.StepStatus_STEP_STATUS_STARTED),
+	})
+
+	if err != nil {
+		return err
+	}
+
+	stepErr := stepFunc()
+
+	if stepErr != nil {
+		errorCode := BuildStepErrorCode(stepErr)
+
+		// Errors without a specific code
+		// take the one associated with the step
+		if errorCode == proto.ErrorCode_ERROR_CODE_INTERNAL {
+			errorCode = buildStepErrorCodes[step.Type]
+			stepErr = newBuildStepError(errorCode, stepErr)
+		}
+
+		stepEvent := buildStepEvent(step, proto.StepStatus_STEP_STATUS_FAILED)
+		stepEvent.DurationMs = time.Since(stepStartedAt).Milliseconds()
+		stepEvent.ErrorCode = errorCode
+		stepEvent.ErrorMessage = stepErr.Error()
+
+		// The step error is returned even if the event could
+		// not be sent given that it is more meaningful
+		_ = stream.Send(&proto.BuildAndStartDevEnvReply{
+			StepEvent: stepEvent,
+		})
+
+		return stepErr
+	}
+
+	stepEvent := buildStepEvent(step, proto.StepStatus_STEP_STATUS_FINISHED)
+	stepEvent.DurationMs = time.Since(stepStartedAt).Milliseconds()
+
+	return stream.Send(&proto.BuildAndStartDevEnvReply{
+		StepEvent: stepEvent,
+	})
+}
+
+// skipBuildStep reports a step that was not run
+func skipBuildStep(
+	stream proto.Agent_BuildAndStartDevEnvServer,
+	step buildStep,
+	reason string,
+) error {
+
+	stepEvent := buildStepEvent(step, proto.StepStatus_STEP_STATUS_SKIPPED)
+	stepEvent.ErrorMessage = reason
+
+	return stream.Send(&proto.BuildAndStartDevEnvReply{
+		StepEvent: stepEvent,
+	})
+}
+
+var buildStepErrorCodes = map[proto.StepType]proto.ErrorCode{
+	proto.StepType_STEP_TYPE_CLONE_USER_CONFIG_REPO:   proto.ErrorCode_ERROR_CODE_CLONE_FAILED,
+	proto.StepType_STEP_TYPE_CLONE_DEV_ENV_REPO:       proto.ErrorCode_ERROR_CODE_CLONE_FAILED,
+	proto.StepType_STEP_TYPE_CLONE_REPO:               proto.ErrorCode_ERROR_CODE_CLONE_FAILED,
+	proto.StepType_STEP_TYPE_SYNC_REPO:                proto.ErrorCode_ERROR_CODE_CLONE_FAILED,
+	proto.StepType_STEP_TYPE_INIT_SUBMODULE:           proto.ErrorCode_ERROR_CODE_CLONE_FAILED,
+	proto.StepType_STEP_TYPE_PULL_LFS_OBJECTS:         proto.ErrorCode_ERROR_CODE_CLONE_FAILED,
+	proto.StepType_STEP_TYPE_BUILD_USER_CONFIG_IMAGE:  proto.ErrorCode_ERROR_CODE_IMAGE_BUILD_FAILED,
+	proto.StepType_STEP_TYPE_BUILD_DEV_ENV_REPO_IMAGE: proto.ErrorCode_ERROR_CODE_IMAGE_BUILD_FAILED,
+	proto.StepType_STEP_TYPE_START_CONTAINER:          proto.ErrorCode_ERROR_CODE_CONTAINER_START_FAILED,
+	proto.StepType_STEP_TYPE_RUN_HOOK:                 proto.ErrorCode_ERROR_CODE_HOOK_FAILED,
+}
+
+func buildStepEvent(
+	step buildStep,
+	status proto.StepStatus,
+) *proto.StepEvent {
+
+	return &proto.StepEvent{
+		StepId:      step.ID,
+		StepType:    step.Type,
+		Status:      status,
+		Description: step.Description,
+		Repository:  step.Repository,
+	}
+}
+
+// BuildStepErrorCode returns the error code
+// that corresponds to the passed error.
+func BuildStepErrorCode(err error) proto.ErrorCode {
+	if err == nil {
+		return proto.ErrorCode_ERROR_CODE_UNSPECIFIED
+	}
+
+	if errors.Is(err, context.Canceled) {
+		return proto.ErrorCode_ERROR_CODE_CANCELED
+	}
+
+	var buildStepErr *BuildStepError
+
+	if errors.As(err, &buildStepErr) {
+		return buildStepErr.Code
+	}
+
+	return proto.ErrorCode_ERROR_CODE_INTERNAL
+}
+
+// BuildStepsRecorder records the last event of each build step sent
+// through the wrapped stream to let the result summarize the build.
+// Concurrent calls to "Send" are not supported
+// (see "syncBuildAndStartDevEnvStream").
+type BuildStepsRecorder struct {
+	proto.Agent_BuildAndStartDevEnvServer
+	stepIDs        []string
+	stepLastEvents map[string]*proto.StepEvent
+}
+
+func NewBuildStepsRecorder(
+	stream proto.Agent_BuildAndStartDevEnvServer,
+) *BuildStepsRecorder {
+
+	return &BuildStepsRecorder{
+		Agent_BuildAndStartDevEnvServer: stream,
+		stepIDs:                         []string{},
+		stepLastEvents:                  map[string]*proto.StepEvent{},
+	}
+}
+
+func (b *BuildStepsRecorder) Send(reply *proto.BuildAndStartDevEnvReply) error {
+	if reply.StepEvent != nil {
+		stepID := reply.StepEvent.StepId
+
+		if _, ok := b.stepLastEvents[stepID]; !ok {
+			b.stepIDs = append(b.stepIDs, stepID)
+		}
+
+		b.stepLastEvents[stepID] = reply.StepEvent
+	}
+
+	return b.Agent_BuildAndStartDevEnvServer.Send(reply)
+}
+
+// Steps returns the last event of each step in start order
+func (b *BuildStepsRecorder) Steps() []*proto.StepEvent {
+	steps := []*proto.StepEvent{}
+
+	for _, stepID := range b.stepIDs {
+		steps = append(steps, b.stepLastEvents[stepID])
+	}
+
+	return steps
+}
+
+// NewBuildAndStartDevEnvResult builds the last message
+// sent during the "BuildAndStartDevEnv" method.
+func NewBuildAndStartDevEnvResult(
+	startedAt time.Time,
+	err error,
+	steps []*proto.StepEvent,
+) *proto.BuildAndStartDevEnvResult {
+
+	result := &proto.BuildAndStartDevEnvResult{
+		Success:    err == nil,
+		DurationMs: time.Since(startedAt).Milliseconds(),
+		Steps:      steps,
+	}
+
+	if err != nil {
+		result.ErrorCode = BuildStepErrorCode(err)
+		result.ErrorMessage = err.Error()
+	}
+
+	for _, step := range steps {
+		if step.StepType == proto.StepType_STEP_TYPE_START_CONTAINER &&
+			step.Status == proto.StepStatus_STEP_STATUS_FINISHED {
+
+			result.DevEnvStarted = true
+		}
+	}
+
+	result.Summary = buildBuildAndStartDevEnvSummary(result)
+
+	return result
+}
+
+// buildBuildAndStartDevEnvSummary lists the failed and skipped steps
+// (eg: "The development environment is up, but 1 step has failed:")
+func buildBuildAndStartDevEnvSummary(
+	result *proto.BuildAndStartDevEnvResult,
+) string {
+
+	failedSteps := 0
+	skippedSteps := 0
+	stepLines := []string{}
+
+	for _, step := range result.Steps {
+		stepName := step.Description
+
+		if len(step.Repository) > 0 {
+			stepName = step.Repository + ": " + stepName
+		}
+
+		switch step.Status {
+		case proto.StepStatus_STEP_STATUS_FAILED:
+			failedSteps++
+			stepLines = append(stepLines, fmt.Sprintf(
+				"  - %s (failed: %s)",
+				stepName,
+				step.ErrorMessage,
+			))
+		case proto.StepStatus_STEP_STATUS_SKIPPED:
+			skippedSteps++
+			stepLines = append(stepLines, fmt.Sprintf(
+				"  - %s (skipped: %s)",
+				stepName,
+				step.ErrorMessage,
+			))
+		}
+	}
+
+	if failedSteps == 0 && skippedSteps == 0 {
+		return ""
+	}
+
+	counts := []string{}
+
+	if failedSteps > 0 {
+		counts = append(counts, pluralizeSteps(failedSteps, "has failed", "have failed"))
+	}
+
+	if skippedSteps > 0 {
+		counts = append(counts, pluralizeSteps(skippedSteps, "was skipped", "were skipped"))
+	}
+
+	summaryHeader := "The development environment could not be started: "
+
+	if result.DevEnvStarted {
+		summaryHeader = "The development environment is up, but "
+	}
+
+	return summaryHeader + strings.Join(counts, " and ") + ":\n" +
+		strings.Join(stepLines, "\n")
+}
+
+func pluralizeSteps(count int, singularVerb, pluralVerb string) string {
+	if count == 1 {
+		return "1 step " + singularVerb
+	}
+
+	return fmt.Sprintf("%d steps %s", count, pluralVerb)
+}
@@ -0,0 +1,110 @@
+package devenv
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/recode-sh/agent/proto"
+)
+
+func TestBuildAndStartDevEnvResultWithFailedHooks(t *testing.T) {
+	stepsRecorder := NewBuildStepsRecorder(&testBuildAndStartDevEnvStream{})
+
+	startContainerStep := buildStep{
+		ID:          buildStepIDStartContainer,
+		Type:        proto.StepType_STEP_TYPE_START_CONTAINER,
+		Description: "Starting container",
+	}
+
+	webHookStep := buildStep{
+		ID:          buildStepIDRunHook("acme", "web", "init.sh"),
+		Type:        proto.StepType_STEP_TYPE_RUN_HOOK,
+		Description: "Running acme/web/.recode/hooks/init.sh",
+		Repository:  "acme/web",
+	}
+
+	docsHookStep := buildStep{
+		ID:          buildStepIDRunHook("acme", "docs", "init.sh"),
+		Type:        proto.StepType_STEP_TYPE_RUN_HOOK,
+		Description: "Running acme/docs/.recode/hooks/init.sh",
+		Repository:  "acme/docs",
+	}
+
+	err := runBuildStep(stepsRecorder, startContainerStep, func() error {
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	// The error is returned by the step
+	_ = runBuildStep(stepsRecorder, webHookStep, func() error {
+		return errors.New("exit status code 1")
+	})
+
+	err = skipBuildStep(stepsRecorder, docsHookStep, "the hook of \"acme/web\" has not succeeded")
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	result := NewBuildAndStartDevEnvResult(
+		time.Now(),
+		nil,
+		stepsRecorder.Steps(),
+	)
+
+	if !result.Success || !result.DevEnvStarted {
+		t.Fatalf("expected success and started dev env, got '%+v'", result)
+	}
+
+	expectedStatuses := []proto.StepStatus{
+		proto.StepStatus_STEP_STATUS_FINISHED,
+		proto.StepStatus_STEP_STATUS_FAILED,
+		proto.StepStatus_STEP_STATUS_SKIPPED,
+	}
+
+	if len(result.Steps) != len(expectedStatuses) {
+		t.Fatalf("expected '%d' steps, got '%d'", len(expectedStatuses), len(result.Steps))
+	}
+
+	for stepIndex, expectedStatus := range expectedStatuses {
+		if result.Steps[stepIndex].Status != expectedStatus {
+			t.Fatalf(
+				"expected step '%d' status to equal '%s', got '%s'",
+				stepIndex,
+				expectedStatus,
+				result.Steps[stepIndex].Status,
+			)
+		}
+	}
+
+	expectedSummaryPrefix := "The development environment is up, but 1 step has failed and 1 step was skipped:\n"
+
+	if !strings.HasPrefix(result.Summary, expectedSummaryPrefix) {
+		t.Fatalf("expected summary to start with '%s', got '%s'", expectedSummaryPrefix, result.Summary)
+	}
+
+	expectedFailedStepLine := "  - acme/web: Running acme/web/.recode/hooks/init.sh (failed: exit status code 1)"
+
+	if !strings.Contains(result.Summary, expectedFailedStepLine) {
+		t.Fatalf("expected summary to contain '%s', got '%s'", expectedFailedStepLine, result.Summary)
+	}
+}
+
+func TestBuildAndStartDevEnvResultWithoutFailedSteps(t *testing.T) {
+	result := NewBuildAndStartDevEnvResult(time.Now(), nil, []*proto.StepEvent{
+		{
+			StepId:   buildStepIDStartContainer,
+			StepType: proto.StepType_STEP_TYPE_START_CONTAINER,
+			Status:   proto.StepStatus_STEP_STATUS_FINISHED,
+		},
+	})
+
+	if len(result.Summary) > 0 {
+		t.Fatalf("expected no summary, got '%s'", result.Summary)
+	}
+}
@@ -0,0 +1,174 @@
+package devenv
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/recode-sh/agent/constants"
+	"github.com/recode-sh/agent/internal/system"
+)
+
+// Used in Docker image names that must be lowercase
+var devEnvNameRegExp = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,31}$`)
+
+// DevEnv holds the names and paths of a development environment.
+// One instance may host multiple development environments.
+type DevEnv struct {
+	Name                               string
+	DockerImageName                    string
+	DockerContainerName                string
+	WorkspaceDirPath                   string
+	WorkspaceArchiveDirPath            string
+	WorkspaceConfigDirPath             string
+	WorkspaceConfigHooksDirPath        string
+	WorkspaceConfigHooksResultsDirPath string
+	WorkspaceConfigFilePath            string
+	VSCodeWorkspaceConfigFilePath      string
+	DockerImagesHistoryFilePath        string
+}
+
+// NewDevEnv returns the development environment named "name".
+// An empty name corresponds to the default development environment.
+func NewDevEnv(name string) (*DevEnv, error) {
+	if len(name) == 0 || name == constants.DevEnvDefaultName {
+		return newDefaultDevEnv(), nil
+	}
+
+	if !devEnvNameRegExp.MatchString(name) {
+		return nil, fmt.Errorf(
+			"invalid development environment name \"%s\" (expected: %s)",
+			name,
+			devEnvNameRegExp.String(),
+		)
+	}
+
+	// Named development environments use the same
+	// names and paths than the default one suffixed
+	// with their name to avoid migrating existing instances
+	workspaceConfigDirPath := constants.DevEnvWorkspaceConfigDirPath + "-" + name
+
+	return &DevEnv{
+		Name:                               name,
+		DockerImageName:                    constants.DevEnvDockerImageName + "-" + name,
+		DockerContainerName:                constants.DevEnvDockerContainerName + "-" + name,
+		WorkspaceDirPath:                   constants.DevEnvWorkspaceDirPath + "-" + name,
+		WorkspaceArchiveDirPath:            constants.DevEnvWorkspaceArchiveDirPath + "-" + name,
+		WorkspaceConfigDirPath:             workspaceConfigDirPath,
+		WorkspaceConfigHooksDirPath:        filepath.Join(workspaceConfigDirPath, "hooks"),
+		WorkspaceConfigHooksResultsDirPath: filepath.Join(workspaceConfigDirPath, "hooks-results"),
+		WorkspaceConfigFilePath:            filepath.Join(workspaceConfigDirPath, "recode.workspace"),
+		VSCodeWorkspaceConfigFilePath:      filepath.Join(workspaceConfigDirPath, "recode.code-workspace"),
+		DockerImagesHistoryFilePath:        filepath.Join(constants.DevEnvDockerImagesHistoryDirPath, name+".json"),
+	}, nil
+}
+
+func newDefaultDevEnv() *DevEnv {
+	return &DevEnv{
+		Name:                               constants.DevEnvDefaultName,
+		DockerImageName:                    constants.DevEnvDockerImageName,
+		DockerContainerName:                constants.DevEnvDockerContainerName,
+		WorkspaceDirPath:                   constants.DevEnvWorkspaceDirPath,
+		WorkspaceArchiveDirPath:            constants.DevEnvWorkspaceArchiveDirPath,
+		WorkspaceConfigDirPath:             constants.DevEnvWorkspaceConfigDirPath,
+		WorkspaceConfigHooksDirPath:        constants.DevEnvWorkspaceConfigHooksDirPath,
+		WorkspaceConfigHooksResultsDirPath: constants.DevEnvWorkspaceConfigHooksResultsDirPath,
+		WorkspaceConfigFilePath:            constants.DevEnvWorkspaceConfigFilePath,
+		VSCodeWorkspaceConfigFilePath:      constants.DevEnvVSCodeWorkspaceConfigFilePath,
+		DockerImagesHistoryFilePath:        filepath.Join(constants.DevEnvDockerImagesHistoryDirPath, constants.DevEnvDefaultName+".json"),
+	}
+}
+
+// LoadWorkspaceConfig loads the workspace config of the development
+// environment. The workspace of the named development environments
+// (created during their first build) is initialized if needed.
+func (d *DevEnv) LoadWorkspaceConfig() (*WorkspaceConfig, error) {
+	workspaceConfig, err := LoadWorkspaceConfig(d.WorkspaceConfigFilePath)
+
+	// The workspace config of the default
+	// development environment is created during instance init
+	if err == nil || !os.IsNotExist(err) || d.Name == constants.DevEnvDefaultName {
+		return workspaceConfig, err
+	}
+
+	for _, dirPath := range []string{d.WorkspaceDirPath, d.WorkspaceConfigDirPath} {
+		err = os.MkdirAll(dirPath, os.FileMode(0755))
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	workspaceConfig = NewWorkspaceConfig()
+
+	err = SaveWorkspaceConfigAsFile(d.WorkspaceConfigFilePath, workspaceConfig)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return workspaceConfig, nil
+}
+
+// ListDevEnvs returns the development environments
+// that have a workspace config (the default one first).
+func ListDevEnvs() ([]*DevEnv, error) {
+	devEnvs := []*DevEnv{}
+
+	filesManager := system.NewFileManager()
+
+	defaultDevEnv := newDefaultDevEnv()
+
+	defaultDevEnvExists, err := filesManager.DoesFileExist(
+		defaultDevEnv.WorkspaceConfigFilePath,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if defaultDevEnvExists {
+		devEnvs = append(devEnvs, defaultDevEnv)
+	}
+
+	workspaceConfigDirPaths, err := filepath.Glob(
+		constants.DevEnvWorkspaceConfigDirPath + "-*",
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(workspaceConfigDirPaths)
+
+	for _, workspaceConfigDirPath := range workspaceConfigDirPaths {
+		devEnv, err := NewDevEnv(
+			strings.TrimPrefix(
+				workspaceConfigDirPath,
+				constants.DevEnvWorkspaceConfigDirPath+"-",
+			),
+		)
+
+		// Not created by the agent
+		if err != nil {
+			continue
+		}
+
+		devEnvExists, err := filesManager.DoesFileExist(
+			devEnv.WorkspaceConfigFilePath,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if devEnvExists {
+			devEnvs = append(devEnvs, devEnv)
+		}
+	}
+
+	return devEnvs, nil
+}
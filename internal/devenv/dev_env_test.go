@@ -0,0 +1,99 @@
+package devenv
+
+import (
+	"testing"
+
+	"github.com/recode-sh/agent/constants"
+)
+
+func TestNewDevEnv(t *testing.T) {
+	testCases := []struct {
+		test                        string
+		name                        string
+		expectedName                string
+		expectedDockerContainerName string
+		expectedWorkspaceDirPath    string
+		expectError                 bool
+	}{
+		{
+			test:                        "empty_name",
+			name:                        "",
+			expectedName:                constants.DevEnvDefaultName,
+			expectedDockerContainerName: constants.DevEnvDockerContainerName,
+			expectedWorkspaceDirPath:    constants.DevEnvWorkspaceDirPath,
+			expectError:                 false,
+		},
+
+		{
+			test:                        "default_name",
+			name:                        constants.DevEnvDefaultName,
+			expectedName:                constants.DevEnvDefaultName,
+			expectedDockerContainerName: constants.DevEnvDockerContainerName,
+			expectedWorkspaceDirPath:    constants.DevEnvWorkspaceDirPath,
+			expectError:                 false,
+		},
+
+		{
+			test:                        "named",
+			name:                        "api",
+			expectedName:                "api",
+			expectedDockerContainerName: constants.DevEnvDockerContainerName + "-api",
+			expectedWorkspaceDirPath:    constants.DevEnvWorkspaceDirPath + "-api",
+			expectError:                 false,
+		},
+
+		{
+			test:        "invalid_name",
+			name:        "../api",
+			expectError: true,
+		},
+
+		{
+			test:        "uppercase_name",
+			name:        "API",
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.test, func(t *testing.T) {
+			devEnv, err := NewDevEnv(tc.name)
+
+			if tc.expectError {
+				if err == nil {
+					t.Fatalf("expected error, got nothing")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error, got '%+v'", err)
+			}
+
+			if devEnv.Name != tc.expectedName {
+				t.Fatalf(
+					"expected name to equal '%s', got '%s'",
+					tc.expectedName,
+					devEnv.Name,
+				)
+			}
+
+			if devEnv.DockerContainerName != tc.expectedDockerContainerName {
+				t.Fatalf(
+					"expected container name to equal '%s', got '%s'",
+					tc.expectedDockerContainerName,
+					devEnv.DockerContainerName,
+				)
+			}
+
+			if devEnv.WorkspaceDirPath != tc.expectedWorkspaceDirPath {
+				t.Fatalf(
+					"expected workspace dir path to equal '%s', got '%s'",
+					tc.expectedWorkspaceDirPath,
+					devEnv.WorkspaceDirPath,
+				)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package devenv
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
@@ -10,15 +11,50 @@ import (
 	"github.com/docker/docker/client"
 	"github.com/recode-sh/agent/constants"
 	"github.com/recode-sh/agent/internal/docker"
+	"github.com/recode-sh/agent/proto"
 )
 
+// StartBuiltDockerContainer ensures that the container
+// is running once the development environment is built.
+func StartBuiltDockerContainer(
+	ctx context.Context,
+	dockerClient *client.Client,
+	stream proto.Agent_BuildAndStartDevEnvServer,
+	devEnv *DevEnv,
+	retryPolicy RetryPolicy,
+) error {
+
+	return runBuildStep(
+		stream,
+		buildStep{
+			ID:   buildStepIDStartContainer,
+			Type: proto.StepType_STEP_TYPE_START_CONTAINER,
+			Description: fmt.Sprintf(
+				"Starting %s",
+				devEnv.DockerContainerName,
+			),
+		},
+		func() error {
+			return retryWithBackoff(
+				ctx,
+				retryPolicy,
+				func() error {
+					return EnsureDockerContainerRunning(dockerClient, devEnv)
+				},
+				newRetryLogLineSender(stream, retryPolicy),
+			)
+		},
+	)
+}
+
 func EnsureDockerContainerRunning(
 	dockerClient *client.Client,
+	devEnv *DevEnv,
 ) error {
 
 	isContainerRunning, err := docker.IsContainerRunning(
 		dockerClient,
-		constants.DevEnvDockerContainerName,
+		devEnv.DockerContainerName,
 	)
 
 	if err != nil {
@@ -31,7 +67,7 @@ func EnsureDockerContainerRunning(
 
 	dockerContainer, err := docker.LookupContainer(
 		dockerClient,
-		constants.DevEnvDockerContainerName,
+		devEnv.DockerContainerName,
 	)
 
 	if err != nil {
@@ -50,8 +86,8 @@ func EnsureDockerContainerRunning(
 		context.TODO(),
 
 		&container.Config{
-			WorkingDir: constants.DevEnvWorkspaceDirPath,
-			Image:      constants.DevEnvDockerImageName,
+			WorkingDir: devEnv.WorkspaceDirPath,
+			Image:      devEnv.DockerImageName,
 			User:       constants.DevEnvRecodeUserName,
 			Entrypoint: strslice.StrSlice{
 				constants.DevEnvDockerContainerEntrypointFilePath,
@@ -61,7 +97,7 @@ func EnsureDockerContainerRunning(
 
 		&container.HostConfig{
 			AutoRemove:  false,
-			Binds:       buildHostMounts(),
+			Binds:       buildHostMounts(devEnv),
 			NetworkMode: container.NetworkMode("host"),
 			Privileged:  true,
 			RestartPolicy: container.RestartPolicy{
@@ -73,7 +109,7 @@ func EnsureDockerContainerRunning(
 
 		nil,
 
-		constants.DevEnvDockerContainerName,
+		devEnv.DockerContainerName,
 	)
 
 	if err != nil {
@@ -87,20 +123,20 @@ func EnsureDockerContainerRunning(
 	)
 }
 
-func buildHostMounts() []string {
+func buildHostMounts(devEnv *DevEnv) []string {
 	return []string{
 		// Working dir
 
 		fmt.Sprintf(
 			"%s:%s",
-			constants.DevEnvWorkspaceDirPath,
-			constants.DevEnvWorkspaceDirPath,
+			devEnv.WorkspaceDirPath,
+			devEnv.WorkspaceDirPath,
 		),
 
 		fmt.Sprintf(
 			"%s:%s",
-			constants.DevEnvWorkspaceConfigDirPath,
-			constants.DevEnvWorkspaceConfigDirPath,
+			devEnv.WorkspaceConfigDirPath,
+			devEnv.WorkspaceConfigDirPath,
 		),
 
 		/* Config files are mounted to /etc/
@@ -168,10 +204,14 @@ func buildHostMounts() []string {
 	}
 }
 
-func EnsureDockerContainerRemoved(dockerClient *client.Client) error {
+func EnsureDockerContainerRemoved(
+	dockerClient *client.Client,
+	devEnv *DevEnv,
+) error {
+
 	dockerContainer, err := docker.LookupContainer(
 		dockerClient,
-		constants.DevEnvDockerContainerName,
+		devEnv.DockerContainerName,
 	)
 
 	if err != nil {
@@ -190,3 +230,33 @@ func EnsureDockerContainerRemoved(dockerClient *client.Client) error {
 		},
 	)
 }
+
+func EnsureDockerContainerStopped(
+	dockerClient *client.Client,
+	devEnv *DevEnv,
+	stopTimeout time.Duration,
+) error {
+
+	dockerContainer, err := docker.LookupContainer(
+		dockerClient,
+		devEnv.DockerContainerName,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	if dockerContainer == nil ||
+		dockerContainer.State != string(docker.ContainerStateRunning) {
+
+		return nil
+	}
+
+	// The container will receive a "SIGTERM" signal
+	// and then a "SIGKILL" one once the timeout has expired
+	return dockerClient.ContainerStop(
+		context.TODO(),
+		dockerContainer.ID,
+		&stopTimeout,
+	)
+}
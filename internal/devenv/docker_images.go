@@ -0,0 +1,373 @@
+package devenv
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/errdefs"
+	"github.com/recode-sh/agent/internal/docker"
+	"github.com/recode-sh/agent/proto"
+)
+
+var (
+	ErrNoPreviousDevEnvImage = errors.New("no previous image to roll back to")
+	ErrDevEnvImageNotFound   = errors.New("image not found")
+)
+
+// DevEnvImage represents the image of a successful build
+// of a development environment. The images of the last builds
+// are tagged and kept to let users roll back to them.
+type DevEnvImage struct {
+	Tag                 string    `json:"tag"`
+	ImageID             string    `json:"image_id"`
+	BuiltAt             time.Time `json:"built_at"`
+	DevEnvRepoCommitSHA string    `json:"dev_env_repo_commit_sha,omitempty"`
+}
+
+type devEnvImagesHistory struct {
+	// Most recent first
+	Images []DevEnvImage `json:"images"`
+}
+
+// LoadDevEnvImages returns the images kept
+// for the development environment (most recent first)
+func LoadDevEnvImages(devEnv *DevEnv) ([]DevEnvImage, error) {
+	historyAsJSON, err := os.ReadFile(devEnv.DockerImagesHistoryFilePath)
+
+	// Not built yet (or built before images were tagged)
+	if err != nil && os.IsNotExist(err) {
+		return []DevEnvImage{}, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	history := devEnvImagesHistory{}
+	err = json.Unmarshal(historyAsJSON, &history)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return history.Images, nil
+}
+
+func saveDevEnvImages(devEnv *DevEnv, images []DevEnvImage) error {
+	historyAsJSON, err := json.Marshal(devEnvImagesHistory{
+		Images: images,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(
+		filepath.Dir(devEnv.DockerImagesHistoryFilePath),
+		os.FileMode(0755),
+	)
+
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(
+		devEnv.DockerImagesHistoryFilePath,
+		historyAsJSON,
+		os.FileMode(0644),
+	)
+}
+
+// buildDevEnvImageTag returns the tag of a built image derived from
+// the commit of the dev env repository and from the image ID
+// (eg: "3f2a1bc-9e8d7c6b5a4f"). Rebuilding the same content
+// at the same commit returns the same tag.
+func buildDevEnvImageTag(devEnvRepoCommitSHA, imageID string) string {
+	imageIDDigest := strings.TrimPrefix(imageID, "sha256:")
+
+	if len(imageIDDigest) > 12 {
+		imageIDDigest = imageIDDigest[:12]
+	}
+
+	if len(devEnvRepoCommitSHA) == 0 {
+		return imageIDDigest
+	}
+
+	if len(devEnvRepoCommitSHA) > 7 {
+		devEnvRepoCommitSHA = devEnvRepoCommitSHA[:7]
+	}
+
+	return devEnvRepoCommitSHA + "-" + imageIDDigest
+}
+
+// addDevEnvImage adds the passed image at the top of the images
+// (replacing the one with the same tag, if any) and returns the
+// images to keep and the ones to remove (above "keepImages")
+func addDevEnvImage(
+	images []DevEnvImage,
+	image DevEnvImage,
+	keepImages int,
+) ([]DevEnvImage, []DevEnvImage) {
+
+	updatedImages := []DevEnvImage{image}
+
+	for _, existingImage := range images {
+		if existingImage.Tag == image.Tag {
+			continue
+		}
+
+		updatedImages = append(updatedImages, existingImage)
+	}
+
+	if len(updatedImages) <= keepImages {
+		return updatedImages, []DevEnvImage{}
+	}
+
+	return updatedImages[:keepImages], updatedImages[keepImages:]
+}
+
+// TagBuiltDockerImage tags the image that was just built and removes
+// the tags of the images built before the last "keepImages" builds.
+// The untagged images are then removed by the build cache prune.
+func TagBuiltDockerImage(
+	ctx context.Context,
+	dockerClient *client.Client,
+	stream proto.Agent_BuildAndStartDevEnvServer,
+	devEnv *DevEnv,
+	workspaceConfig *WorkspaceConfig,
+	keepImages int,
+) error {
+
+	builtImage, err := docker.LookupImage(dockerClient, devEnv.DockerImageName)
+
+	if err != nil {
+		return err
+	}
+
+	if builtImage == nil {
+		return fmt.Errorf(
+			"the image \"%s\" was not found after the build",
+			devEnv.DockerImageName,
+		)
+	}
+
+	devEnvRepoCommitSHA := ""
+
+	for _, repo := range workspaceConfig.Repositories {
+		if repo.IsDevEnvRepo {
+			devEnvRepoCommitSHA = repo.CommitSHA
+		}
+	}
+
+	image := DevEnvImage{
+		Tag:                 buildDevEnvImageTag(devEnvRepoCommitSHA, builtImage.ID),
+		ImageID:             builtImage.ID,
+		BuiltAt:             time.Now(),
+		DevEnvRepoCommitSHA: devEnvRepoCommitSHA,
+	}
+
+	err = dockerClient.ImageTag(
+		ctx,
+		builtImage.ID,
+		devEnv.DockerImageName+":"+image.Tag,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	removedImages, err := recordDevEnvImage(devEnv, image, keepImages)
+
+	if err != nil {
+		return err
+	}
+
+	for _, removedImage := range removedImages {
+		// Only the tag is removed if the image has other tags.
+		// The images still used by a container are not removed.
+		_, err = dockerClient.ImageRemove(
+			ctx,
+			devEnv.DockerImageName+":"+removedImage.Tag,
+			types.ImageRemoveOptions{
+				PruneChildren: true,
+			},
+		)
+
+		if err != nil && !client.IsErrNotFound(err) && !errdefs.IsConflict(err) {
+			return err
+		}
+	}
+
+	return stream.Send(&proto.BuildAndStartDevEnvReply{
+		LogLine: fmt.Sprintf(
+			"Tagged %s:%s\n",
+			devEnv.DockerImageName,
+			image.Tag,
+		),
+	})
+}
+
+// recordDevEnvImage adds the passed image to the history of the
+// development environment and returns the images to untag
+func recordDevEnvImage(
+	devEnv *DevEnv,
+	image DevEnvImage,
+	keepImages int,
+) ([]DevEnvImage, error) {
+
+	images, err := LoadDevEnvImages(devEnv)
+
+	if err != nil {
+		return nil, err
+	}
+
+	keptImages, removedImages := addDevEnvImage(images, image, keepImages)
+
+	err = saveDevEnvImages(devEnv, keptImages)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return removedImages, nil
+}
+
+// lookupDevEnvRollbackImage returns the image with the passed
+// tag or, if no tag is passed, the image built before the
+// current one (or the most recent one if the current one is unknown)
+func lookupDevEnvRollbackImage(
+	images []DevEnvImage,
+	currentImageID string,
+	imageTag string,
+) (DevEnvImage, error) {
+
+	if len(imageTag) > 0 {
+		for _, image := range images {
+			if image.Tag == imageTag {
+				return image, nil
+			}
+		}
+
+		return DevEnvImage{}, fmt.Errorf("%w: \"%s\"", ErrDevEnvImageNotFound, imageTag)
+	}
+
+	for imageIndex, image := range images {
+		if image.ImageID != currentImageID {
+			continue
+		}
+
+		if imageIndex+1 == len(images) {
+			return DevEnvImage{}, ErrNoPreviousDevEnvImage
+		}
+
+		return images[imageIndex+1], nil
+	}
+
+	if len(images) == 0 {
+		return DevEnvImage{}, ErrNoPreviousDevEnvImage
+	}
+
+	return images[0], nil
+}
+
+// RollbackDockerImage points the development environment image
+// to a previous image. The container must be recreated next.
+func RollbackDockerImage(
+	ctx context.Context,
+	dockerClient *client.Client,
+	devEnv *DevEnv,
+	imageTag string,
+) (DevEnvImage, error) {
+
+	images, err := LoadDevEnvImages(devEnv)
+
+	if err != nil {
+		return DevEnvImage{}, err
+	}
+
+	currentImageID := ""
+	currentImage, err := docker.LookupImage(dockerClient, devEnv.DockerImageName)
+
+	if err != nil {
+		return DevEnvImage{}, err
+	}
+
+	if currentImage != nil {
+		currentImageID = currentImage.ID
+	}
+
+	rollbackImage, err := lookupDevEnvRollbackImage(
+		images,
+		currentImageID,
+		imageTag,
+	)
+
+	if err != nil {
+		return DevEnvImage{}, err
+	}
+
+	rollbackImageRef := devEnv.DockerImageName + ":" + rollbackImage.Tag
+	dockerImage, err := docker.LookupImage(dockerClient, rollbackImageRef)
+
+	if err != nil {
+		return DevEnvImage{}, err
+	}
+
+	// Removed outside of the agent
+	if dockerImage == nil {
+		return DevEnvImage{}, fmt.Errorf(
+			"%w: \"%s\" was removed",
+			ErrDevEnvImageNotFound,
+			rollbackImageRef,
+		)
+	}
+
+	err = dockerClient.ImageTag(ctx, dockerImage.ID, devEnv.DockerImageName)
+
+	if err != nil {
+		return DevEnvImage{}, err
+	}
+
+	return rollbackImage, nil
+}
+
+// RunWorkspaceHooksAfterRollback runs the "init" hooks (that have not
+// succeeded since the clone) and then the "start" hooks once the
+// container is recreated from a previous image. The caller must
+// hold the hooks lock (see "LockWorkspaceHooks").
+func RunWorkspaceHooksAfterRollback(
+	ctx context.Context,
+	dockerClient *client.Client,
+	devEnv *DevEnv,
+	outputWriter io.Writer,
+) error {
+
+	for _, hookType := range []WorkspaceHookType{
+		WorkspaceHookTypeInit,
+		WorkspaceHookTypeStart,
+	} {
+
+		err := runWorkspaceHooksOncePerContainerStart(
+			ctx,
+			dockerClient,
+			devEnv,
+			hookType,
+			outputWriter,
+		)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,334 @@
+package devenv
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/recode-sh/agent/proto"
+	"github.com/recode-sh/recode/entities"
+)
+
+func TestBuildDevEnvImageTag(t *testing.T) {
+	testCases := []struct {
+		test                string
+		devEnvRepoCommitSHA string
+		imageID             string
+		expectedTag         string
+	}{
+		{
+			test:                "commit_and_image_id",
+			devEnvRepoCommitSHA: "3f2a1bc4d5e6f7a8b9c0d1e2f3a4b5c6d7e8f9a0",
+			imageID:             "sha256:9e8d7c6b5a4f3e2d1c0b9a8f7e6d5c4b3a2f1e0d",
+			expectedTag:         "3f2a1bc-9e8d7c6b5a4f",
+		},
+
+		{
+			test:                "unknown_commit",
+			devEnvRepoCommitSHA: "",
+			imageID:             "sha256:9e8d7c6b5a4f3e2d1c0b9a8f7e6d5c4b3a2f1e0d",
+			expectedTag:         "9e8d7c6b5a4f",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.test, func(t *testing.T) {
+			tag := buildDevEnvImageTag(tc.devEnvRepoCommitSHA, tc.imageID)
+
+			if tag != tc.expectedTag {
+				t.Fatalf("expected tag '%s', got '%s'", tc.expectedTag, tag)
+			}
+		})
+	}
+}
+
+func TestAddDevEnvImage(t *testing.T) {
+	testCases := []struct {
+		test                  string
+		images                []string
+		addedImage            string
+		keepImages            int
+		expectedKeptImages    []string
+		expectedRemovedImages []string
+	}{
+		{
+			test:                  "first_image",
+			images:                []string{},
+			addedImage:            "a",
+			keepImages:            3,
+			expectedKeptImages:    []string{"a"},
+			expectedRemovedImages: []string{},
+		},
+
+		{
+			test:                  "oldest_images_are_removed",
+			images:                []string{"c", "b", "a"},
+			addedImage:            "d",
+			keepImages:            2,
+			expectedKeptImages:    []string{"d", "c"},
+			expectedRemovedImages: []string{"b", "a"},
+		},
+
+		{
+			test:                  "rebuilt_image_is_moved_to_the_top",
+			images:                []string{"c", "b", "a"},
+			addedImage:            "b",
+			keepImages:            3,
+			expectedKeptImages:    []string{"b", "c", "a"},
+			expectedRemovedImages: []string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.test, func(t *testing.T) {
+			keptImages, removedImages := addDevEnvImage(
+				newTestDevEnvImages(tc.images),
+				DevEnvImage{Tag: tc.addedImage},
+				tc.keepImages,
+			)
+
+			if devEnvImagesTags(keptImages) != strings.Join(tc.expectedKeptImages, ",") {
+				t.Fatalf(
+					"expected kept images '%v', got '%s'",
+					tc.expectedKeptImages,
+					devEnvImagesTags(keptImages),
+				)
+			}
+
+			if devEnvImagesTags(removedImages) != strings.Join(tc.expectedRemovedImages, ",") {
+				t.Fatalf(
+					"expected removed images '%v', got '%s'",
+					tc.expectedRemovedImages,
+					devEnvImagesTags(removedImages),
+				)
+			}
+		})
+	}
+}
+
+func TestLookupDevEnvRollbackImage(t *testing.T) {
+	// Image IDs equal tags here
+	images := newTestDevEnvImages([]string{"c", "b", "a"})
+
+	testCases := []struct {
+		test           string
+		currentImageID string
+		imageTag       string
+		expectedTag    string
+		expectedErr    error
+	}{
+		{
+			test:           "previous_image",
+			currentImageID: "c",
+			expectedTag:    "b",
+		},
+
+		{
+			test:           "previous_image_after_rollback",
+			currentImageID: "b",
+			expectedTag:    "a",
+		},
+
+		{
+			test:           "no_previous_image",
+			currentImageID: "a",
+			expectedErr:    ErrNoPreviousDevEnvImage,
+		},
+
+		{
+			test:           "unknown_current_image",
+			currentImageID: "untagged",
+			expectedTag:    "c",
+		},
+
+		{
+			test:           "passed_tag",
+			currentImageID: "c",
+			imageTag:       "a",
+			expectedTag:    "a",
+		},
+
+		{
+			test:           "unknown_tag",
+			currentImageID: "c",
+			imageTag:       "z",
+			expectedErr:    ErrDevEnvImageNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.test, func(t *testing.T) {
+			image, err := lookupDevEnvRollbackImage(
+				images,
+				tc.currentImageID,
+				tc.imageTag,
+			)
+
+			if tc.expectedErr != nil {
+				if !errors.Is(err, tc.expectedErr) {
+					t.Fatalf("expected error '%+v', got '%+v'", tc.expectedErr, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error, got '%+v'", err)
+			}
+
+			if image.Tag != tc.expectedTag {
+				t.Fatalf("expected tag '%s', got '%s'", tc.expectedTag, image.Tag)
+			}
+		})
+	}
+}
+
+func TestDevEnvImagesSaveAndLoad(t *testing.T) {
+	devEnv := newTestDevEnv(t)
+
+	images, err := LoadDevEnvImages(devEnv)
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	if len(images) != 0 {
+		t.Fatalf("expected no images, got '%+v'", images)
+	}
+
+	builtAt := time.Date(2022, 4, 1, 10, 0, 0, 0, time.UTC)
+
+	err = saveDevEnvImages(devEnv, []DevEnvImage{
+		{
+			Tag:                 "3f2a1bc-9e8d7c6b5a4f",
+			ImageID:             "sha256:9e8d7c6b5a4f",
+			BuiltAt:             builtAt,
+			DevEnvRepoCommitSHA: "3f2a1bc",
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	images, err = LoadDevEnvImages(devEnv)
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	if len(images) != 1 || images[0].Tag != "3f2a1bc-9e8d7c6b5a4f" ||
+		!images[0].BuiltAt.Equal(builtAt) {
+
+		t.Fatalf("expected saved image, got '%+v'", images)
+	}
+}
+
+// The workspace config dir is cleared during full syncs
+// so the images history must not be stored in it
+func TestDevEnvImagesAreKeptAcrossWorkspacePreparations(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	gitReposBaseDirPath := t.TempDir()
+
+	createTestGitRepo(
+		t,
+		filepath.Join(gitReposBaseDirPath, "acme", "config"),
+		map[string]string{
+			entities.DevEnvUserConfigDockerfileFileName: "FROM recodesh/base-dev-env:latest\n",
+		},
+	)
+
+	createTestGitRepo(
+		t,
+		filepath.Join(gitReposBaseDirPath, "acme", "api"),
+		map[string]string{
+			"README.md": "# API\n",
+		},
+	)
+
+	gitProvider, err := NewGitProvider("file://" + gitReposBaseDirPath)
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	devEnv := newTestDevEnv(t)
+
+	_, err = recordDevEnvImage(devEnv, DevEnvImage{Tag: "a", ImageID: "a"}, 3)
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	preparedWorkspaceMetadata, err := PrepareWorkspace(
+		context.Background(),
+		&testBuildAndStartDevEnvStream{},
+		devEnv,
+		NewGitRepository(gitProvider, "acme", "config"),
+		NewGitRepository(gitProvider, "acme", "api"),
+		NewWorkspaceConfig(),
+		WorkspaceSyncOptions{},
+		ReposCloneOptions{},
+		proto.WorkspaceLayout_WORKSPACE_LAYOUT_NAME,
+	)
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	defer os.RemoveAll(preparedWorkspaceMetadata.TmpUserConfigRepoDirPath)
+	defer os.RemoveAll(preparedWorkspaceMetadata.TmpDevEnvRepoDirPath)
+
+	_, err = recordDevEnvImage(devEnv, DevEnvImage{Tag: "b", ImageID: "b"}, 3)
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	images, err := LoadDevEnvImages(devEnv)
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	rollbackImage, err := lookupDevEnvRollbackImage(images, "b", "")
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	if rollbackImage.Tag != "a" {
+		t.Fatalf("expected tag 'a', got '%s'", rollbackImage.Tag)
+	}
+}
+
+func newTestDevEnvImages(tags []string) []DevEnvImage {
+	images := []DevEnvImage{}
+
+	for _, tag := range tags {
+		images = append(images, DevEnvImage{
+			Tag:     tag,
+			ImageID: tag,
+		})
+	}
+
+	return images
+}
+
+func devEnvImagesTags(images []DevEnvImage) string {
+	tags := []string{}
+
+	for _, image := range images {
+		tags = append(tags, image.Tag)
+	}
+
+	return strings.Join(tags, ",")
+}
@@ -3,8 +3,11 @@ package devenv
 import (
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/recode-sh/agent/constants"
 	"github.com/recode-sh/agent/internal/docker"
+	"github.com/recode-sh/agent/proto"
 	"github.com/recode-sh/recode/entities"
 )
 
@@ -17,7 +20,7 @@ func ensureUserConfigDockerfileDerivesFromBaseDevEnv(
 	)
 
 	if err != nil {
-		return err
+		return newInvalidDockerfileError(err)
 	}
 
 	if !strings.HasPrefix(
@@ -25,11 +28,11 @@ func ensureUserConfigDockerfileDerivesFromBaseDevEnv(
 		entities.DevEnvUserConfigDockerfileRootImage,
 	) {
 
-		return fmt.Errorf(
+		return newInvalidDockerfileError(fmt.Errorf(
 			"\"%s\" must derive from \"%s\"",
 			entities.DevEnvUserConfigDockerfileFileName,
 			entities.DevEnvUserConfigDockerfileRootImage,
-		)
+		))
 	}
 
 	return nil
@@ -44,7 +47,7 @@ func ensureRepoDockerfileDerivesFromUserBase(
 	)
 
 	if err != nil {
-		return err
+		return newInvalidDockerfileError(err)
 	}
 
 	if !strings.HasPrefix(
@@ -52,11 +55,11 @@ func ensureRepoDockerfileDerivesFromUserBase(
 		entities.DevEnvUserConfigDockerfileImageName,
 	) {
 
-		return fmt.Errorf(
+		return newInvalidDockerfileError(fmt.Errorf(
 			"\"%s\" must derive from \"%s\"",
 			entities.DevEnvRepositoryDockerfileFileName,
 			entities.DevEnvUserConfigDockerfileImageName,
-		)
+		))
 	}
 
 	return nil
@@ -72,7 +75,7 @@ func lookupVSCodeExtensionsInDockerfileLabels(
 	)
 
 	if err != nil {
-		return nil, err
+		return nil, newInvalidDockerfileError(err)
 	}
 
 	if len(vscodeExtLabelValue) == 0 {
@@ -100,7 +103,7 @@ func lookupRepositoriesInDockerfileLabels(
 	)
 
 	if err != nil {
-		return nil, err
+		return nil, newInvalidDockerfileError(err)
 	}
 
 	if len(reposLabelValue) == 0 {
@@ -117,3 +120,139 @@ func lookupRepositoriesInDockerfileLabels(
 
 	return repos, nil
 }
+
+// lookupHooksDependenciesInDockerfileLabels returns the full names
+// of the repositories whose hooks must be run before the ones of
+// the repository. Names without owner take the repository owner.
+func lookupHooksDependenciesInDockerfileLabels(
+	dockerfileFilePath string,
+	repoOwner string,
+) ([]string, error) {
+
+	dependOnLabelValue, err := docker.LookupDockerfileLabelValue(
+		dockerfileFilePath,
+		constants.DevEnvDockerfilesHooksDependOnLabelKey,
+	)
+
+	if err != nil {
+		return nil, newInvalidDockerfileError(err)
+	}
+
+	if len(strings.TrimSpace(dependOnLabelValue)) == 0 {
+		return []string{}, nil
+	}
+
+	// -1 to return all matches
+	dependencies := entities.DevEnvDockerfilesReposLabelSepRegExp.Split(
+		strings.TrimSpace(dependOnLabelValue),
+		-1,
+	)
+
+	for dependencyIndex, dependency := range dependencies {
+		if len(dependency) == 0 {
+			return nil, newInvalidDockerfileError(fmt.Errorf(
+				"invalid hooks dependencies \"%s\" in label \"%s\"",
+				dependOnLabelValue,
+				constants.DevEnvDockerfilesHooksDependOnLabelKey,
+			))
+		}
+
+		if !strings.Contains(dependency, "/") {
+			dependencies[dependencyIndex] = repoOwner + "/" + dependency
+		}
+	}
+
+	return dependencies, nil
+}
+
+func lookupBuildArgsInDockerfileLabels(
+	dockerfileFilePath string,
+) (map[string]string, error) {
+
+	buildArgs := map[string]string{}
+
+	buildArgsLabelValue, err := docker.LookupDockerfileLabelValue(
+		dockerfileFilePath,
+		constants.DevEnvDockerfilesBuildArgsLabelKey,
+	)
+
+	if err != nil {
+		return nil, newInvalidDockerfileError(err)
+	}
+
+	if len(strings.TrimSpace(buildArgsLabelValue)) == 0 {
+		return buildArgs, nil
+	}
+
+	// -1 to return all matches
+	labelBuildArgs := entities.DevEnvDockerfilesReposLabelSepRegExp.Split(
+		strings.TrimSpace(buildArgsLabelValue),
+		-1,
+	)
+
+	for _, labelBuildArg := range labelBuildArgs {
+		buildArgNameAndValue := strings.SplitN(labelBuildArg, "=", 2)
+
+		if len(buildArgNameAndValue) != 2 {
+			return nil, newInvalidDockerfileError(fmt.Errorf(
+				"invalid build arg \"%s\" in label \"%s\" (expected \"KEY=value\")",
+				labelBuildArg,
+				constants.DevEnvDockerfilesBuildArgsLabelKey,
+			))
+		}
+
+		buildArgs[buildArgNameAndValue[0]] = buildArgNameAndValue[1]
+	}
+
+	return buildArgs, nil
+}
+
+func newInvalidDockerfileError(err error) *BuildStepError {
+	return newBuildStepError(
+		proto.ErrorCode_ERROR_CODE_INVALID_DOCKERFILE,
+		err,
+	)
+}
+
+// lookupHookTimeoutsInDockerfileLabels returns the hook timeouts
+// set in the "sh.recode.hooks.<type>.timeout" labels (eg: "20m")
+func lookupHookTimeoutsInDockerfileLabels(
+	dockerfileFilePath string,
+) (map[WorkspaceHookType]time.Duration, error) {
+
+	hookTimeouts := map[WorkspaceHookType]time.Duration{}
+
+	for _, hookType := range workspaceHookTypes {
+		hookTimeoutLabelKey := fmt.Sprintf(
+			constants.DevEnvDockerfilesHookTimeoutLabelKeyFormat,
+			hookType,
+		)
+
+		hookTimeoutLabelValue, err := docker.LookupDockerfileLabelValue(
+			dockerfileFilePath,
+			hookTimeoutLabelKey,
+		)
+
+		if err != nil {
+			return nil, newInvalidDockerfileError(err)
+		}
+
+		if len(hookTimeoutLabelValue) == 0 {
+			continue
+		}
+
+		hookTimeout, err := time.ParseDuration(hookTimeoutLabelValue)
+
+		if err != nil || hookTimeout < time.Second {
+			return nil, newInvalidDockerfileError(fmt.Errorf(
+				"invalid hook timeout \"%s\" in label \"%s\" (expected a duration like \"20m\")",
+				hookTimeoutLabelValue,
+				hookTimeoutLabelKey,
+			))
+		}
+
+		hookTimeouts[hookType] = hookTimeout
+	}
+
+	return hookTimeouts, nil
+}
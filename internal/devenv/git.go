@@ -2,68 +2,375 @@ package devenv
 
 import (
 	"bytes"
+	"context"
 	"fmt"
+	"os"
 	"os/exec"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
-
-	"github.com/recode-sh/recode/github"
 )
 
-func cloneGitHubRepo(
-	repoOwner string,
-	repoName string,
+func cloneGitRepo(
+	ctx context.Context,
+	repo *GitRepository,
 	cloneDir string,
+	progressStream *gitCloneProgressStream,
+	extraCloneArgs ...string,
 ) error {
 
-	maxRetries := 3
-	retriesInterval := 4 * time.Second
-	var lastErrorReturned error
-
-	// Avoid a GitHub race condition that
-	// prevent an SSH key pair to be used
-	// just after creation
-	for retry := 0; retry < maxRetries; retry++ {
-		githubGitURL := github.BuildGitURL(repoOwner, repoName)
-
-		cmd := exec.Command(
-			"git",
-			"clone",
-			"--quiet",
-			string(githubGitURL),
-			cloneDir,
-		)
+	err := ensureGitProviderSSHConfigured(ctx, repo.Provider)
 
-		var stdout bytes.Buffer
-		var stderr bytes.Buffer
+	if err != nil {
+		return err
+	}
 
-		cmd.Stdout = &stdout
-		cmd.Stderr = &stderr
+	cloneArgs := append(
+		repo.CloneOptions.buildCloneArgs(),
+		extraCloneArgs...,
+	)
 
-		err := cmd.Run()
+	// Shallow clones fetch only the default
+	// branch unless told otherwise
+	if repo.CloneOptions.Depth > 0 && len(repo.Ref) > 0 {
+		cloneArgs = append(cloneArgs, "--no-single-branch")
+	}
 
-		if err != nil {
-			newLineRegExp := regexp.MustCompile(`\n+`)
-
-			lastErrorReturned = fmt.Errorf(
-				"error while cloning the repository \"%s/%s\".\n\n%s\n\n%s",
-				repoOwner,
-				repoName,
-				strings.TrimSpace(
-					newLineRegExp.ReplaceAllLiteralString(stderr.String(), " "),
-				),
-				err.Error(),
+	// Transient errors (network, SSH keys not yet propagated by
+	// GitHub just after their creation...) are retried
+	err = retryWithBackoff(
+		ctx,
+		repo.RetryPolicy,
+		func() error {
+			return runGitClone(ctx, repo, cloneDir, cloneArgs, progressStream)
+		},
+		func(attempt int, _ time.Duration, lastErr error) error {
+			return progressStream.sendRetry(
+				attempt,
+				repo.RetryPolicy.MaxAttempts,
+				lastErr,
 			)
+		},
+	)
 
-			time.Sleep(retriesInterval)
+	if err != nil {
+		return err
+	}
 
-			continue
+	if repo.CloneOptions.isSparse() {
+		_, err = runGitCommand(
+			ctx,
+			cloneDir,
+			repo.CloneOptions.buildSparseCheckoutArgs()...,
+		)
+
+		if err != nil {
+			return err
 		}
+	}
+
+	if len(repo.Ref) == 0 {
+		return nil
+	}
+
+	return checkoutGitRef(
+		ctx,
+		cloneDir,
+		repo.Ref,
+		repo.CloneOptions.Depth,
+	)
+}
+
+func runGitClone(
+	ctx context.Context,
+	repo *GitRepository,
+	cloneDir string,
+	cloneArgs []string,
+	progressStream *gitCloneProgressStream,
+) error {
+
+	// The "git" process is killed if
+	// the context is canceled
+	cmd := exec.CommandContext(
+		ctx,
+		"git",
+		append(
+			append([]string{"clone", "--progress"}, cloneArgs...),
+			"--",
+			repo.CloneURL(),
+			cloneDir,
+		)...,
+	)
+
+	cmd.Env = append(os.Environ(), gitLFSSkipSmudgeEnvVar)
+
+	var stdout bytes.Buffer
+	stderr := &gitCloneStderrWriter{
+		progressStream: progressStream,
+	}
+
+	cmd.Stdout = &stdout
+	cmd.Stderr = stderr
+
+	err := cmd.Run()
+
+	if err != nil && ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if progressStream != nil && progressStream.sendErr != nil {
+		return progressStream.sendErr
+	}
+
+	if err != nil {
+		newLineRegExp := regexp.MustCompile(`\n+`)
+
+		return fmt.Errorf(
+			"error while cloning the repository \"%s\".\n\n%s\n\n%s",
+			repo.FullName(),
+			strings.TrimSpace(
+				newLineRegExp.ReplaceAllLiteralString(stderr.String(), " "),
+			),
+			err.Error(),
+		)
+	}
+
+	return nil
+}
+
+func runGitCommand(
+	ctx context.Context,
+	repoDirPath string,
+	args ...string,
+) (string, error) {
+
+	return runGitCommandWithEnv(
+		ctx,
+		repoDirPath,
+		[]string{gitLFSSkipSmudgeEnvVar},
+		args...,
+	)
+}
+
+// runGitCommandWithEnv runs "git" with the passed environment
+// variables appended to the ones of the agent
+func runGitCommandWithEnv(
+	ctx context.Context,
+	repoDirPath string,
+	env []string,
+	args ...string,
+) (string, error) {
+
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = repoDirPath
+	cmd.Env = append(os.Environ(), env...)
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+
+	if err != nil && ctx.Err() != nil {
+		return "", ctx.Err()
+	}
+
+	if err != nil {
+		return "", fmt.Errorf(
+			"error while running \"git %s\" in \"%s\".\n\n%s\n\n%s",
+			strings.Join(args, " "),
+			repoDirPath,
+			strings.TrimSpace(stderr.String()),
+			err.Error(),
+		)
+	}
 
-		lastErrorReturned = nil
-		break
+	return stdout.String(), nil
+}
+
+// gitRepoHasLocalChanges returns true if the repository
+// has uncommitted changes, untracked files, stashes or
+// commits that are not on any remote branch (unpushed
+// commits, local-only branches...).
+func gitRepoHasLocalChanges(
+	ctx context.Context,
+	repoDirPath string,
+) (bool, error) {
+
+	statusOutput, err := runGitCommand(
+		ctx,
+		repoDirPath,
+		"status",
+		"--porcelain",
+	)
+
+	if err != nil {
+		return false, err
 	}
 
-	return lastErrorReturned
+	if len(strings.TrimSpace(statusOutput)) > 0 {
+		return true, nil
+	}
+
+	stashListOutput, err := runGitCommand(
+		ctx,
+		repoDirPath,
+		"stash",
+		"list",
+	)
+
+	if err != nil {
+		return false, err
+	}
+
+	if len(strings.TrimSpace(stashListOutput)) > 0 {
+		return true, nil
+	}
+
+	unpushedCommitsOutput, err := runGitCommand(
+		ctx,
+		repoDirPath,
+		"log",
+		"--branches",
+		"--not",
+		"--remotes",
+		"--oneline",
+	)
+
+	if err != nil {
+		return false, err
+	}
+
+	return len(strings.TrimSpace(unpushedCommitsOutput)) > 0, nil
+}
+
+func fetchGitRepo(
+	ctx context.Context,
+	repoDirPath string,
+) error {
+
+	_, err := runGitCommand(
+		ctx,
+		repoDirPath,
+		"fetch",
+		"--quiet",
+		"origin",
+	)
+
+	return err
+}
+
+// fastForwardGitRepo fast-forwards the current branch to its
+// upstream. An error is returned if the branches have diverged.
+func fastForwardGitRepo(
+	ctx context.Context,
+	repoDirPath string,
+) error {
+
+	_, err := runGitCommand(
+		ctx,
+		repoDirPath,
+		"merge",
+		"--ff-only",
+		"--quiet",
+		"@{upstream}",
+	)
+
+	return err
+}
+
+// checkoutGitRef checks out the passed branch, tag or commit SHA.
+// Refs that are not fetched during clone (eg: "refs/pull/1/head")
+// are fetched and checked out in detached HEAD mode.
+func checkoutGitRef(
+	ctx context.Context,
+	repoDirPath string,
+	gitRef string,
+	depth int32,
+) error {
+
+	// "--" prevents refs from being interpreted as paths
+	_, err := runGitCommand(
+		ctx,
+		repoDirPath,
+		"checkout",
+		"--quiet",
+		gitRef,
+		"--",
+	)
+
+	if err == nil || ctx.Err() != nil {
+		return err
+	}
+
+	fetchArgs := []string{"fetch", "--quiet"}
+
+	if depth > 0 {
+		fetchArgs = append(fetchArgs, "--depth", strconv.Itoa(int(depth)))
+	}
+
+	_, err = runGitCommand(
+		ctx,
+		repoDirPath,
+		append(fetchArgs, "origin", gitRef)...,
+	)
+
+	if err != nil {
+		return fmt.Errorf(
+			"the ref \"%s\" was not found in the repository.\n\n%v",
+			gitRef,
+			err,
+		)
+	}
+
+	_, err = runGitCommand(
+		ctx,
+		repoDirPath,
+		"checkout",
+		"--quiet",
+		"--detach",
+		"FETCH_HEAD",
+	)
+
+	return err
+}
+
+func resolveGitRepoHeadSHA(
+	ctx context.Context,
+	repoDirPath string,
+) (string, error) {
+
+	revParseOutput, err := runGitCommand(
+		ctx,
+		repoDirPath,
+		"rev-parse",
+		"HEAD",
+	)
+
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(revParseOutput), nil
+}
+
+// gitRepoIsOnBranch returns false if
+// the repository is in detached HEAD mode
+func gitRepoIsOnBranch(
+	ctx context.Context,
+	repoDirPath string,
+) bool {
+
+	_, err := runGitCommand(
+		ctx,
+		repoDirPath,
+		"symbolic-ref",
+		"--quiet",
+		"HEAD",
+	)
+
+	return err == nil
 }
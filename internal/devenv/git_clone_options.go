@@ -0,0 +1,146 @@
+package devenv
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/recode-sh/recode/entities"
+)
+
+// See "--filter" in "git help rev-list"
+var gitCloneFilterRegExp = regexp.MustCompile(
+	`^(blob:none|blob:limit=\d+[kmg]?|tree:\d+)$`,
+)
+
+// GitCloneOptions are used to speed up the clone of large repositories
+type GitCloneOptions struct {
+	// Shallow clone when greater than zero
+	Depth int32
+	// Partial clone (eg: "blob:none")
+	Filter string
+	// Cone mode sparse checkout. The config directory
+	// (".recode") is always added to these paths.
+	SparseCheckoutPaths []string
+	SkipSubmodules      bool
+	SkipLFS             bool
+}
+
+func NewGitCloneOptions(
+	depth int32,
+	filter string,
+	sparseCheckoutPaths []string,
+) (GitCloneOptions, error) {
+
+	if depth < 0 {
+		return GitCloneOptions{}, fmt.Errorf(
+			"invalid clone depth \"%d\"",
+			depth,
+		)
+	}
+
+	if len(filter) > 0 && !gitCloneFilterRegExp.MatchString(filter) {
+		return GitCloneOptions{}, fmt.Errorf(
+			"invalid clone filter \"%s\"",
+			filter,
+		)
+	}
+
+	for _, sparseCheckoutPath := range sparseCheckoutPaths {
+		cleanedPath := filepath.Clean(sparseCheckoutPath)
+
+		if len(sparseCheckoutPath) == 0 ||
+			strings.HasPrefix(sparseCheckoutPath, "-") ||
+			filepath.IsAbs(cleanedPath) ||
+			cleanedPath == ".." ||
+			strings.HasPrefix(cleanedPath, "../") {
+
+			return GitCloneOptions{}, fmt.Errorf(
+				"invalid sparse checkout path \"%s\"",
+				sparseCheckoutPath,
+			)
+		}
+	}
+
+	return GitCloneOptions{
+		Depth:               depth,
+		Filter:              filter,
+		SparseCheckoutPaths: sparseCheckoutPaths,
+	}, nil
+}
+
+func (g GitCloneOptions) isSparse() bool {
+	return len(g.SparseCheckoutPaths) > 0
+}
+
+func (g GitCloneOptions) equals(other GitCloneOptions) bool {
+	if g.Depth != other.Depth ||
+		g.Filter != other.Filter ||
+		g.SkipSubmodules != other.SkipSubmodules ||
+		g.SkipLFS != other.SkipLFS ||
+		len(g.SparseCheckoutPaths) != len(other.SparseCheckoutPaths) {
+
+		return false
+	}
+
+	for index, sparseCheckoutPath := range g.SparseCheckoutPaths {
+		if sparseCheckoutPath != other.SparseCheckoutPaths[index] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (g GitCloneOptions) buildCloneArgs() []string {
+	cloneArgs := []string{}
+
+	if g.Depth > 0 {
+		cloneArgs = append(
+			cloneArgs,
+			"--depth",
+			strconv.Itoa(int(g.Depth)),
+		)
+	}
+
+	if len(g.Filter) > 0 {
+		cloneArgs = append(cloneArgs, "--filter="+g.Filter)
+	}
+
+	if g.isSparse() {
+		cloneArgs = append(cloneArgs, "--sparse")
+	}
+
+	return cloneArgs
+}
+
+func (g GitCloneOptions) buildSparseCheckoutArgs() []string {
+	return append(
+		[]string{
+			"sparse-checkout",
+			"set",
+			"--cone",
+			"--",
+			entities.DevEnvRepositoryConfigDirectory,
+		},
+		g.SparseCheckoutPaths...,
+	)
+}
+
+// ReposCloneOptions holds the clone options
+// of the repositories of a development environment.
+type ReposCloneOptions struct {
+	Default GitCloneOptions
+	// Indexed by repository full name ("owner/name")
+	ByRepoFullName map[string]GitCloneOptions
+}
+
+func (r ReposCloneOptions) Lookup(repoFullName string) GitCloneOptions {
+	if repoCloneOptions, ok := r.ByRepoFullName[repoFullName]; ok {
+		return repoCloneOptions
+	}
+
+	return r.Default
+}
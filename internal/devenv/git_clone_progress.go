@@ -0,0 +1,172 @@
+package devenv
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/recode-sh/agent/constants"
+	"github.com/recode-sh/agent/proto"
+)
+
+// Matches the progress lines written by "git clone --progress"
+// (eg: "Receiving objects:  45% (450/1000), 1.20 MiB | 2.00 MiB/s")
+var gitCloneProgressLineRegExp = regexp.MustCompile(
+	`^(?:remote: )?([A-Za-z ]+):\s+(\d+)% \((\d+)/(\d+)\)(?:, ([\d.]+) (bytes|KiB|MiB|GiB))?`,
+)
+
+var gitCloneProgressByteUnits = map[string]float64{
+	"bytes": 1,
+	"KiB":   1024,
+	"MiB":   1024 * 1024,
+	"GiB":   1024 * 1024 * 1024,
+}
+
+func parseGitCloneProgressLine(line string) (*proto.GitCloneProgress, bool) {
+	lineMatches := gitCloneProgressLineRegExp.FindStringSubmatch(
+		strings.TrimSpace(line),
+	)
+
+	if lineMatches == nil {
+		return nil, false
+	}
+
+	percent, _ := strconv.Atoi(lineMatches[2])
+	current, _ := strconv.ParseInt(lineMatches[3], 10, 64)
+	total, _ := strconv.ParseInt(lineMatches[4], 10, 64)
+
+	progress := &proto.GitCloneProgress{
+		Phase:   lineMatches[1],
+		Percent: int32(percent),
+		Current: current,
+		Total:   total,
+	}
+
+	if len(lineMatches[5]) > 0 {
+		receivedSize, _ := strconv.ParseFloat(lineMatches[5], 64)
+		progress.ReceivedBytes = int64(
+			receivedSize * gitCloneProgressByteUnits[lineMatches[6]],
+		)
+	}
+
+	return progress, true
+}
+
+// gitCloneProgressStream sends the progress of a clone (and its
+// retries) through the "BuildAndStartDevEnv" stream. A nil
+// "*gitCloneProgressStream" may be used to discard them.
+type gitCloneProgressStream struct {
+	stream     proto.Agent_BuildAndStartDevEnvServer
+	stepID     string
+	repository string
+
+	lastPhase  string
+	lastSentAt time.Time
+	sendErr    error
+}
+
+func newGitCloneProgressStream(
+	stream proto.Agent_BuildAndStartDevEnvServer,
+	stepID string,
+	repository string,
+) *gitCloneProgressStream {
+
+	return &gitCloneProgressStream{
+		stream:     stream,
+		stepID:     stepID,
+		repository: repository,
+	}
+}
+
+func (g *gitCloneProgressStream) sendProgress(progress *proto.GitCloneProgress) {
+	if g == nil || g.sendErr != nil {
+		return
+	}
+
+	// Throttled to avoid flooding the stream
+	if progress.Phase == g.lastPhase &&
+		progress.Percent < 100 &&
+		time.Since(g.lastSentAt) < constants.GRPCServerGitCloneProgressInterval {
+
+		return
+	}
+
+	progress.StepId = g.stepID
+	progress.Repository = g.repository
+
+	g.lastPhase = progress.Phase
+	g.lastSentAt = time.Now()
+
+	g.sendErr = g.stream.Send(&proto.BuildAndStartDevEnvReply{
+		GitCloneProgress: progress,
+	})
+}
+
+func (g *gitCloneProgressStream) sendRetry(
+	attempt int,
+	maxAttempts int,
+	lastErr error,
+) error {
+
+	if g == nil {
+		return nil
+	}
+
+	// Progress restarts from scratch
+	g.lastPhase = ""
+
+	return g.stream.Send(&proto.BuildAndStartDevEnvReply{
+		GitCloneRetry: &proto.GitCloneRetry{
+			StepId:       g.stepID,
+			Repository:   g.repository,
+			Attempt:      int32(attempt),
+			MaxAttempts:  int32(maxAttempts),
+			ErrorMessage: lastErr.Error(),
+		},
+	})
+}
+
+// gitCloneStderrWriter parses the progress lines written
+// on stderr by "git clone" (terminated by "\r" or "\n").
+// The other lines are kept to build error messages.
+type gitCloneStderrWriter struct {
+	progressStream *gitCloneProgressStream
+	currentLine    bytes.Buffer
+	output         bytes.Buffer
+}
+
+func (g *gitCloneStderrWriter) Write(p []byte) (int, error) {
+	for _, b := range p {
+		if b != '\r' && b != '\n' {
+			g.currentLine.WriteByte(b)
+			continue
+		}
+
+		g.flushCurrentLine()
+	}
+
+	return len(p), nil
+}
+
+func (g *gitCloneStderrWriter) flushCurrentLine() {
+	line := g.currentLine.String()
+	g.currentLine.Reset()
+
+	if len(strings.TrimSpace(line)) == 0 {
+		return
+	}
+
+	if progress, ok := parseGitCloneProgressLine(line); ok {
+		g.progressStream.sendProgress(progress)
+		return
+	}
+
+	g.output.WriteString(line + "\n")
+}
+
+func (g *gitCloneStderrWriter) String() string {
+	g.flushCurrentLine()
+	return g.output.String()
+}
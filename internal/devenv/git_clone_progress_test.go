@@ -0,0 +1,98 @@
+package devenv
+
+import "testing"
+
+func TestParseGitCloneProgressLine(t *testing.T) {
+	testCases := []struct {
+		test                  string
+		line                  string
+		expectedPhase         string
+		expectedPercent       int32
+		expectedCurrent       int64
+		expectedTotal         int64
+		expectedReceivedBytes int64
+		expectProgress        bool
+	}{
+		{
+			test:            "counting_objects",
+			line:            "remote: Counting objects:  12% (12/100)",
+			expectedPhase:   "Counting objects",
+			expectedPercent: 12,
+			expectedCurrent: 12,
+			expectedTotal:   100,
+			expectProgress:  true,
+		},
+
+		{
+			test:                  "receiving_objects",
+			line:                  "Receiving objects:  45% (450/1000), 1.50 MiB | 2.00 MiB/s",
+			expectedPhase:         "Receiving objects",
+			expectedPercent:       45,
+			expectedCurrent:       450,
+			expectedTotal:         1000,
+			expectedReceivedBytes: 1572864,
+			expectProgress:        true,
+		},
+
+		{
+			test:            "resolving_deltas_done",
+			line:            "Resolving deltas: 100% (30/30), done.",
+			expectedPhase:   "Resolving deltas",
+			expectedPercent: 100,
+			expectedCurrent: 30,
+			expectedTotal:   30,
+			expectProgress:  true,
+		},
+
+		{
+			test:           "not_progress",
+			line:           "fatal: repository 'acme/api' not found",
+			expectProgress: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.test, func(t *testing.T) {
+			progress, ok := parseGitCloneProgressLine(tc.line)
+
+			if ok != tc.expectProgress {
+				t.Fatalf("expected progress to be '%t', got '%t'", tc.expectProgress, ok)
+			}
+
+			if !tc.expectProgress {
+				return
+			}
+
+			if progress.Phase != tc.expectedPhase {
+				t.Fatalf(
+					"expected phase to equal '%s', got '%s'",
+					tc.expectedPhase,
+					progress.Phase,
+				)
+			}
+
+			if progress.Percent != tc.expectedPercent ||
+				progress.Current != tc.expectedCurrent ||
+				progress.Total != tc.expectedTotal {
+
+				t.Fatalf(
+					"expected '%d%% (%d/%d)', got '%d%% (%d/%d)'",
+					tc.expectedPercent,
+					tc.expectedCurrent,
+					tc.expectedTotal,
+					progress.Percent,
+					progress.Current,
+					progress.Total,
+				)
+			}
+
+			if progress.ReceivedBytes != tc.expectedReceivedBytes {
+				t.Fatalf(
+					"expected received bytes to equal '%d', got '%d'",
+					tc.expectedReceivedBytes,
+					progress.ReceivedBytes,
+				)
+			}
+		})
+	}
+}
@@ -0,0 +1,640 @@
+package devenv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/recode-sh/agent/constants"
+	"github.com/recode-sh/recode/github"
+)
+
+// GitProvider represents a git hosting provider
+// (eg: GitHub, GitLab, a self-hosted server or local repositories).
+type GitProvider interface {
+	// Name identifies the provider in requests (eg: "github")
+	Name() string
+	// ParseRepositoryName parses names like "owner/name" or
+	// "name" (in this case "defaultRepoOwner" is used).
+	ParseRepositoryName(repoName, defaultRepoOwner string) (*GitRepository, error)
+	BuildCloneURL(repoOwner, repoName string) string
+	// SSHHost returns an empty string if the
+	// provider doesn't use SSH (eg: "file://" remotes)
+	SSHHost() string
+	SSHHostConfig() string
+	KnownHostsEntries(ctx context.Context) ([]string, error)
+}
+
+type GitRepository struct {
+	Provider GitProvider
+	Owner    string
+	Name     string
+	// Branch, tag or commit SHA checked out after
+	// clone (the default branch is used when empty)
+	Ref          string
+	CloneOptions GitCloneOptions
+	RetryPolicy  RetryPolicy
+	// Path relative to the workspace (the
+	// workspace layout is used when empty)
+	WorkspacePath string
+}
+
+func NewGitRepository(
+	provider GitProvider,
+	repoOwner string,
+	repoName string,
+) *GitRepository {
+
+	return &GitRepository{
+		Provider:    provider,
+		Owner:       repoOwner,
+		Name:        repoName,
+		RetryPolicy: DefaultRetryPolicy(),
+	}
+}
+
+// FullName returns the repository name as "owner/name"
+func (g *GitRepository) FullName() string {
+	return g.Owner + "/" + g.Name
+}
+
+// FullNameWithRef returns the repository name as "owner/name@ref"
+// (or "owner/name" if the repository is not pinned)
+func (g *GitRepository) FullNameWithRef() string {
+	if len(g.Ref) == 0 {
+		return g.FullName()
+	}
+
+	return g.FullName() + "@" + g.Ref
+}
+
+func (g *GitRepository) CloneURL() string {
+	return g.Provider.BuildCloneURL(g.Owner, g.Name)
+}
+
+const (
+	GitProviderNameGitHub = "github"
+	GitProviderNameGitLab = "gitlab"
+)
+
+// NewGitProvider returns the provider that corresponds to the passed spec:
+// "github" (or an empty string), "gitlab", "ssh://user@host[:port]",
+// "https://host[/path]" or "file:///base/dir/path".
+func NewGitProvider(providerSpec string) (GitProvider, error) {
+	if len(providerSpec) == 0 || providerSpec == GitProviderNameGitHub {
+		return gitHubProvider{}, nil
+	}
+
+	if providerSpec == GitProviderNameGitLab {
+		return newSSHGitProvider(GitProviderNameGitLab, "git", "gitlab.com", ""), nil
+	}
+
+	providerURL, err := url.Parse(providerSpec)
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid git provider \"%s\": %v", providerSpec, err)
+	}
+
+	switch providerURL.Scheme {
+	case "ssh":
+		return newSSHGitProvider(
+			providerSpec,
+			providerURL.User.Username(),
+			providerURL.Hostname(),
+			providerURL.Port(),
+		), nil
+	case "https", "http":
+		return httpsGitProvider{
+			baseURL: strings.TrimSuffix(providerSpec, "/"),
+		}, nil
+	case "file":
+		return fileGitProvider{
+			baseDirPath: filepath.Clean(providerURL.Path),
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unknown git provider \"%s\"", providerSpec)
+}
+
+// Matches SCP-like git URLs (eg: "git@gitlab.com:owner/name.git")
+var scpLikeGitURLRegExp = regexp.MustCompile(`^([\w.-]+)@([\w.-]+):(.+)$`)
+
+// ValidateGitRef returns an error if the passed
+// ref could be interpreted as a "git" option
+func ValidateGitRef(gitRef string) error {
+	if strings.HasPrefix(gitRef, "-") ||
+		strings.ContainsAny(gitRef, " \t\n~^:?*[\\") {
+
+		return fmt.Errorf("invalid git ref \"%s\"", gitRef)
+	}
+
+	return nil
+}
+
+// splitGitRef splits repositories passed as "<repository>@<ref>".
+// The "@" of the user info in URLs (eg: "ssh://git@host/...")
+// is ignored given that only the path may contain the ref.
+func splitGitRef(repoRef string) (string, string) {
+	pathStartIndex := 0
+
+	if schemeIndex := strings.Index(repoRef, "://"); schemeIndex != -1 {
+		pathStartIndex = schemeIndex + len("://")
+		pathIndex := strings.Index(repoRef[pathStartIndex:], "/")
+
+		if pathIndex == -1 {
+			return repoRef, ""
+		}
+
+		pathStartIndex += pathIndex
+	} else if scpLikeGitURLRegExp.MatchString(repoRef) {
+		pathStartIndex = strings.Index(repoRef, ":")
+	}
+
+	refIndex := strings.Index(repoRef[pathStartIndex:], "@")
+
+	if refIndex == -1 {
+		return repoRef, ""
+	}
+
+	refIndex += pathStartIndex
+
+	return repoRef[:refIndex], repoRef[refIndex+1:]
+}
+
+// ResolveGitRepository resolves the repositories passed in the
+// "dev_env.Dockerfile" labels. Repositories may be passed as git URLs
+// ("https://", "ssh://", SCP-like or "file://") or as names ("owner/name"
+// or "name") that are resolved using the default provider and owner.
+// In both cases, a ref may be passed using the "@ref" suffix
+// and a path in the workspace using the "=path" suffix.
+func ResolveGitRepository(
+	repoRef string,
+	defaultProvider GitProvider,
+	defaultRepoOwner string,
+) (*GitRepository, error) {
+
+	repoRef, workspacePath := splitWorkspacePath(repoRef)
+
+	if len(workspacePath) > 0 {
+		if err := validateWorkspacePath(workspacePath); err != nil {
+			return nil, err
+		}
+	}
+
+	repoRef, gitRef := splitGitRef(repoRef)
+
+	if len(gitRef) > 0 {
+		if err := ValidateGitRef(gitRef); err != nil {
+			return nil, err
+		}
+	}
+
+	repo, err := resolveGitRepository(repoRef, defaultProvider, defaultRepoOwner)
+
+	if err != nil {
+		return nil, err
+	}
+
+	repo.Ref = gitRef
+	repo.WorkspacePath = workspacePath
+
+	return repo, nil
+}
+
+func resolveGitRepository(
+	repoRef string,
+	defaultProvider GitProvider,
+	defaultRepoOwner string,
+) (*GitRepository, error) {
+
+	if scpLikeURLMatches := scpLikeGitURLRegExp.FindStringSubmatch(repoRef); scpLikeURLMatches != nil {
+		repoRef = fmt.Sprintf(
+			"ssh://%s@%s/%s",
+			scpLikeURLMatches[1],
+			scpLikeURLMatches[2],
+			scpLikeURLMatches[3],
+		)
+	}
+
+	if !strings.Contains(repoRef, "://") {
+		return defaultProvider.ParseRepositoryName(repoRef, defaultRepoOwner)
+	}
+
+	repoURL, err := url.Parse(repoRef)
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid repository \"%s\": %v", repoRef, err)
+	}
+
+	repoPath := strings.Trim(repoURL.Path, "/")
+
+	// Local repositories are located in "<base dir>/<owner>/<name>"
+	if repoURL.Scheme == "file" {
+		repoDirPath := filepath.Clean(repoURL.Path)
+		repoOwnerDirPath := filepath.Dir(repoDirPath)
+
+		return fileGitProvider{
+			baseDirPath: filepath.Dir(repoOwnerDirPath),
+		}.ParseRepositoryName(
+			filepath.Base(repoOwnerDirPath)+"/"+filepath.Base(repoDirPath),
+			"",
+		)
+	}
+
+	var provider GitProvider
+
+	switch {
+	case repoURL.Hostname() == "github.com":
+		provider = gitHubProvider{}
+	case repoURL.Hostname() == "gitlab.com" && repoURL.Scheme == "ssh":
+		provider = newSSHGitProvider(GitProviderNameGitLab, "git", "gitlab.com", "")
+	case repoURL.Scheme == "ssh":
+		provider = newSSHGitProvider(
+			fmt.Sprintf("ssh://%s@%s", repoURL.User.Username(), repoURL.Host),
+			repoURL.User.Username(),
+			repoURL.Hostname(),
+			repoURL.Port(),
+		)
+	case repoURL.Scheme == "https" || repoURL.Scheme == "http":
+		provider = httpsGitProvider{
+			baseURL: repoURL.Scheme + "://" + repoURL.Host,
+		}
+	default:
+		return nil, fmt.Errorf("unsupported repository URL \"%s\"", repoRef)
+	}
+
+	return provider.ParseRepositoryName(repoPath, "")
+}
+
+// parseGitRepositoryName parses names like "owner/name", "name" or
+// "group/subgroup/name" (GitLab subgroups are part of the owner).
+func parseGitRepositoryName(
+	provider GitProvider,
+	repoName string,
+	defaultRepoOwner string,
+) (*GitRepository, error) {
+
+	repoNameParts := strings.Split(
+		strings.TrimSuffix(strings.Trim(repoName, "/"), ".git"),
+		"/",
+	)
+
+	for _, repoNamePart := range repoNameParts {
+		if len(repoNamePart) == 0 || repoNamePart == "." || repoNamePart == ".." {
+			return nil, fmt.Errorf("invalid repository name \"%s\"", repoName)
+		}
+	}
+
+	if len(repoNameParts) == 1 {
+		if len(defaultRepoOwner) == 0 {
+			return nil, fmt.Errorf("invalid repository name \"%s\" (missing owner)", repoName)
+		}
+
+		return NewGitRepository(provider, defaultRepoOwner, repoNameParts[0]), nil
+	}
+
+	return NewGitRepository(
+		provider,
+		strings.Join(repoNameParts[:len(repoNameParts)-1], "/"),
+		repoNameParts[len(repoNameParts)-1],
+	), nil
+}
+
+type gitHubProvider struct{}
+
+func (gitHubProvider) Name() string {
+	return GitProviderNameGitHub
+}
+
+func (g gitHubProvider) ParseRepositoryName(
+	repoName string,
+	defaultRepoOwner string,
+) (*GitRepository, error) {
+
+	parsedRepo, err := github.ParseRepositoryName(repoName, defaultRepoOwner)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return NewGitRepository(g, parsedRepo.Owner, parsedRepo.Name), nil
+}
+
+func (gitHubProvider) BuildCloneURL(repoOwner, repoName string) string {
+	return string(github.BuildGitURL(repoOwner, repoName))
+}
+
+func (gitHubProvider) SSHHost() string {
+	return "github.com"
+}
+
+// The SSH config and the known hosts for
+// GitHub are set during instance init
+func (gitHubProvider) SSHHostConfig() string {
+	return buildGitSSHHostConfig("github.com", "git", "")
+}
+
+func (gitHubProvider) KnownHostsEntries(ctx context.Context) ([]string, error) {
+	return scanSSHKnownHostsEntries(ctx, "github.com", "")
+}
+
+type sshGitProvider struct {
+	name string
+	user string
+	host string
+	port string
+}
+
+func newSSHGitProvider(name, user, host, port string) sshGitProvider {
+	if len(user) == 0 {
+		user = "git"
+	}
+
+	return sshGitProvider{
+		name: name,
+		user: user,
+		host: host,
+		port: port,
+	}
+}
+
+func (s sshGitProvider) Name() string {
+	return s.name
+}
+
+func (s sshGitProvider) ParseRepositoryName(
+	repoName string,
+	defaultRepoOwner string,
+) (*GitRepository, error) {
+
+	return parseGitRepositoryName(s, repoName, defaultRepoOwner)
+}
+
+func (s sshGitProvider) BuildCloneURL(repoOwner, repoName string) string {
+	if len(s.port) == 0 {
+		return fmt.Sprintf("%s@%s:%s/%s.git", s.user, s.host, repoOwner, repoName)
+	}
+
+	return fmt.Sprintf(
+		"ssh://%s@%s:%s/%s/%s.git",
+		s.user,
+		s.host,
+		s.port,
+		repoOwner,
+		repoName,
+	)
+}
+
+func (s sshGitProvider) SSHHost() string {
+	return s.host
+}
+
+func (s sshGitProvider) SSHHostConfig() string {
+	return buildGitSSHHostConfig(s.host, s.user, s.port)
+}
+
+func (s sshGitProvider) KnownHostsEntries(ctx context.Context) ([]string, error) {
+	return scanSSHKnownHostsEntries(ctx, s.host, s.port)
+}
+
+// httpsGitProvider is used for public repositories
+// (or with credentials set in the git config)
+type httpsGitProvider struct {
+	baseURL string
+}
+
+func (h httpsGitProvider) Name() string {
+	return h.baseURL
+}
+
+func (h httpsGitProvider) ParseRepositoryName(
+	repoName string,
+	defaultRepoOwner string,
+) (*GitRepository, error) {
+
+	return parseGitRepositoryName(h, repoName, defaultRepoOwner)
+}
+
+func (h httpsGitProvider) BuildCloneURL(repoOwner, repoName string) string {
+	return fmt.Sprintf("%s/%s/%s.git", h.baseURL, repoOwner, repoName)
+}
+
+func (httpsGitProvider) SSHHost() string {
+	return ""
+}
+
+func (httpsGitProvider) SSHHostConfig() string {
+	return ""
+}
+
+func (httpsGitProvider) KnownHostsEntries(ctx context.Context) ([]string, error) {
+	return nil, nil
+}
+
+// fileGitProvider clones repositories located
+// in "<base dir>/<owner>/<name>" on the instance
+type fileGitProvider struct {
+	baseDirPath string
+}
+
+func (f fileGitProvider) Name() string {
+	return "file://" + f.baseDirPath
+}
+
+func (f fileGitProvider) ParseRepositoryName(
+	repoName string,
+	defaultRepoOwner string,
+) (*GitRepository, error) {
+
+	return parseGitRepositoryName(f, repoName, defaultRepoOwner)
+}
+
+func (f fileGitProvider) BuildCloneURL(repoOwner, repoName string) string {
+	return "file://" + filepath.Join(f.baseDirPath, repoOwner, repoName)
+}
+
+func (fileGitProvider) SSHHost() string {
+	return ""
+}
+
+func (fileGitProvider) SSHHostConfig() string {
+	return ""
+}
+
+func (fileGitProvider) KnownHostsEntries(ctx context.Context) ([]string, error) {
+	return nil, nil
+}
+
+// The key pair generated during instance
+// init is used for all the SSH providers
+func buildGitSSHHostConfig(host, user, port string) string {
+	hostConfig := fmt.Sprintf(
+		"Host %s\n  User %s\n  Hostname %s\n  PreferredAuthentications publickey\n  IdentityFile ~/.ssh/recode_github\n",
+		host,
+		user,
+		host,
+	)
+
+	if len(port) > 0 {
+		hostConfig += fmt.Sprintf("  Port %s\n", port)
+	}
+
+	return hostConfig
+}
+
+func scanSSHKnownHostsEntries(
+	ctx context.Context,
+	host string,
+	port string,
+) ([]string, error) {
+
+	args := []string{"-t", "rsa,ecdsa,ed25519"}
+
+	if len(port) > 0 {
+		args = append(args, "-p", port)
+	}
+
+	keyscanOutput, err := exec.CommandContext(
+		ctx,
+		"ssh-keyscan",
+		append(args, host)...,
+	).Output()
+
+	if err != nil {
+		return nil, fmt.Errorf(
+			"error while scanning the SSH keys of \"%s\": %v",
+			host,
+			err,
+		)
+	}
+
+	knownHostsEntries := []string{}
+
+	for _, keyscanLine := range strings.Split(string(keyscanOutput), "\n") {
+		if len(keyscanLine) == 0 || strings.HasPrefix(keyscanLine, "#") {
+			continue
+		}
+
+		knownHostsEntries = append(knownHostsEntries, keyscanLine)
+	}
+
+	return knownHostsEntries, nil
+}
+
+// Development environments may be built concurrently
+var gitProvidersSSHConfigMutex sync.Mutex
+
+// ensureGitProviderSSHConfigured adds the SSH host config and the
+// known hosts entries of the passed provider if they are missing.
+func ensureGitProviderSSHConfigured(
+	ctx context.Context,
+	provider GitProvider,
+) error {
+
+	sshHost := provider.SSHHost()
+
+	if len(sshHost) == 0 {
+		return nil
+	}
+
+	gitProvidersSSHConfigMutex.Lock()
+	defer gitProvidersSSHConfigMutex.Unlock()
+
+	sshConfigContent, err := readFileIfExists(constants.DevEnvSSHConfigFilePath)
+
+	if err != nil {
+		return err
+	}
+
+	sshHostConfigRegExp := regexp.MustCompile(
+		`(?m)^Host\s+` + regexp.QuoteMeta(sshHost) + `\s*$`,
+	)
+
+	if !sshHostConfigRegExp.MatchString(sshConfigContent) {
+		err = appendToFile(
+			constants.DevEnvSSHConfigFilePath,
+			provider.SSHHostConfig(),
+			os.FileMode(0600),
+		)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	knownHostsContent, err := readFileIfExists(constants.DevEnvSSHKnownHostsFilePath)
+
+	if err != nil {
+		return err
+	}
+
+	knownHostRegExp := regexp.MustCompile(
+		`(?m)^(\[)?` + regexp.QuoteMeta(sshHost) + `[\]:,\s]`,
+	)
+
+	if knownHostRegExp.MatchString(knownHostsContent) {
+		return nil
+	}
+
+	knownHostsEntries, err := provider.KnownHostsEntries(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	if len(knownHostsEntries) == 0 {
+		return nil
+	}
+
+	return appendToFile(
+		constants.DevEnvSSHKnownHostsFilePath,
+		strings.Join(knownHostsEntries, "\n")+"\n",
+		os.FileMode(0644),
+	)
+}
+
+func readFileIfExists(filePath string) (string, error) {
+	fileContent, err := os.ReadFile(filePath)
+
+	if err != nil && errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return string(fileContent), nil
+}
+
+func appendToFile(
+	filePath string,
+	content string,
+	fileMode os.FileMode,
+) error {
+
+	file, err := os.OpenFile(
+		filePath,
+		os.O_APPEND|os.O_CREATE|os.O_WRONLY,
+		fileMode,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	// Make sure that the appended content
+	// starts on a new line
+	_, err = file.WriteString("\n" + content)
+
+	return err
+}
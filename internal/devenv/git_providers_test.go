@@ -0,0 +1,286 @@
+package devenv
+
+import "testing"
+
+func TestResolveGitRepository(t *testing.T) {
+	testCases := []struct {
+		test             string
+		repoRef          string
+		expectedOwner    string
+		expectedName     string
+		expectedCloneURL string
+		expectedRef      string
+		expectedPath     string
+		expectError      bool
+	}{
+		{
+			test:             "name_only",
+			repoRef:          "api",
+			expectedOwner:    "acme",
+			expectedName:     "api",
+			expectedCloneURL: "git@github.com:acme/api.git",
+			expectError:      false,
+		},
+
+		{
+			test:             "owner_and_name",
+			repoRef:          "other/api",
+			expectedOwner:    "other",
+			expectedName:     "api",
+			expectedCloneURL: "git@github.com:other/api.git",
+			expectError:      false,
+		},
+
+		{
+			test:             "scp_like_gitlab_url",
+			repoRef:          "git@gitlab.com:group/subgroup/api.git",
+			expectedOwner:    "group/subgroup",
+			expectedName:     "api",
+			expectedCloneURL: "git@gitlab.com:group/subgroup/api.git",
+			expectError:      false,
+		},
+
+		{
+			test:             "ssh_url_with_port",
+			repoRef:          "ssh://gitea@git.acme.dev:2222/acme/api.git",
+			expectedOwner:    "acme",
+			expectedName:     "api",
+			expectedCloneURL: "ssh://gitea@git.acme.dev:2222/acme/api.git",
+			expectError:      false,
+		},
+
+		{
+			test:             "https_url",
+			repoRef:          "https://bitbucket.org/acme/api.git",
+			expectedOwner:    "acme",
+			expectedName:     "api",
+			expectedCloneURL: "https://bitbucket.org/acme/api.git",
+			expectError:      false,
+		},
+
+		{
+			test:             "file_url",
+			repoRef:          "file:///srv/git/acme/api",
+			expectedOwner:    "acme",
+			expectedName:     "api",
+			expectedCloneURL: "file:///srv/git/acme/api",
+			expectError:      false,
+		},
+
+		{
+			test:             "name_with_ref",
+			repoRef:          "other/api@feature/login",
+			expectedOwner:    "other",
+			expectedName:     "api",
+			expectedCloneURL: "git@github.com:other/api.git",
+			expectedRef:      "feature/login",
+			expectError:      false,
+		},
+
+		{
+			test:             "scp_like_url_with_ref",
+			repoRef:          "git@gitlab.com:acme/api.git@v1.2.0",
+			expectedOwner:    "acme",
+			expectedName:     "api",
+			expectedCloneURL: "git@gitlab.com:acme/api.git",
+			expectedRef:      "v1.2.0",
+			expectError:      false,
+		},
+
+		{
+			test:             "ssh_url_with_ref",
+			repoRef:          "ssh://git@git.acme.dev/acme/api@0a1b2c3",
+			expectedOwner:    "acme",
+			expectedName:     "api",
+			expectedCloneURL: "git@git.acme.dev:acme/api.git",
+			expectedRef:      "0a1b2c3",
+			expectError:      false,
+		},
+
+		{
+			test:             "name_with_ref_and_path",
+			repoRef:          "other/api@main=services/other-api",
+			expectedOwner:    "other",
+			expectedName:     "api",
+			expectedCloneURL: "git@github.com:other/api.git",
+			expectedRef:      "main",
+			expectedPath:     "services/other-api",
+			expectError:      false,
+		},
+
+		{
+			test:        "path_outside_workspace",
+			repoRef:     "other/api=../api",
+			expectError: true,
+		},
+
+		{
+			test:        "ref_as_option",
+			repoRef:     "acme/api@--upload-pack=touch",
+			expectError: true,
+		},
+
+		{
+			test:        "path_traversal",
+			repoRef:     "https://git.acme.dev/acme/../api",
+			expectError: true,
+		},
+
+		{
+			test:        "unsupported_scheme",
+			repoRef:     "ftp://git.acme.dev/acme/api",
+			expectError: true,
+		},
+	}
+
+	defaultProvider, err := NewGitProvider("")
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.test, func(t *testing.T) {
+			repo, err := ResolveGitRepository(tc.repoRef, defaultProvider, "acme")
+
+			if tc.expectError {
+				if err == nil {
+					t.Fatalf("expected error, got nothing")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error, got '%+v'", err)
+			}
+
+			if repo.Owner != tc.expectedOwner {
+				t.Fatalf(
+					"expected owner to equal '%s', got '%s'",
+					tc.expectedOwner,
+					repo.Owner,
+				)
+			}
+
+			if repo.Name != tc.expectedName {
+				t.Fatalf(
+					"expected name to equal '%s', got '%s'",
+					tc.expectedName,
+					repo.Name,
+				)
+			}
+
+			if repo.CloneURL() != tc.expectedCloneURL {
+				t.Fatalf(
+					"expected clone URL to equal '%s', got '%s'",
+					tc.expectedCloneURL,
+					repo.CloneURL(),
+				)
+			}
+
+			if repo.Ref != tc.expectedRef {
+				t.Fatalf(
+					"expected ref to equal '%s', got '%s'",
+					tc.expectedRef,
+					repo.Ref,
+				)
+			}
+
+			if repo.WorkspacePath != tc.expectedPath {
+				t.Fatalf(
+					"expected workspace path to equal '%s', got '%s'",
+					tc.expectedPath,
+					repo.WorkspacePath,
+				)
+			}
+		})
+	}
+}
+
+func TestNewGitProvider(t *testing.T) {
+	testCases := []struct {
+		test             string
+		providerSpec     string
+		expectedCloneURL string
+		expectedSSHHost  string
+		expectError      bool
+	}{
+		{
+			test:             "default",
+			providerSpec:     "",
+			expectedCloneURL: "git@github.com:acme/api.git",
+			expectedSSHHost:  "github.com",
+		},
+
+		{
+			test:             "gitlab",
+			providerSpec:     GitProviderNameGitLab,
+			expectedCloneURL: "git@gitlab.com:acme/api.git",
+			expectedSSHHost:  "gitlab.com",
+		},
+
+		{
+			test:             "ssh",
+			providerSpec:     "ssh://git@git.acme.dev",
+			expectedCloneURL: "git@git.acme.dev:acme/api.git",
+			expectedSSHHost:  "git.acme.dev",
+		},
+
+		{
+			test:             "https",
+			providerSpec:     "https://git.acme.dev/",
+			expectedCloneURL: "https://git.acme.dev/acme/api.git",
+			expectedSSHHost:  "",
+		},
+
+		{
+			test:             "file",
+			providerSpec:     "file:///srv/git",
+			expectedCloneURL: "file:///srv/git/acme/api",
+			expectedSSHHost:  "",
+		},
+
+		{
+			test:         "unknown",
+			providerSpec: "bitbucket",
+			expectError:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.test, func(t *testing.T) {
+			provider, err := NewGitProvider(tc.providerSpec)
+
+			if tc.expectError {
+				if err == nil {
+					t.Fatalf("expected error, got nothing")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error, got '%+v'", err)
+			}
+
+			cloneURL := provider.BuildCloneURL("acme", "api")
+
+			if cloneURL != tc.expectedCloneURL {
+				t.Fatalf(
+					"expected clone URL to equal '%s', got '%s'",
+					tc.expectedCloneURL,
+					cloneURL,
+				)
+			}
+
+			if provider.SSHHost() != tc.expectedSSHHost {
+				t.Fatalf(
+					"expected SSH host to equal '%s', got '%s'",
+					tc.expectedSSHHost,
+					provider.SSHHost(),
+				)
+			}
+		})
+	}
+}
@@ -0,0 +1,272 @@
+package devenv
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/recode-sh/agent/internal/system"
+	"github.com/recode-sh/agent/proto"
+)
+
+// LFS objects are not downloaded during clones and checkouts.
+// They are pulled afterward in a dedicated step.
+const gitLFSSkipSmudgeEnvVar = "GIT_LFS_SKIP_SMUDGE=1"
+
+// populateRepoSubmodulesAndLFSObjects initializes the submodules
+// of the passed repository recursively and pulls its LFS objects
+// (unless skipped in the clone options of the repository).
+func populateRepoSubmodulesAndLFSObjects(
+	ctx context.Context,
+	stream proto.Agent_BuildAndStartDevEnvServer,
+	repo *GitRepository,
+	repoDirPath string,
+) error {
+
+	if !repo.CloneOptions.SkipSubmodules {
+		err := initRepoSubmodules(ctx, stream, repo, repoDirPath)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if repo.CloneOptions.SkipLFS {
+		return nil
+	}
+
+	repoUsesLFS, err := gitRepoUsesLFS(repoDirPath)
+
+	if err != nil || !repoUsesLFS {
+		return err
+	}
+
+	return runBuildStep(
+		stream,
+		buildStep{
+			ID:   buildStepIDPullLFSObjects(repo.Owner, repo.Name),
+			Type: proto.StepType_STEP_TYPE_PULL_LFS_OBJECTS,
+			Description: fmt.Sprintf(
+				"Pulling LFS objects of %s",
+				repo.FullName(),
+			),
+			Repository: repo.FullName(),
+		},
+		func() error {
+			return pullGitLFSObjects(ctx, repo, repoDirPath)
+		},
+	)
+}
+
+func initRepoSubmodules(
+	ctx context.Context,
+	stream proto.Agent_BuildAndStartDevEnvServer,
+	repo *GitRepository,
+	repoDirPath string,
+) error {
+
+	submodulePaths, err := lookupGitSubmodulePaths(ctx, repoDirPath)
+
+	if err != nil {
+		return err
+	}
+
+	filesManager := system.NewFileManager()
+
+	for _, submodulePath := range submodulePaths {
+		// Submodules located outside of the
+		// sparse checkout paths are not initialized
+		submoduleParentDirExists, err := filesManager.DoesFileExist(
+			filepath.Dir(filepath.Join(repoDirPath, submodulePath)),
+		)
+
+		if err != nil {
+			return err
+		}
+
+		if !submoduleParentDirExists {
+			continue
+		}
+
+		err = runBuildStep(
+			stream,
+			buildStep{
+				ID: buildStepIDInitSubmodule(
+					repo.Owner,
+					repo.Name,
+					submodulePath,
+				),
+				Type: proto.StepType_STEP_TYPE_INIT_SUBMODULE,
+				Description: fmt.Sprintf(
+					"Initializing submodule %s of %s",
+					submodulePath,
+					repo.FullName(),
+				),
+				Repository: repo.FullName(),
+			},
+			func() error {
+				return initGitSubmodule(ctx, repo, repoDirPath, submodulePath)
+			},
+		)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// lookupGitSubmodulePaths returns the paths of the
+// submodules declared in the ".gitmodules" file (if any)
+func lookupGitSubmodulePaths(
+	ctx context.Context,
+	repoDirPath string,
+) ([]string, error) {
+
+	gitModulesFilePath := filepath.Join(repoDirPath, ".gitmodules")
+
+	filesManager := system.NewFileManager()
+
+	gitModulesFileExists, err := filesManager.DoesFileExist(gitModulesFilePath)
+
+	if err != nil || !gitModulesFileExists {
+		return []string{}, err
+	}
+
+	// "--null" separates keys from values with a new line
+	// and entries with a NUL byte (paths may contain spaces)
+	gitConfigOutput, err := runGitCommand(
+		ctx,
+		repoDirPath,
+		"config",
+		"--file",
+		gitModulesFilePath,
+		"--null",
+		"--get-regexp",
+		`^submodule\..*\.path$`,
+	)
+
+	// "git config" exits with 1 when nothing matches
+	if err != nil && ctx.Err() == nil {
+		return []string{}, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	submodulePaths := []string{}
+
+	for _, gitConfigEntry := range strings.Split(gitConfigOutput, "\x00") {
+		gitConfigEntryParts := strings.SplitN(gitConfigEntry, "\n", 2)
+
+		if len(gitConfigEntryParts) != 2 || len(gitConfigEntryParts[1]) == 0 {
+			continue
+		}
+
+		submodulePaths = append(submodulePaths, gitConfigEntryParts[1])
+	}
+
+	return submodulePaths, nil
+}
+
+func initGitSubmodule(
+	ctx context.Context,
+	repo *GitRepository,
+	repoDirPath string,
+	submodulePath string,
+) error {
+
+	_, err := runGitCommand(
+		ctx,
+		repoDirPath,
+		"submodule",
+		"--quiet",
+		"update",
+		"--init",
+		"--recursive",
+		"--",
+		submodulePath,
+	)
+
+	if err != nil && ctx.Err() == nil {
+		return fmt.Errorf(
+			"error while initializing the submodule \"%s\" of \"%s\".\n\n%v",
+			submodulePath,
+			repo.FullName(),
+			err,
+		)
+	}
+
+	return err
+}
+
+// gitRepoUsesLFS looks for LFS filters in the
+// ".gitattributes" file at the root of the repository
+func gitRepoUsesLFS(repoDirPath string) (bool, error) {
+	gitAttributesContent, err := os.ReadFile(
+		filepath.Join(repoDirPath, ".gitattributes"),
+	)
+
+	if err != nil && os.IsNotExist(err) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	return strings.Contains(string(gitAttributesContent), "filter=lfs"), nil
+}
+
+func pullGitLFSObjects(
+	ctx context.Context,
+	repo *GitRepository,
+	repoDirPath string,
+) error {
+
+	if _, err := exec.LookPath("git-lfs"); err != nil {
+		return fmt.Errorf(
+			"\"%s\" uses Git LFS but \"git-lfs\" is not installed on the instance (LFS may be skipped in the clone options)",
+			repo.FullName(),
+		)
+	}
+
+	// The submodules LFS objects are pulled too
+	lfsPullCommands := [][]string{
+		{"lfs", "pull"},
+		{"submodule", "--quiet", "foreach", "--recursive", "git lfs pull"},
+	}
+
+	for _, lfsPullCommand := range lfsPullCommands {
+		if repo.CloneOptions.SkipSubmodules && lfsPullCommand[0] == "submodule" {
+			continue
+		}
+
+		// Smudging must not be skipped here
+		_, err := runGitCommandWithEnv(
+			ctx,
+			repoDirPath,
+			[]string{},
+			lfsPullCommand...,
+		)
+
+		if err != nil && ctx.Err() == nil {
+			return fmt.Errorf(
+				"error while pulling the LFS objects of \"%s\".\n\n%v",
+				repo.FullName(),
+				err,
+			)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
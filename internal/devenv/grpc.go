@@ -1,6 +1,94 @@
 package devenv
 
-import "github.com/recode-sh/agent/proto"
+import (
+	"bytes"
+	"io"
+	"sync"
+
+	"github.com/recode-sh/agent/proto"
+)
+
+// syncBuildAndStartDevEnvStream lets multiple goroutines send
+// replies through the same stream given that gRPC streams
+// don't support concurrent calls to "Send"
+type syncBuildAndStartDevEnvStream struct {
+	proto.Agent_BuildAndStartDevEnvServer
+	mutex *sync.Mutex
+}
+
+func newSyncBuildAndStartDevEnvStream(
+	stream proto.Agent_BuildAndStartDevEnvServer,
+) proto.Agent_BuildAndStartDevEnvServer {
+
+	if _, ok := stream.(syncBuildAndStartDevEnvStream); ok {
+		return stream
+	}
+
+	return syncBuildAndStartDevEnvStream{
+		Agent_BuildAndStartDevEnvServer: stream,
+		mutex:                           &sync.Mutex{},
+	}
+}
+
+func (s syncBuildAndStartDevEnvStream) Send(
+	reply *proto.BuildAndStartDevEnvReply,
+) error {
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.Agent_BuildAndStartDevEnvServer.Send(reply)
+}
+
+// linePrefixWriter prefixes each line written with the passed
+// prefix. Complete lines are written at once to the underlying
+// writer to prevent concurrent outputs from being mixed up.
+type linePrefixWriter struct {
+	writer      io.Writer
+	prefix      string
+	currentLine bytes.Buffer
+}
+
+func newLinePrefixWriter(
+	writer io.Writer,
+	prefix string,
+) *linePrefixWriter {
+
+	return &linePrefixWriter{
+		writer: writer,
+		prefix: prefix,
+	}
+}
+
+func (l *linePrefixWriter) Write(p []byte) (int, error) {
+	for _, b := range p {
+		l.currentLine.WriteByte(b)
+
+		if b != '\n' {
+			continue
+		}
+
+		if err := l.Flush(); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(p), nil
+}
+
+// Flush writes the current line (even if not terminated)
+func (l *linePrefixWriter) Flush() error {
+	if l.currentLine.Len() == 0 {
+		return nil
+	}
+
+	line := l.prefix + l.currentLine.String()
+	l.currentLine.Reset()
+
+	_, err := l.writer.Write([]byte(line))
+
+	return err
+}
 
 type GRPCBuildAndStartDevEnvStreamWriter struct {
 	Stream proto.Agent_BuildAndStartDevEnvServer
@@ -29,3 +117,87 @@ func (g GRPCBuildAndStartDevEnvStreamWriter) Write(
 
 	return len(p), nil
 }
+
+type GRPCStopDevEnvStreamWriter struct {
+	Stream proto.Agent_StopDevEnvServer
+}
+
+func NewGRPCStopDevEnvStreamWriter(
+	stream proto.Agent_StopDevEnvServer,
+) GRPCStopDevEnvStreamWriter {
+
+	return GRPCStopDevEnvStreamWriter{
+		Stream: stream,
+	}
+}
+
+func (g GRPCStopDevEnvStreamWriter) Write(
+	p []byte,
+) (int, error) {
+
+	streamSendErr := g.Stream.Send(&proto.StopDevEnvReply{
+		LogLine: string(p),
+	})
+
+	if streamSendErr != nil {
+		return 0, streamSendErr
+	}
+
+	return len(p), nil
+}
+
+type GRPCRollbackDevEnvStreamWriter struct {
+	Stream proto.Agent_RollbackDevEnvServer
+}
+
+func NewGRPCRollbackDevEnvStreamWriter(
+	stream proto.Agent_RollbackDevEnvServer,
+) GRPCRollbackDevEnvStreamWriter {
+
+	return GRPCRollbackDevEnvStreamWriter{
+		Stream: stream,
+	}
+}
+
+func (g GRPCRollbackDevEnvStreamWriter) Write(
+	p []byte,
+) (int, error) {
+
+	streamSendErr := g.Stream.Send(&proto.RollbackDevEnvReply{
+		LogLine: string(p),
+	})
+
+	if streamSendErr != nil {
+		return 0, streamSendErr
+	}
+
+	return len(p), nil
+}
+
+type GRPCSnapshotWorkspaceStreamWriter struct {
+	Stream proto.Agent_SnapshotWorkspaceServer
+}
+
+func NewGRPCSnapshotWorkspaceStreamWriter(
+	stream proto.Agent_SnapshotWorkspaceServer,
+) GRPCSnapshotWorkspaceStreamWriter {
+
+	return GRPCSnapshotWorkspaceStreamWriter{
+		Stream: stream,
+	}
+}
+
+func (g GRPCSnapshotWorkspaceStreamWriter) Write(
+	p []byte,
+) (int, error) {
+
+	streamSendErr := g.Stream.Send(&proto.SnapshotWorkspaceReply{
+		Chunk: p,
+	})
+
+	if streamSendErr != nil {
+		return 0, streamSendErr
+	}
+
+	return len(p), nil
+}
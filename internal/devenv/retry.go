@@ -0,0 +1,243 @@
+package devenv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/recode-sh/agent/constants"
+	"github.com/recode-sh/agent/proto"
+)
+
+// RetryPolicy is used to retry the operations
+// that may fail transiently (clones, image builds...)
+// using exponential backoff
+type RetryPolicy struct {
+	// Including the first attempt
+	MaxAttempts     int
+	InitialInterval time.Duration
+	MaxInterval     time.Duration
+	// No retry is attempted once elapsed
+	MaxElapsedTime time.Duration
+	Multiplier     float64
+	// Intervals are randomized by +/- this factor
+	JitterFactor float64
+}
+
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:     constants.DevEnvRetryMaxAttempts,
+		InitialInterval: constants.DevEnvRetryInitialInterval,
+		MaxInterval:     constants.DevEnvRetryMaxInterval,
+		MaxElapsedTime:  constants.DevEnvRetryMaxElapsedTime,
+		Multiplier:      constants.DevEnvRetryMultiplier,
+		JitterFactor:    constants.DevEnvRetryJitterFactor,
+	}
+}
+
+// NewRetryPolicy returns the default retry policy
+// overridden with the passed values (when not zero)
+func NewRetryPolicy(
+	maxAttempts int,
+	initialInterval time.Duration,
+	maxInterval time.Duration,
+	maxElapsedTime time.Duration,
+) (RetryPolicy, error) {
+
+	if maxAttempts < 0 ||
+		initialInterval < 0 ||
+		maxInterval < 0 ||
+		maxElapsedTime < 0 {
+
+		return RetryPolicy{}, errors.New(
+			"invalid retry policy (negative values are not allowed)",
+		)
+	}
+
+	retryPolicy := DefaultRetryPolicy()
+
+	if maxAttempts > 0 {
+		retryPolicy.MaxAttempts = maxAttempts
+	}
+
+	if initialInterval > 0 {
+		retryPolicy.InitialInterval = initialInterval
+	}
+
+	if maxInterval > 0 {
+		retryPolicy.MaxInterval = maxInterval
+	}
+
+	if maxElapsedTime > 0 {
+		retryPolicy.MaxElapsedTime = maxElapsedTime
+	}
+
+	if retryPolicy.InitialInterval > retryPolicy.MaxInterval {
+		return RetryPolicy{}, fmt.Errorf(
+			"invalid retry policy (initial interval \"%s\" is greater than max interval \"%s\")",
+			retryPolicy.InitialInterval,
+			retryPolicy.MaxInterval,
+		)
+	}
+
+	return retryPolicy, nil
+}
+
+// backoffInterval returns the interval to wait
+// before the passed retry (starting at 1), without jitter
+func (r RetryPolicy) backoffInterval(retry int) time.Duration {
+	interval := float64(r.InitialInterval) * math.Pow(
+		math.Max(r.Multiplier, 1),
+		float64(retry-1),
+	)
+
+	if interval > float64(r.MaxInterval) {
+		return r.MaxInterval
+	}
+
+	return time.Duration(interval)
+}
+
+func (r RetryPolicy) randomizedBackoffInterval(retry int) time.Duration {
+	interval := float64(r.backoffInterval(retry))
+	jitter := interval * r.JitterFactor
+
+	return time.Duration(interval - jitter + rand.Float64()*(2*jitter))
+}
+
+// retryWithBackoff runs the operation until it succeeds,
+// fails with a permanent error or the policy is exhausted.
+// "onRetry" is called before waiting for the next attempt.
+func retryWithBackoff(
+	ctx context.Context,
+	retryPolicy RetryPolicy,
+	operation func() error,
+	onRetry func(attempt int, wait time.Duration, lastErr error) error,
+) error {
+
+	startedAt := time.Now()
+
+	for attempt := 1; ; attempt++ {
+		err := operation()
+
+		if err == nil || !isTransientError(err) || ctx.Err() != nil {
+			return err
+		}
+
+		if attempt >= retryPolicy.MaxAttempts {
+			return err
+		}
+
+		wait := retryPolicy.randomizedBackoffInterval(attempt)
+
+		if retryPolicy.MaxElapsedTime > 0 &&
+			time.Since(startedAt)+wait > retryPolicy.MaxElapsedTime {
+
+			return err
+		}
+
+		if onRetry != nil {
+			onRetryErr := onRetry(attempt+1, wait, err)
+
+			if onRetryErr != nil {
+				return onRetryErr
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// Checked first given that git adds "The remote end hung up
+// unexpectedly" to some permanent errors
+var permanentErrorPatterns = []string{
+	"repository not found",
+	"does not appear to be a git repository",
+	"could not find remote branch",
+	"couldn't find remote ref",
+	"dockerfile parse error",
+	"unknown instruction",
+	"returned a non-zero code",
+	"manifest unknown",
+	"pull access denied",
+}
+
+var transientErrorPatterns = []string{
+	// The SSH keys may be used just after their
+	// creation, before being propagated by GitHub
+	"permission denied (publickey)",
+	"connection reset",
+	"connection refused",
+	"connection timed out",
+	"i/o timeout",
+	"tls handshake timeout",
+	"could not resolve host",
+	"temporary failure in name resolution",
+	"unexpected eof",
+	"early eof",
+	"the remote end hung up unexpectedly",
+	// Registries
+	"toomanyrequests",
+	"429 too many requests",
+	"500 internal server error",
+	"502 bad gateway",
+	"503 service unavailable",
+	"504 gateway timeout",
+}
+
+// isTransientError returns true if the operation
+// that returned the passed error may succeed if retried
+func isTransientError(err error) bool {
+	if err == nil ||
+		errors.Is(err, context.Canceled) ||
+		errors.Is(err, context.DeadlineExceeded) {
+
+		return false
+	}
+
+	errMessage := strings.ToLower(err.Error())
+
+	for _, permanentErrorPattern := range permanentErrorPatterns {
+		if strings.Contains(errMessage, permanentErrorPattern) {
+			return false
+		}
+	}
+
+	for _, transientErrorPattern := range transientErrorPatterns {
+		if strings.Contains(errMessage, transientErrorPattern) {
+			return true
+		}
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// newRetryLogLineSender returns an "onRetry" function that
+// announces the retries in the "BuildAndStartDevEnv" stream
+func newRetryLogLineSender(
+	stream proto.Agent_BuildAndStartDevEnvServer,
+	retryPolicy RetryPolicy,
+) func(int, time.Duration, error) error {
+
+	return func(attempt int, wait time.Duration, lastErr error) error {
+		return stream.Send(&proto.BuildAndStartDevEnvReply{
+			LogLine: fmt.Sprintf(
+				"%v\nRetrying in %s (attempt %d/%d)...\n",
+				lastErr,
+				wait.Round(100*time.Millisecond),
+				attempt,
+				retryPolicy.MaxAttempts,
+			),
+		})
+	}
+}
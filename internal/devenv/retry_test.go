@@ -0,0 +1,179 @@
+package devenv
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyBackoffInterval(t *testing.T) {
+	retryPolicy := RetryPolicy{
+		InitialInterval: time.Second,
+		MaxInterval:     5 * time.Second,
+		Multiplier:      2,
+	}
+
+	testCases := []struct {
+		test             string
+		retry            int
+		expectedInterval time.Duration
+	}{
+		{
+			test:             "first_retry",
+			retry:            1,
+			expectedInterval: time.Second,
+		},
+
+		{
+			test:             "third_retry",
+			retry:            3,
+			expectedInterval: 4 * time.Second,
+		},
+
+		{
+			test:             "capped_by_max_interval",
+			retry:            10,
+			expectedInterval: 5 * time.Second,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.test, func(t *testing.T) {
+			interval := retryPolicy.backoffInterval(tc.retry)
+
+			if interval != tc.expectedInterval {
+				t.Fatalf(
+					"expected interval to equal '%s', got '%s'",
+					tc.expectedInterval,
+					interval,
+				)
+			}
+		})
+	}
+}
+
+func TestIsTransientError(t *testing.T) {
+	testCases := []struct {
+		test              string
+		err               error
+		expectedTransient bool
+	}{
+		{
+			test:              "ssh_key_not_propagated",
+			err:               errors.New("git@github.com: Permission denied (publickey)."),
+			expectedTransient: true,
+		},
+
+		{
+			test:              "registry_5xx",
+			err:               errors.New("received unexpected HTTP status: 503 Service Unavailable"),
+			expectedTransient: true,
+		},
+
+		{
+			test:              "repository_not_found",
+			err:               errors.New("ERROR: Repository not found. fatal: The remote end hung up unexpectedly"),
+			expectedTransient: false,
+		},
+
+		{
+			test:              "dockerfile_error",
+			err:               errors.New("The command '/bin/sh -c exit 1' returned a non-zero code: 1"),
+			expectedTransient: false,
+		},
+
+		{
+			test:              "canceled",
+			err:               context.Canceled,
+			expectedTransient: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.test, func(t *testing.T) {
+			transient := isTransientError(tc.err)
+
+			if transient != tc.expectedTransient {
+				t.Fatalf(
+					"expected transient to equal '%t', got '%t'",
+					tc.expectedTransient,
+					transient,
+				)
+			}
+		})
+	}
+}
+
+func TestRetryWithBackoff(t *testing.T) {
+	retryPolicy := RetryPolicy{
+		MaxAttempts:     3,
+		InitialInterval: time.Millisecond,
+		MaxInterval:     time.Millisecond,
+		Multiplier:      2,
+		JitterFactor:    0.2,
+	}
+
+	testCases := []struct {
+		test             string
+		err              error
+		expectedAttempts int
+	}{
+		{
+			test:             "transient_error",
+			err:              errors.New("connection reset by peer"),
+			expectedAttempts: 3,
+		},
+
+		{
+			test:             "permanent_error",
+			err:              errors.New("repository not found"),
+			expectedAttempts: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.test, func(t *testing.T) {
+			attempts := 0
+			retries := 0
+
+			err := retryWithBackoff(
+				context.Background(),
+				retryPolicy,
+				func() error {
+					attempts++
+					return tc.err
+				},
+				func(attempt int, _ time.Duration, _ error) error {
+					retries++
+
+					if attempt != attempts+1 {
+						t.Fatalf("expected attempt to equal '%d', got '%d'", attempts+1, attempt)
+					}
+
+					return nil
+				},
+			)
+
+			if err != tc.err {
+				t.Fatalf("expected error '%v', got '%v'", tc.err, err)
+			}
+
+			if attempts != tc.expectedAttempts {
+				t.Fatalf(
+					"expected attempts to equal '%d', got '%d'",
+					tc.expectedAttempts,
+					attempts,
+				)
+			}
+
+			if retries != tc.expectedAttempts-1 {
+				t.Fatalf(
+					"expected retries to equal '%d', got '%d'",
+					tc.expectedAttempts-1,
+					retries,
+				)
+			}
+		})
+	}
+}
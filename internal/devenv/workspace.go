@@ -1,117 +1,292 @@
 package devenv
 
 import (
+	"context"
+	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
-	"github.com/recode-sh/agent/constants"
 	"github.com/recode-sh/agent/internal/system"
+	"github.com/recode-sh/agent/proto"
 	"github.com/recode-sh/recode/entities"
-	"github.com/recode-sh/recode/github"
 )
 
 type PreparedWorkspaceMetadata struct {
-	TmpUserConfigRepoDirPath    string
+	TmpUserConfigRepoDirPath string
+	// The temporary clone of the dev env repository is moved to the
+	// workspace when possible. In this case, this path is emptied and
+	// the config dir and Dockerfile paths point to the workspace.
 	TmpDevEnvRepoDirPath        string
 	TmpDevEnvRepoConfigDirPath  string
 	TmpDevEnvRepoDockerfilePath string
 	DevEnvRepoHasDockerfile     bool
+	// Repositories cloned during this preparation
+	// (existing clones are synced in incremental mode)
+	ClonedRepoDirPaths []string
 }
 
 func PrepareWorkspace(
-	userConfigRepoOwner string,
-	userConfigRepoName string,
-	devEnvRepoOwner string,
-	devEnvRepoName string,
+	ctx context.Context,
+	stream proto.Agent_BuildAndStartDevEnvServer,
+	devEnv *DevEnv,
+	userConfigRepo *GitRepository,
+	devEnvRepo *GitRepository,
 	workspaceConfig *WorkspaceConfig,
+	syncOptions WorkspaceSyncOptions,
+	reposCloneOptions ReposCloneOptions,
+	layout proto.WorkspaceLayout,
 ) (*PreparedWorkspaceMetadata, error) {
 
 	preparedWorkspaceMetadata := &PreparedWorkspaceMetadata{}
+	previousRepos := workspaceConfig.Repositories
+
+	err := prepareWorkspace(
+		ctx,
+		stream,
+		devEnv,
+		userConfigRepo,
+		devEnvRepo,
+		workspaceConfig,
+		previousRepos,
+		syncOptions,
+		reposCloneOptions,
+		layout,
+		preparedWorkspaceMetadata,
+	)
+
+	if err != nil {
+		os.RemoveAll(preparedWorkspaceMetadata.TmpUserConfigRepoDirPath)
+		os.RemoveAll(preparedWorkspaceMetadata.TmpDevEnvRepoDirPath)
+
+		var resetErr error
+
+		if syncOptions.isIncremental() {
+			// Existing clones are never removed in incremental mode.
+			// Only the repositories cloned during this preparation are.
+			resetErr = restoreWorkspace(
+				devEnv,
+				workspaceConfig,
+				previousRepos,
+				preparedWorkspaceMetadata,
+			)
+		} else {
+			// Leave the workspace in a consistent state
+			// in case of error (or cancellation) given that
+			// a half-prepared workspace is of no use.
+			resetErr = ResetWorkspace(devEnv)
+		}
+
+		if resetErr != nil {
+			return nil, fmt.Errorf(
+				"%v (error while resetting the workspace: %v)",
+				err,
+				resetErr,
+			)
+		}
+
+		return nil, err
+	}
+
+	return preparedWorkspaceMetadata, nil
+}
+
+// ResetWorkspace removes all the repositories from the
+// workspace and saves an empty workspace config.
+func ResetWorkspace(devEnv *DevEnv) error {
+	filesManager := system.NewFileManager()
+
+	err := filesManager.RemoveDirContent(
+		devEnv.WorkspaceDirPath,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	err = filesManager.RemoveDirContent(
+		devEnv.WorkspaceConfigDirPath,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	return SaveWorkspaceConfigAsFile(
+		devEnv.WorkspaceConfigFilePath,
+		NewWorkspaceConfig(),
+	)
+}
+
+// restoreWorkspace reverts the workspace config to the passed
+// repositories and removes the repositories cloned during
+// a failed incremental preparation.
+func restoreWorkspace(
+	devEnv *DevEnv,
+	workspaceConfig *WorkspaceConfig,
+	previousRepos []WorkspaceConfigRepository,
+	preparedWorkspaceMetadata *PreparedWorkspaceMetadata,
+) error {
+
+	for _, clonedRepoDirPath := range preparedWorkspaceMetadata.ClonedRepoDirPaths {
+		err := os.RemoveAll(clonedRepoDirPath)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	workspaceConfig.Repositories = previousRepos
+
+	err := removeUnusedWorkspaceHooks(devEnv, workspaceConfig)
+
+	if err != nil {
+		return err
+	}
+
+	return SaveWorkspaceConfigAsFile(
+		devEnv.WorkspaceConfigFilePath,
+		workspaceConfig,
+	)
+}
+
+func prepareWorkspace(
+	ctx context.Context,
+	stream proto.Agent_BuildAndStartDevEnvServer,
+	devEnv *DevEnv,
+	userConfigRepo *GitRepository,
+	devEnvRepo *GitRepository,
+	workspaceConfig *WorkspaceConfig,
+	previousRepos []WorkspaceConfigRepository,
+	syncOptions WorkspaceSyncOptions,
+	reposCloneOptions ReposCloneOptions,
+	layout proto.WorkspaceLayout,
+	preparedWorkspaceMetadata *PreparedWorkspaceMetadata,
+) error {
+
 	vscodeWorkspaceConfig := buildInitialVSCodeWorkspaceConfig()
 
 	err := prepareUserConfigRepo(
-		userConfigRepoOwner,
-		userConfigRepoName,
+		ctx,
+		stream,
+		userConfigRepo,
 		preparedWorkspaceMetadata,
 		&vscodeWorkspaceConfig,
 	)
 
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	reposToCloneInWorkspace, err := prepareDevEnvRepo(
-		devEnvRepoOwner,
-		devEnvRepoName,
+		ctx,
+		stream,
+		devEnvRepo,
+		reposCloneOptions,
 		preparedWorkspaceMetadata,
 		&vscodeWorkspaceConfig,
 	)
 
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	filesManager := system.NewFileManager()
-
-	// The method "PrepareWorkspace" could
-	// be called multiple times in case of error
-	// so we need to make sure that our code is idempotent
-	err = filesManager.RemoveDirContent(
-		constants.DevEnvWorkspaceDirPath,
+	// Fail fast (before touching the workspace) on collisions
+	repoRelDirPathsInWorkspace, err := buildRepoRelDirPathsInWorkspace(
+		reposToCloneInWorkspace,
+		layout,
 	)
 
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	// Same than previous comment
-	err = filesManager.RemoveDirContent(
-		constants.DevEnvWorkspaceConfigDirPath,
-	)
+	if !syncOptions.isIncremental() {
+		filesManager := system.NewFileManager()
 
-	if err != nil {
-		return nil, err
+		// The method "PrepareWorkspace" could
+		// be called multiple times in case of error
+		// so we need to make sure that our code is idempotent
+		err = filesManager.RemoveDirContent(
+			devEnv.WorkspaceDirPath,
+		)
+
+		if err != nil {
+			return err
+		}
+
+		// Same than previous comment
+		err = filesManager.RemoveDirContent(
+			devEnv.WorkspaceConfigDirPath,
+		)
+
+		if err != nil {
+			return err
+		}
 	}
 
-	for _, repoToCloneInWorkspace := range reposToCloneInWorkspace {
+	// Repositories are re-added below
+	workspaceConfig.Repositories = []WorkspaceConfigRepository{}
+
+	for repoIndex, repoToCloneInWorkspace := range reposToCloneInWorkspace {
 		err = addRepoToWorkspace(
-			devEnvRepoOwner,
-			devEnvRepoName,
+			ctx,
+			stream,
+			devEnv,
+			devEnvRepo,
 			repoToCloneInWorkspace,
+			repoRelDirPathsInWorkspace[repoIndex],
 			workspaceConfig,
 			&vscodeWorkspaceConfig,
 			preparedWorkspaceMetadata,
 		)
 
 		if err != nil {
-			return nil, err
+			return err
+		}
+	}
+
+	if syncOptions.isIncremental() {
+		err = removeReposNoLongerInWorkspace(
+			ctx,
+			stream,
+			devEnv,
+			previousRepos,
+			workspaceConfig,
+			syncOptions.ArchiveRemovedRepos,
+		)
+
+		if err != nil {
+			return err
 		}
 	}
 
 	err = saveVSCodeWorkspaceConfigAsFile(
-		constants.DevEnvVSCodeWorkspaceConfigFilePath,
+		devEnv.VSCodeWorkspaceConfigFilePath,
 		vscodeWorkspaceConfig,
 	)
 
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	err = SaveWorkspaceConfigAsFile(
-		constants.DevEnvWorkspaceConfigFilePath,
+		devEnv.WorkspaceConfigFilePath,
 		workspaceConfig,
 	)
 
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	return preparedWorkspaceMetadata, nil
+	// Hooks are re-installed during each preparation
+	return removeUnusedWorkspaceHooks(devEnv, workspaceConfig)
 }
 
 func prepareUserConfigRepo(
-	userConfigRepoOwner string,
-	userConfigRepoName string,
+	ctx context.Context,
+	stream proto.Agent_BuildAndStartDevEnvServer,
+	userConfigRepo *GitRepository,
 	preparedWorkspaceMetadata *PreparedWorkspaceMetadata,
 	vscodeWorkspaceConfig *VSCodeWorkspaceConfig,
 ) error {
@@ -124,9 +299,39 @@ func prepareUserConfigRepo(
 
 	preparedWorkspaceMetadata.TmpUserConfigRepoDirPath = tmpUserConfigRepoDirPath
 
-	err = cloneGitHubRepo(
-		userConfigRepoOwner,
-		userConfigRepoName,
+	err = runBuildStep(
+		stream,
+		buildStep{
+			ID:   buildStepIDCloneUserConfigRepo,
+			Type: proto.StepType_STEP_TYPE_CLONE_USER_CONFIG_REPO,
+			Description: fmt.Sprintf(
+				"Cloning %s",
+				userConfigRepo.FullNameWithRef(),
+			),
+			Repository: userConfigRepo.FullName(),
+		},
+		func() error {
+			return cloneGitRepo(
+				ctx,
+				userConfigRepo,
+				tmpUserConfigRepoDirPath,
+				newGitCloneProgressStream(
+					stream,
+					buildStepIDCloneUserConfigRepo,
+					userConfigRepo.FullName(),
+				),
+			)
+		},
+	)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = sendResolvedRepo(
+		ctx,
+		stream,
+		userConfigRepo,
 		tmpUserConfigRepoDirPath,
 	)
 
@@ -154,11 +359,13 @@ func prepareUserConfigRepo(
 }
 
 func prepareDevEnvRepo(
-	devEnvRepoOwner string,
-	devEnvRepoName string,
+	ctx context.Context,
+	stream proto.Agent_BuildAndStartDevEnvServer,
+	devEnvRepo *GitRepository,
+	reposCloneOptions ReposCloneOptions,
 	preparedWorkspaceMetadata *PreparedWorkspaceMetadata,
 	vscodeWorkspaceConfig *VSCodeWorkspaceConfig,
-) ([]string, error) {
+) ([]*GitRepository, error) {
 
 	tmpDevEnvRepoDirPath, err := createTmpDirForCloningDevEnvRepo()
 
@@ -168,10 +375,29 @@ func prepareDevEnvRepo(
 
 	preparedWorkspaceMetadata.TmpDevEnvRepoDirPath = tmpDevEnvRepoDirPath
 
-	err = cloneGitHubRepo(
-		devEnvRepoOwner,
-		devEnvRepoName,
-		tmpDevEnvRepoDirPath,
+	err = runBuildStep(
+		stream,
+		buildStep{
+			ID:   buildStepIDCloneDevEnvRepo,
+			Type: proto.StepType_STEP_TYPE_CLONE_DEV_ENV_REPO,
+			Description: fmt.Sprintf(
+				"Cloning %s",
+				devEnvRepo.FullNameWithRef(),
+			),
+			Repository: devEnvRepo.FullName(),
+		},
+		func() error {
+			return cloneGitRepo(
+				ctx,
+				devEnvRepo,
+				tmpDevEnvRepoDirPath,
+				newGitCloneProgressStream(
+					stream,
+					buildStepIDCloneDevEnvRepo,
+					devEnvRepo.FullName(),
+				),
+			)
+		},
 	)
 
 	if err != nil {
@@ -200,8 +426,8 @@ func prepareDevEnvRepo(
 		return nil, err
 	}
 
-	reposToCloneInWorkspace := []string{
-		devEnvRepoOwner + "/" + devEnvRepoName,
+	reposToCloneInWorkspace := []*GitRepository{
+		devEnvRepo,
 	}
 
 	if devEnvRepoHasDockerfile {
@@ -221,7 +447,7 @@ func prepareDevEnvRepo(
 			devEnvRepoVSCodeExtensions,
 		)
 
-		devEnvRepos, err := lookupRepositoriesInDockerfileLabels(
+		devEnvRepoRefs, err := lookupRepositoriesInDockerfileLabels(
 			devEnvRepoDockerfilePath,
 		)
 
@@ -229,8 +455,41 @@ func prepareDevEnvRepo(
 			return nil, err
 		}
 
-		if len(devEnvRepos) > 0 {
-			reposToCloneInWorkspace = devEnvRepos
+		if len(devEnvRepoRefs) > 0 {
+			reposToCloneInWorkspace = []*GitRepository{}
+		}
+
+		// Repositories passed as names are hosted
+		// by the provider of the dev env repository
+		for _, devEnvRepoRef := range devEnvRepoRefs {
+			resolvedRepo, err := ResolveGitRepository(
+				devEnvRepoRef,
+				devEnvRepo.Provider,
+				devEnvRepo.Owner,
+			)
+
+			if err != nil {
+				return nil, err
+			}
+
+			// The dev env repository is cloned at the requested
+			// ref unless another one is set in the labels
+			if len(resolvedRepo.Ref) == 0 &&
+				resolvedRepo.CloneURL() == devEnvRepo.CloneURL() {
+
+				resolvedRepo.Ref = devEnvRepo.Ref
+			}
+
+			resolvedRepo.CloneOptions = reposCloneOptions.Lookup(
+				resolvedRepo.FullName(),
+			)
+
+			resolvedRepo.RetryPolicy = devEnvRepo.RetryPolicy
+
+			reposToCloneInWorkspace = append(
+				reposToCloneInWorkspace,
+				resolvedRepo,
+			)
 		}
 	}
 
@@ -238,32 +497,50 @@ func prepareDevEnvRepo(
 }
 
 func addRepoToWorkspace(
-	devEnvRepoOwner string,
-	devEnvRepoName string,
-	repoName string,
+	ctx context.Context,
+	stream proto.Agent_BuildAndStartDevEnvServer,
+	devEnv *DevEnv,
+	devEnvRepo *GitRepository,
+	repo *GitRepository,
+	repoRelDirPathInWorkspace string,
 	workspaceConfig *WorkspaceConfig,
 	vscodeWorkspaceConfig *VSCodeWorkspaceConfig,
 	preparedWorkspaceMetadata *PreparedWorkspaceMetadata,
 ) error {
 
-	parsedRepo, err := github.ParseRepositoryName(
-		repoName,
-		devEnvRepoOwner,
+	repoDirPathInWorkspace := filepath.Join(
+		devEnv.WorkspaceDirPath,
+		repoRelDirPathInWorkspace,
+	)
+
+	// Nested layouts (eg: "<owner>/<name>")
+	err := os.MkdirAll(
+		filepath.Dir(repoDirPathInWorkspace),
+		os.FileMode(0755),
 	)
 
 	if err != nil {
 		return err
 	}
 
-	// <!> If multiple repos it will clash if same name
-	repoDirPathInWorkspace := filepath.Join(
-		constants.DevEnvWorkspaceDirPath,
-		parsedRepo.Name,
+	// The workspace is empty here unless in incremental mode
+	err = cloneOrSyncRepoInWorkspace(
+		ctx,
+		stream,
+		devEnvRepo,
+		repo,
+		repoDirPathInWorkspace,
+		preparedWorkspaceMetadata,
 	)
 
-	err = cloneGitHubRepo(
-		parsedRepo.Owner,
-		parsedRepo.Name,
+	if err != nil {
+		return err
+	}
+
+	repoCommitSHA, err := sendResolvedRepo(
+		ctx,
+		stream,
+		repo,
 		repoDirPathInWorkspace,
 	)
 
@@ -277,13 +554,15 @@ func addRepoToWorkspace(
 	)
 
 	workspaceConfigRepository := WorkspaceConfigRepository{
-		Owner:         parsedRepo.Owner,
-		Name:          parsedRepo.Name,
+		Owner:         repo.Owner,
+		Name:          repo.Name,
+		CloneURL:      repo.CloneURL(),
+		Ref:           repo.Ref,
+		CommitSHA:     repoCommitSHA,
 		RootDirPath:   repoDirPathInWorkspace,
 		ConfigDirPath: repoConfigDirPath,
 		Hooks:         []WorkspaceConfigRepositoryHook{},
-		IsDevEnvRepo: parsedRepo.Name == devEnvRepoName &&
-			parsedRepo.Owner == devEnvRepoOwner,
+		IsDevEnvRepo:  repo.CloneURL() == devEnvRepo.CloneURL(),
 	}
 
 	repoDockerfilePath := filepath.Join(
@@ -301,8 +580,27 @@ func addRepoToWorkspace(
 		return err
 	}
 
+	hookTimeouts := map[WorkspaceHookType]time.Duration{}
+
 	if repoHasDockerfile {
 
+		hookTimeouts, err = lookupHookTimeoutsInDockerfileLabels(
+			repoDockerfilePath,
+		)
+
+		if err != nil {
+			return err
+		}
+
+		workspaceConfigRepository.HooksDependOn, err = lookupHooksDependenciesInDockerfileLabels(
+			repoDockerfilePath,
+			repo.Owner,
+		)
+
+		if err != nil {
+			return err
+		}
+
 		repoVSCodeExtensions, err := lookupVSCodeExtensionsInDockerfileLabels(
 			repoDockerfilePath,
 		)
@@ -330,23 +628,28 @@ func addRepoToWorkspace(
 	// Maybe by displaying a warning to users that
 	// some repositories need to be run as part of a workspace?
 	if preparedWorkspaceMetadata.DevEnvRepoHasDockerfile {
-		initHookFilePath := filepath.Join(
-			repoConfigDirPath,
-			entities.DevEnvRepositoryConfigHooksDirectory,
-			entities.DevEnvRepositoryInitHookFileName,
-		)
+		for _, hookType := range workspaceHookTypes {
+			hookFilePathInRepo := filepath.Join(
+				repoConfigDirPath,
+				entities.DevEnvRepositoryConfigHooksDirectory,
+				workspaceHookFileNames[hookType],
+			)
 
-		initHookExists, err := filesManager.DoesFileExist(
-			initHookFilePath,
-		)
+			hookExists, err := filesManager.DoesFileExist(
+				hookFilePathInRepo,
+			)
 
-		if err != nil {
-			return err
-		}
+			if err != nil {
+				return err
+			}
+
+			if !hookExists {
+				continue
+			}
 
-		if initHookExists {
 			hookFilePath, err := installHookInWorkspaceConfigDir(
-				initHookFilePath,
+				devEnv,
+				hookFilePathInRepo,
 			)
 
 			if err != nil {
@@ -356,8 +659,11 @@ func addRepoToWorkspace(
 			workspaceConfigRepository.Hooks = append(
 				workspaceConfigRepository.Hooks,
 				WorkspaceConfigRepositoryHook{
+					Type:                 hookType,
 					ScriptFilePath:       hookFilePath,
 					ScriptWorkingDirPath: workspaceConfigRepository.RootDirPath,
+					// The default timeout is used when zero
+					TimeoutSeconds: int64(hookTimeouts[hookType].Seconds()),
 				},
 			)
 		}
@@ -372,12 +678,43 @@ func addRepoToWorkspace(
 		vscodeWorkspaceConfig.Folders,
 		VSCodeWorkspaceConfigFolder{
 			Path: repoDirPathInWorkspace,
+			Name: repoRelDirPathInWorkspace,
 		},
 	)
 
 	return nil
 }
 
+// sendResolvedRepo reports the commit SHA checked
+// out in the passed repository and returns it
+func sendResolvedRepo(
+	ctx context.Context,
+	stream proto.Agent_BuildAndStartDevEnvServer,
+	repo *GitRepository,
+	repoDirPath string,
+) (string, error) {
+
+	repoCommitSHA, err := resolveGitRepoHeadSHA(ctx, repoDirPath)
+
+	if err != nil {
+		return "", err
+	}
+
+	err = stream.Send(&proto.BuildAndStartDevEnvReply{
+		ResolvedRepository: &proto.ResolvedRepository{
+			Repository: repo.FullName(),
+			Ref:        repo.Ref,
+			CommitSha:  repoCommitSHA,
+		},
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	return repoCommitSHA, nil
+}
+
 func createTmpDirForCloningUserConfigRepo() (string, error) {
 	return os.MkdirTemp("", "recode-dev-env-user-config-*")
 }
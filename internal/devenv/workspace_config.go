@@ -8,14 +8,22 @@ import (
 type WorkspaceConfigRepository struct {
 	Owner         string                          `json:"owner"`
 	Name          string                          `json:"name"`
+	CloneURL      string                          `json:"clone_url,omitempty"`
+	Ref           string                          `json:"ref,omitempty"`
+	CommitSHA     string                          `json:"commit_sha,omitempty"`
 	RootDirPath   string                          `json:"root_dir_path"`
 	ConfigDirPath string                          `json:"config_dir_path"`
 	Hooks         []WorkspaceConfigRepositoryHook `json:"hooks"`
-	IsDevEnvRepo  bool                            `json:"is_main"`
+	// Full names of the repositories whose hooks must be run first
+	HooksDependOn []string `json:"hooks_depend_on,omitempty"`
+	IsDevEnvRepo  bool     `json:"is_main"`
 }
 
 type WorkspaceConfig struct {
 	Repositories []WorkspaceConfigRepository `json:"repositories"`
+	// Start time of the container during which the "start"
+	// and "attach" hooks were last run (indexed by hook type)
+	HooksRunForContainerStartedAt map[WorkspaceHookType]string `json:"hooks_run_for_container_started_at,omitempty"`
 }
 
 func NewWorkspaceConfig() *WorkspaceConfig {
@@ -41,9 +49,43 @@ func LoadWorkspaceConfig(
 		return nil, err
 	}
 
+	// Hooks were only of type "init" before the type was added
+	for repoIndex, repo := range workspaceConfig.Repositories {
+		for hookIndex, hook := range repo.Hooks {
+			if len(hook.Type) == 0 {
+				workspaceConfig.Repositories[repoIndex].Hooks[hookIndex].Type = WorkspaceHookTypeInit
+			}
+		}
+	}
+
 	return workspaceConfig, nil
 }
 
+// repositoriesHooksDependencies returns the full names of the
+// repositories whose hooks must be run first (indexed by the
+// full names of all the repositories, with or without hooks)
+func (w *WorkspaceConfig) repositoriesHooksDependencies() map[string][]string {
+	reposHooksDependencies := map[string][]string{}
+
+	for _, repo := range w.Repositories {
+		reposHooksDependencies[repo.Owner+"/"+repo.Name] = repo.HooksDependOn
+	}
+
+	return reposHooksDependencies
+}
+
+func (w *WorkspaceConfig) markHooksRunForContainerStart(
+	hookType WorkspaceHookType,
+	containerStartedAt string,
+) {
+
+	if w.HooksRunForContainerStartedAt == nil {
+		w.HooksRunForContainerStartedAt = map[WorkspaceHookType]string{}
+	}
+
+	w.HooksRunForContainerStartedAt[hookType] = containerStartedAt
+}
+
 func SaveWorkspaceConfigAsFile(
 	workspaceConfigFilePath string,
 	workspaceConfig *WorkspaceConfig,
@@ -2,120 +2,867 @@ package devenv
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"sync"
+	"time"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/client"
 	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/recode-sh/agent/constants"
+	"github.com/recode-sh/agent/internal/docker"
+	"github.com/recode-sh/agent/internal/system"
 	"github.com/recode-sh/agent/proto"
 	"github.com/recode-sh/recode/entities"
 )
 
+// WorkspaceHookType represents the moment at which a hook
+// is run during the lifecycle of a development environment
+type WorkspaceHookType string
+
+const (
+	// Run once after each build (ie: after clone)
+	WorkspaceHookTypeInit WorkspaceHookType = "init"
+	// Run after each container start
+	// (including after instance reboot)
+	WorkspaceHookTypeStart WorkspaceHookType = "start"
+	// Run during the first SSH session
+	// following each container start
+	WorkspaceHookTypeAttach WorkspaceHookType = "attach"
+	// Run before the container is stopped
+	WorkspaceHookTypeStop WorkspaceHookType = "stop"
+)
+
+// Ordered by lifecycle
+var workspaceHookTypes = []WorkspaceHookType{
+	WorkspaceHookTypeInit,
+	WorkspaceHookTypeStart,
+	WorkspaceHookTypeAttach,
+	WorkspaceHookTypeStop,
+}
+
+// Looked up in the hooks directory of each repository
+var workspaceHookFileNames = map[WorkspaceHookType]string{
+	WorkspaceHookTypeInit:   entities.DevEnvRepositoryInitHookFileName,
+	WorkspaceHookTypeStart:  constants.DevEnvRepositoryStartHookFileName,
+	WorkspaceHookTypeAttach: constants.DevEnvRepositoryAttachHookFileName,
+	WorkspaceHookTypeStop:   constants.DevEnvRepositoryStopHookFileName,
+}
+
+// Used when no timeout is set in the "dev_env.Dockerfile" labels
+var workspaceHookDefaultTimeouts = map[WorkspaceHookType]time.Duration{
+	WorkspaceHookTypeInit:   time.Hour,
+	WorkspaceHookTypeStart:  10 * time.Minute,
+	WorkspaceHookTypeAttach: 5 * time.Minute,
+	WorkspaceHookTypeStop:   5 * time.Minute,
+}
+
 type WorkspaceConfigRepositoryHook struct {
-	ScriptFilePath       string `json:"script_file_path"`
-	ScriptWorkingDirPath string `json:"script_working_dir_path"`
+	Type                 WorkspaceHookType `json:"type"`
+	ScriptFilePath       string            `json:"script_file_path"`
+	ScriptWorkingDirPath string            `json:"script_working_dir_path"`
+	TimeoutSeconds       int64             `json:"timeout_seconds,omitempty"`
+	LastExitCode         *int              `json:"last_exit_code,omitempty"`
 }
 
+// Timeout returns the timeout set in the labels or the default one
+func (w WorkspaceConfigRepositoryHook) Timeout() time.Duration {
+	if w.TimeoutSeconds > 0 {
+		return time.Duration(w.TimeoutSeconds) * time.Second
+	}
+
+	return workspaceHookDefaultTimeouts[w.Type]
+}
+
+// Prevent concurrent runs of the hooks of each development
+// environment (indexed by name). Channels are used to let
+// callers stop waiting.
+var (
+	workspaceHooksLocksMutex sync.Mutex
+	workspaceHooksLocks      = map[string]chan struct{}{}
+)
+
+var ErrWorkspaceHooksRunning = errors.New("the workspace hooks are already running")
+
+func lookupWorkspaceHooksLock(devEnv *DevEnv) chan struct{} {
+	workspaceHooksLocksMutex.Lock()
+	defer workspaceHooksLocksMutex.Unlock()
+
+	workspaceHooksLock, lockExists := workspaceHooksLocks[devEnv.Name]
+
+	if !lockExists {
+		workspaceHooksLock = make(chan struct{}, 1)
+		workspaceHooksLocks[devEnv.Name] = workspaceHooksLock
+	}
+
+	return workspaceHooksLock
+}
+
+// LockWorkspaceHooks waits until the hooks of the development
+// environment are not running. Operations that (re)create the
+// container take it before doing so to prevent SSH sessions
+// from running the "start" and "attach" hooks before theirs.
+func LockWorkspaceHooks(ctx context.Context, devEnv *DevEnv) error {
+	select {
+	case lookupWorkspaceHooksLock(devEnv) <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func tryLockWorkspaceHooks(devEnv *DevEnv) bool {
+	select {
+	case lookupWorkspaceHooksLock(devEnv) <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+func UnlockWorkspaceHooks(devEnv *DevEnv) {
+	<-lookupWorkspaceHooksLock(devEnv)
+}
+
+func buildWorkspaceHookDescription(
+	repo WorkspaceConfigRepository,
+	hookType WorkspaceHookType,
+) string {
+
+	return fmt.Sprintf(
+		"Running %s/%s/%s/%s/%s",
+		repo.Owner,
+		repo.Name,
+		entities.DevEnvRepositoryConfigDirectory,
+		entities.DevEnvRepositoryConfigHooksDirectory,
+		workspaceHookFileNames[hookType],
+	)
+}
+
+// RunWorkspaceHooks runs the "init" hooks (once per clone) and then
+// the "start" hooks of the repositories once the container is started.
+// The hooks of each type are run concurrently using
+// the passed options (see "runWorkspaceHookTasks").
+// When "ContinueOnError" is set, failing hooks are only
+// reported through their build steps. The caller must hold
+// the hooks lock (see "LockWorkspaceHooks").
 func RunWorkspaceHooks(
+	ctx context.Context,
 	dockerClient *client.Client,
 	stream proto.Agent_BuildAndStartDevEnvServer,
+	devEnv *DevEnv,
 	workspaceConfig *WorkspaceConfig,
+	hooksOptions WorkspaceHooksOptions,
 ) error {
 
-	for _, repo := range workspaceConfig.Repositories {
+	stream = newSyncBuildAndStartDevEnvStream(stream)
+
+	for _, hookType := range []WorkspaceHookType{
+		WorkspaceHookTypeInit,
+		WorkspaceHookTypeStart,
+	} {
+
+		err := runWorkspaceBuildHooks(
+			ctx,
+			dockerClient,
+			stream,
+			devEnv,
+			workspaceConfig,
+			hookType,
+			hooksOptions,
+		)
 
-		if len(repo.Hooks) == 0 {
+		if err != nil && hooksOptions.ContinueOnError && ctx.Err() == nil {
 			continue
 		}
 
-		initHook := repo.Hooks[0]
-
-		err := stream.Send(&proto.BuildAndStartDevEnvReply{
-			LogLineHeader: fmt.Sprintf(
-				"Running %s/%s/%s/%s/%s",
-				repo.Owner,
-				repo.Name,
-				entities.DevEnvRepositoryConfigDirectory,
-				entities.DevEnvRepositoryConfigHooksDirectory,
-				entities.DevEnvRepositoryInitHookFileName,
-			),
-		})
-
 		if err != nil {
 			return err
 		}
+	}
+
+	// Prevent the "start" hooks from being run
+	// again until the next container start
+	containerStartedAt, err := docker.LookupContainerStartedAt(
+		dockerClient,
+		devEnv.DockerContainerName,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	workspaceConfig.markHooksRunForContainerStart(
+		WorkspaceHookTypeStart,
+		containerStartedAt,
+	)
+
+	return SaveWorkspaceConfigAsFile(
+		devEnv.WorkspaceConfigFilePath,
+		workspaceConfig,
+	)
+}
+
+func runWorkspaceBuildHooks(
+	ctx context.Context,
+	dockerClient *client.Client,
+	stream proto.Agent_BuildAndStartDevEnvServer,
+	devEnv *DevEnv,
+	workspaceConfig *WorkspaceConfig,
+	hookType WorkspaceHookType,
+	hooksOptions WorkspaceHooksOptions,
+) error {
 
-		exec, err := dockerClient.ContainerExecCreate(
-			context.TODO(),
-			constants.DevEnvDockerContainerName,
-			types.ExecConfig{
-				AttachStdin:  false,
-				AttachStdout: true,
-				AttachStderr: true,
-				Detach:       false,
-				Tty:          false,
-				Cmd: []string{
-					initHook.ScriptFilePath,
+	// Guards the workspace config
+	// updated by the running hooks
+	var workspaceConfigMutex sync.Mutex
+
+	tasks := []*workspaceHookTask{}
+
+	for repoIndex, repo := range workspaceConfig.Repositories {
+		repo := repo
+
+		for hookIndex, hook := range repo.Hooks {
+			if hook.Type != hookType {
+				continue
+			}
+
+			// Run once per clone
+			if hookType == WorkspaceHookTypeInit && hasWorkspaceInitHookRun(repo) {
+				err := stream.Send(&proto.BuildAndStartDevEnvReply{
+					LogLine: fmt.Sprintf(
+						"Skipping the init hook of %s/%s (already run after the clone)\n",
+						repo.Owner,
+						repo.Name,
+					),
+				})
+
+				if err != nil {
+					return err
+				}
+
+				continue
+			}
+
+			hookToRun := &workspaceConfig.Repositories[repoIndex].Hooks[hookIndex]
+
+			hookStep := buildStep{
+				ID: buildStepIDRunHook(
+					repo.Owner,
+					repo.Name,
+					workspaceHookFileNames[hookType],
+				),
+				Type:        proto.StepType_STEP_TYPE_RUN_HOOK,
+				Description: buildWorkspaceHookDescription(repo, hookType),
+				Repository:  repo.Owner + "/" + repo.Name,
+			}
+
+			tasks = append(tasks, &workspaceHookTask{
+				repoFullName: repo.Owner + "/" + repo.Name,
+				dependsOn:    repo.HooksDependOn,
+				run: func(taskCtx context.Context) error {
+					return runBuildStep(
+						stream,
+						hookStep,
+						func() error {
+							return runWorkspaceBuildHook(
+								taskCtx,
+								dockerClient,
+								stream,
+								devEnv,
+								workspaceConfig,
+								&workspaceConfigMutex,
+								repo,
+								hookToRun,
+							)
+						},
+					)
 				},
-				WorkingDir: initHook.ScriptWorkingDirPath,
-				User:       constants.DevEnvRecodeUserName,
-				Privileged: true,
-			},
+				skip: func(reason string) {
+					// Like failure events, skip events are best-effort
+					_ = skipBuildStep(stream, hookStep, reason)
+				},
+			})
+		}
+	}
+
+	return runWorkspaceHookTasks(
+		ctx,
+		tasks,
+		workspaceConfig.repositoriesHooksDependencies(),
+		hooksOptions,
+	)
+}
+
+func runWorkspaceBuildHook(
+	ctx context.Context,
+	dockerClient *client.Client,
+	stream proto.Agent_BuildAndStartDevEnvServer,
+	devEnv *DevEnv,
+	workspaceConfig *WorkspaceConfig,
+	workspaceConfigMutex *sync.Mutex,
+	repo WorkspaceConfigRepository,
+	hook *WorkspaceConfigRepositoryHook,
+) error {
+
+	err := stream.Send(&proto.BuildAndStartDevEnvReply{
+		LogLineHeader: buildWorkspaceHookDescription(repo, hook.Type),
+	})
+
+	if err != nil {
+		return err
+	}
+
+	// Hooks may run concurrently so each line
+	// is prefixed with the repository full name
+	hookOutputWriter := newLinePrefixWriter(
+		NewGRPCBuildAndStartDevEnvStreamWriter(stream),
+		"["+repo.Owner+"/"+repo.Name+"] ",
+	)
+
+	hookResult, err := runWorkspaceHook(
+		ctx,
+		dockerClient,
+		devEnv,
+		repo,
+		*hook,
+		hookOutputWriter,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	err = hookOutputWriter.Flush()
+
+	if err != nil {
+		return err
+	}
+
+	workspaceConfigMutex.Lock()
+	defer workspaceConfigMutex.Unlock()
+
+	// Persisted to let callers know
+	// the result of the last run
+	hook.LastExitCode = &hookResult.ExitCode
+
+	err = SaveWorkspaceConfigAsFile(
+		devEnv.WorkspaceConfigFilePath,
+		workspaceConfig,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	if hookResult.TimedOut {
+		return fmt.Errorf(
+			"error while running \"%s hook\" for \"%s/%s\". Timed out after %s",
+			hook.Type,
+			repo.Owner,
+			repo.Name,
+			hook.Timeout(),
 		)
+	}
 
-		if err != nil {
-			return err
-		}
+	if hookResult.ExitCode != 0 {
+		return fmt.Errorf(
+			"error while running \"%s hook\" for \"%s/%s\". Exit status code %d",
+			hook.Type,
+			repo.Owner,
+			repo.Name,
+			hookResult.ExitCode,
+		)
+	}
+
+	if hook.Type == WorkspaceHookTypeInit {
+		return markWorkspaceInitHookRun(repo)
+	}
+
+	return nil
+}
+
+// RunWorkspaceStartHooksAfterRestart runs the "start" hooks if the
+// container was restarted outside of a build (eg: by Docker after
+// an instance reboot given that its restart policy is "always").
+func RunWorkspaceStartHooksAfterRestart(
+	ctx context.Context,
+	dockerClient *client.Client,
+	devEnv *DevEnv,
+	outputWriter io.Writer,
+) error {
+
+	err := LockWorkspaceHooks(ctx, devEnv)
+
+	if err != nil {
+		return err
+	}
+
+	defer UnlockWorkspaceHooks(devEnv)
+
+	return runWorkspaceHooksOncePerContainerStart(
+		ctx,
+		dockerClient,
+		devEnv,
+		WorkspaceHookTypeStart,
+		outputWriter,
+	)
+}
+
+// RunWorkspaceHooksBeforeSession runs the "attach" hooks during the
+// first SSH session following each container start. The "start" hooks
+// are run first if they were missed when the agent started (eg: Docker
+// was not ready yet). Sessions don't wait for the hooks run by other
+// sessions ("ErrWorkspaceHooksRunning" is returned).
+func RunWorkspaceHooksBeforeSession(
+	ctx context.Context,
+	dockerClient *client.Client,
+	devEnv *DevEnv,
+	outputWriter io.Writer,
+) error {
+
+	if !tryLockWorkspaceHooks(devEnv) {
+		return ErrWorkspaceHooksRunning
+	}
+
+	defer UnlockWorkspaceHooks(devEnv)
 
-		containerStream, err := dockerClient.ContainerExecAttach(
-			context.TODO(),
-			exec.ID,
-			types.ExecStartCheck{},
+	for _, hookType := range []WorkspaceHookType{
+		WorkspaceHookTypeStart,
+		WorkspaceHookTypeAttach,
+	} {
+
+		err := runWorkspaceHooksOncePerContainerStart(
+			ctx,
+			dockerClient,
+			devEnv,
+			hookType,
+			outputWriter,
 		)
 
 		if err != nil {
 			return err
 		}
+	}
 
-		defer containerStream.Close()
+	return nil
+}
 
-		grpcServerStreamWriter := NewGRPCBuildAndStartDevEnvStreamWriter(stream)
+// runWorkspaceHooksOncePerContainerStart runs the hooks of the passed
+// type unless they were already run since the last container start.
+// A failing hook is reported but doesn't prevent the next hooks from running.
+// The workspace hooks lock must be held by the caller.
+func runWorkspaceHooksOncePerContainerStart(
+	ctx context.Context,
+	dockerClient *client.Client,
+	devEnv *DevEnv,
+	hookType WorkspaceHookType,
+	outputWriter io.Writer,
+) error {
 
-		_, err = stdcopy.StdCopy(
-			grpcServerStreamWriter,
-			grpcServerStreamWriter,
-			containerStream.Reader,
-		)
+	containerStartedAt, err := docker.LookupContainerStartedAt(
+		dockerClient,
+		devEnv.DockerContainerName,
+	)
 
-		if err != nil {
-			return err
+	if err != nil || len(containerStartedAt) == 0 {
+		return err
+	}
+
+	workspaceConfig, err := LoadWorkspaceConfig(devEnv.WorkspaceConfigFilePath)
+
+	// Not built yet
+	if err != nil && os.IsNotExist(err) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if workspaceConfig.HooksRunForContainerStartedAt[hookType] == containerStartedAt {
+		return nil
+	}
+
+	// Indexed by repository full name
+	hooksExitCodes := map[string]int{}
+
+	for _, repo := range workspaceConfig.Repositories {
+		for _, hook := range repo.Hooks {
+			if hook.Type != hookType {
+				continue
+			}
+
+			if hookType == WorkspaceHookTypeInit && hasWorkspaceInitHookRun(repo) {
+				continue
+			}
+
+			fmt.Fprintln(outputWriter, buildWorkspaceHookDescription(repo, hookType))
+
+			hookResult, err := runWorkspaceHook(
+				ctx,
+				dockerClient,
+				devEnv,
+				repo,
+				hook,
+				outputWriter,
+			)
+
+			if err != nil {
+				return err
+			}
+
+			hooksExitCodes[repo.Owner+"/"+repo.Name] = hookResult.ExitCode
+
+			if hookResult.ExitCode != 0 {
+				fmt.Fprintln(
+					outputWriter,
+					buildWorkspaceHookFailureMessage(repo, hook, hookResult),
+				)
+
+				continue
+			}
+
+			if hookType == WorkspaceHookTypeInit {
+				err = markWorkspaceInitHookRun(repo)
+
+				if err != nil {
+					return err
+				}
+			}
 		}
+	}
 
-		containerInspect, err := dockerClient.ContainerExecInspect(
-			context.TODO(),
-			exec.ID,
-		)
+	return saveWorkspaceHooksRunState(
+		devEnv,
+		hookType,
+		containerStartedAt,
+		hooksExitCodes,
+	)
+}
 
-		if err != nil {
-			return err
+// saveWorkspaceHooksRunState re-loads the workspace config (that may
+// have been updated by an operation while the hooks were running)
+// and only updates the state of the hooks of the passed type
+func saveWorkspaceHooksRunState(
+	devEnv *DevEnv,
+	hookType WorkspaceHookType,
+	containerStartedAt string,
+	hooksExitCodes map[string]int,
+) error {
+
+	workspaceConfig, err := LoadWorkspaceConfig(devEnv.WorkspaceConfigFilePath)
+
+	if err != nil {
+		return err
+	}
+
+	for repoIndex, repo := range workspaceConfig.Repositories {
+		exitCode, ok := hooksExitCodes[repo.Owner+"/"+repo.Name]
+
+		if !ok {
+			continue
+		}
+
+		for hookIndex, hook := range repo.Hooks {
+			if hook.Type != hookType {
+				continue
+			}
+
+			exitCodeCopy := exitCode
+			workspaceConfig.Repositories[repoIndex].Hooks[hookIndex].LastExitCode = &exitCodeCopy
 		}
+	}
+
+	workspaceConfig.markHooksRunForContainerStart(hookType, containerStartedAt)
+
+	return SaveWorkspaceConfigAsFile(
+		devEnv.WorkspaceConfigFilePath,
+		workspaceConfig,
+	)
+}
+
+// hasWorkspaceInitHookRun returns true if the "init" hook of the
+// repository has succeeded since its clone. The marker is written
+// in the ".git" dir so it is removed with the clone.
+func hasWorkspaceInitHookRun(repo WorkspaceConfigRepository) bool {
+	_, err := os.Stat(workspaceInitHookMarkerFilePath(repo))
+	return err == nil
+}
+
+func markWorkspaceInitHookRun(repo WorkspaceConfigRepository) error {
+	err := os.WriteFile(
+		workspaceInitHookMarkerFilePath(repo),
+		[]byte(repo.CommitSHA+"\n"),
+		os.FileMode(0644),
+	)
+
+	// Not a git clone. The "init" hook
+	// is run during each build in this case.
+	if err != nil && os.IsNotExist(err) {
+		return nil
+	}
+
+	return err
+}
+
+func workspaceInitHookMarkerFilePath(repo WorkspaceConfigRepository) string {
+	return filepath.Join(
+		repo.RootDirPath,
+		".git",
+		constants.DevEnvInitHookMarkerFileName,
+	)
+}
+
+// RunWorkspaceStopHooks runs the "stop" hooks of the
+// repositories of the workspace. Given that these hooks are run
+// before stopping the container, a failing hook is reported
+// but doesn't prevent the next hooks from running.
+func RunWorkspaceStopHooks(
+	ctx context.Context,
+	dockerClient *client.Client,
+	stream proto.Agent_StopDevEnvServer,
+	devEnv *DevEnv,
+	workspaceConfig *WorkspaceConfig,
+) error {
+
+	for repoIndex, repo := range workspaceConfig.Repositories {
+		for hookIndex, hook := range repo.Hooks {
+			if hook.Type != WorkspaceHookTypeStop {
+				continue
+			}
+
+			err := stream.Send(&proto.StopDevEnvReply{
+				LogLineHeader: buildWorkspaceHookDescription(
+					repo,
+					WorkspaceHookTypeStop,
+				),
+			})
+
+			if err != nil {
+				return err
+			}
+
+			hookResult, err := runWorkspaceHook(
+				ctx,
+				dockerClient,
+				devEnv,
+				repo,
+				hook,
+				NewGRPCStopDevEnvStreamWriter(stream),
+			)
 
-		if containerInspect.ExitCode != 0 {
-			return fmt.Errorf(
-				"error while running \"init hook\" for \"%s/%s\". Exit status code %d",
-				repo.Owner,
-				repo.Name,
-				containerInspect.ExitCode,
+			if err != nil {
+				return err
+			}
+
+			workspaceConfig.Repositories[repoIndex].Hooks[hookIndex].LastExitCode = &hookResult.ExitCode
+
+			err = SaveWorkspaceConfigAsFile(
+				devEnv.WorkspaceConfigFilePath,
+				workspaceConfig,
 			)
+
+			if err != nil {
+				return err
+			}
+
+			if hookResult.ExitCode != 0 {
+				err = stream.Send(&proto.StopDevEnvReply{
+					LogLine: buildWorkspaceHookFailureMessage(
+						repo,
+						hook,
+						hookResult,
+					) + "\n",
+				})
+
+				if err != nil {
+					return err
+				}
+			}
 		}
 	}
 
 	return nil
 }
 
-func installHookInWorkspaceConfigDir(hookFilePath string) (string, error) {
+// runWorkspaceHook runs the hook in the container (killing it once
+// its timeout has expired) and writes the result of the run in
+// the hooks results directory of the workspace config.
+func runWorkspaceHook(
+	ctx context.Context,
+	dockerClient *client.Client,
+	devEnv *DevEnv,
+	repo WorkspaceConfigRepository,
+	hook WorkspaceConfigRepositoryHook,
+	outputWriter io.Writer,
+) (*WorkspaceHookResult, error) {
+
+	hookCtx, cancelHookCtx := context.WithTimeout(ctx, hook.Timeout())
+	defer cancelHookCtx()
+
+	hookOutputTail := newTailWriter(constants.DevEnvHookResultOutputTailSize)
+	startedAt := time.Now()
+
+	hookExitCode, err := execWorkspaceHook(
+		hookCtx,
+		dockerClient,
+		devEnv,
+		hook,
+		buildWorkspaceHookEnv(devEnv, repo, hook),
+		io.MultiWriter(outputWriter, hookOutputTail),
+	)
+
+	hookTimedOut := err != nil &&
+		ctx.Err() == nil &&
+		errors.Is(hookCtx.Err(), context.DeadlineExceeded)
+
+	if hookTimedOut {
+		hookExitCode = constants.DevEnvHookTimeoutExitCode
+	} else if err != nil {
+		return nil, err
+	}
+
+	hookResult := &WorkspaceHookResult{
+		RepoOwner:  repo.Owner,
+		RepoName:   repo.Name,
+		HookType:   hook.Type,
+		ExitCode:   hookExitCode,
+		TimedOut:   hookTimedOut,
+		StartedAt:  startedAt,
+		DurationMs: time.Since(startedAt).Milliseconds(),
+		OutputTail: hookOutputTail.String(),
+	}
+
+	err = saveWorkspaceHookResult(devEnv, repo, hookResult)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return hookResult, nil
+}
+
+func execWorkspaceHook(
+	ctx context.Context,
+	dockerClient *client.Client,
+	devEnv *DevEnv,
+	hook WorkspaceConfigRepositoryHook,
+	hookEnv []string,
+	outputWriter io.Writer,
+) (int, error) {
+
+	exec, err := dockerClient.ContainerExecCreate(
+		ctx,
+		devEnv.DockerContainerName,
+		types.ExecConfig{
+			AttachStdin:  false,
+			AttachStdout: true,
+			AttachStderr: true,
+			Detach:       false,
+			Tty:          false,
+			Cmd: []string{
+				hook.ScriptFilePath,
+			},
+			Env:        hookEnv,
+			WorkingDir: hook.ScriptWorkingDirPath,
+			User:       constants.DevEnvRecodeUserName,
+			Privileged: true,
+		},
+	)
+
+	if err != nil {
+		return 0, err
+	}
+
+	containerStream, err := dockerClient.ContainerExecAttach(
+		ctx,
+		exec.ID,
+		types.ExecStartCheck{},
+	)
+
+	if err != nil {
+		return 0, err
+	}
+
+	defer containerStream.Close()
+
+	hookDoneChan := make(chan struct{})
+	defer close(hookDoneChan)
+
+	// The hijacked connection doesn't honor the context
+	// and the exec is not killed by Docker when the
+	// connection is closed so we need to kill it ourselves.
+	go func() {
+		select {
+		case <-ctx.Done():
+			killWorkspaceHook(dockerClient, exec.ID)
+			containerStream.Close()
+		case <-hookDoneChan:
+		}
+	}()
+
+	_, err = stdcopy.StdCopy(
+		outputWriter,
+		outputWriter,
+		containerStream.Reader,
+	)
+
+	if ctx.Err() != nil {
+		return 0, ctx.Err()
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	containerInspect, err := dockerClient.ContainerExecInspect(
+		ctx,
+		exec.ID,
+	)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return containerInspect.ExitCode, nil
+}
+
+func killWorkspaceHook(
+	dockerClient *client.Client,
+	execID string,
+) error {
+
+	// The passed context may be canceled at this point
+	containerInspect, err := dockerClient.ContainerExecInspect(
+		context.Background(),
+		execID,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	if !containerInspect.Running {
+		return nil
+	}
+
+	// The exec PID is relative to the host
+	// given that the agent runs outside the container
+	return system.NewProcessManager().KillProcessTree(
+		containerInspect.Pid,
+	)
+}
+
+func installHookInWorkspaceConfigDir(
+	devEnv *DevEnv,
+	hookFilePath string,
+) (string, error) {
+
 	hookFileContent, err := os.ReadFile(hookFilePath)
 
 	if err != nil {
@@ -125,7 +872,7 @@ func installHookInWorkspaceConfigDir(hookFilePath string) (string, error) {
 	// Ensure that the hooks directory exists given that it
 	// is not created during instance init
 	err = os.MkdirAll(
-		constants.DevEnvWorkspaceConfigHooksDirPath,
+		devEnv.WorkspaceConfigHooksDirPath,
 		os.FileMode(0755),
 	)
 
@@ -134,7 +881,7 @@ func installHookInWorkspaceConfigDir(hookFilePath string) (string, error) {
 	}
 
 	hookTmpFile, err := os.CreateTemp(
-		constants.DevEnvWorkspaceConfigHooksDirPath,
+		devEnv.WorkspaceConfigHooksDirPath,
 		"recode_workspace_hook_*",
 	)
 
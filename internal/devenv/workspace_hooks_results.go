@@ -0,0 +1,203 @@
+package devenv
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// WorkspaceHookResult is the result of the last run of a hook.
+// Written as JSON in the hooks results directory of the workspace
+// config to let callers (and the container MOTD) display it.
+type WorkspaceHookResult struct {
+	RepoOwner  string            `json:"repo_owner"`
+	RepoName   string            `json:"repo_name"`
+	HookType   WorkspaceHookType `json:"hook_type"`
+	ExitCode   int               `json:"exit_code"`
+	TimedOut   bool              `json:"timed_out"`
+	StartedAt  time.Time         `json:"started_at"`
+	DurationMs int64             `json:"duration_ms"`
+	// The end of the output (stdout and stderr)
+	OutputTail string `json:"output_tail"`
+}
+
+// Injected in the environment of the hooks
+const (
+	WorkspaceHookEnvVarRepoOwner     = "RECODE_REPO_OWNER"
+	WorkspaceHookEnvVarRepoName      = "RECODE_REPO_NAME"
+	WorkspaceHookEnvVarRepoDir       = "RECODE_REPO_DIR"
+	WorkspaceHookEnvVarRepoRef       = "RECODE_REPO_REF"
+	WorkspaceHookEnvVarRepoCommitSHA = "RECODE_REPO_COMMIT_SHA"
+	WorkspaceHookEnvVarWorkspaceDir  = "RECODE_WORKSPACE_DIR"
+	WorkspaceHookEnvVarDevEnvName    = "RECODE_DEV_ENV_NAME"
+	WorkspaceHookEnvVarHookType      = "RECODE_HOOK_TYPE"
+)
+
+func buildWorkspaceHookEnv(
+	devEnv *DevEnv,
+	repo WorkspaceConfigRepository,
+	hook WorkspaceConfigRepositoryHook,
+) []string {
+
+	hookEnv := map[string]string{
+		WorkspaceHookEnvVarRepoOwner:     repo.Owner,
+		WorkspaceHookEnvVarRepoName:      repo.Name,
+		WorkspaceHookEnvVarRepoDir:       repo.RootDirPath,
+		WorkspaceHookEnvVarRepoRef:       repo.Ref,
+		WorkspaceHookEnvVarRepoCommitSHA: repo.CommitSHA,
+		WorkspaceHookEnvVarWorkspaceDir:  devEnv.WorkspaceDirPath,
+		WorkspaceHookEnvVarDevEnvName:    devEnv.Name,
+		WorkspaceHookEnvVarHookType:      string(hook.Type),
+	}
+
+	hookEnvAsList := []string{}
+
+	for envVarName, envVarValue := range hookEnv {
+		hookEnvAsList = append(hookEnvAsList, envVarName+"="+envVarValue)
+	}
+
+	return hookEnvAsList
+}
+
+// buildWorkspaceHookResultFilePath returns the path of the result
+// file of the passed hook ("<owner>/<name>/<type>.json"). Owners
+// may contain slashes (GitLab subgroups) so dirs are used to keep
+// the paths of different repositories from colliding.
+func buildWorkspaceHookResultFilePath(
+	devEnv *DevEnv,
+	repoOwner string,
+	repoName string,
+	hookType WorkspaceHookType,
+) string {
+
+	return filepath.Join(
+		devEnv.WorkspaceConfigHooksResultsDirPath,
+		filepath.FromSlash(repoOwner),
+		repoName,
+		string(hookType)+".json",
+	)
+}
+
+func saveWorkspaceHookResult(
+	devEnv *DevEnv,
+	repo WorkspaceConfigRepository,
+	hookResult *WorkspaceHookResult,
+) error {
+
+	hookResultFilePath := buildWorkspaceHookResultFilePath(
+		devEnv,
+		repo.Owner,
+		repo.Name,
+		hookResult.HookType,
+	)
+
+	err := os.MkdirAll(
+		filepath.Dir(hookResultFilePath),
+		os.FileMode(0755),
+	)
+
+	if err != nil {
+		return err
+	}
+
+	hookResultAsJSON, err := json.Marshal(hookResult)
+
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(
+		hookResultFilePath,
+		hookResultAsJSON,
+		os.FileMode(0644),
+	)
+}
+
+// LoadWorkspaceHookResult returns the result of the last
+// run of the passed hook (or nil if the hook was never run).
+func LoadWorkspaceHookResult(
+	devEnv *DevEnv,
+	repo WorkspaceConfigRepository,
+	hook WorkspaceConfigRepositoryHook,
+) (*WorkspaceHookResult, error) {
+
+	hookResultAsJSON, err := os.ReadFile(
+		buildWorkspaceHookResultFilePath(
+			devEnv,
+			repo.Owner,
+			repo.Name,
+			hook.Type,
+		),
+	)
+
+	if err != nil && os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var hookResult *WorkspaceHookResult
+	err = json.Unmarshal(hookResultAsJSON, &hookResult)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return hookResult, nil
+}
+
+func buildWorkspaceHookFailureMessage(
+	repo WorkspaceConfigRepository,
+	hook WorkspaceConfigRepositoryHook,
+	hookResult *WorkspaceHookResult,
+) string {
+
+	if hookResult.TimedOut {
+		return fmt.Sprintf(
+			"\"%s hook\" for \"%s/%s\" has timed out after %s",
+			hook.Type,
+			repo.Owner,
+			repo.Name,
+			hook.Timeout(),
+		)
+	}
+
+	return fmt.Sprintf(
+		"\"%s hook\" for \"%s/%s\" has returned a non-zero (%d) exit code",
+		hook.Type,
+		repo.Owner,
+		repo.Name,
+		hookResult.ExitCode,
+	)
+}
+
+// tailWriter keeps the last "size" bytes written
+type tailWriter struct {
+	size int
+	tail []byte
+}
+
+func newTailWriter(size int) *tailWriter {
+	return &tailWriter{
+		size: size,
+		tail: []byte{},
+	}
+}
+
+func (t *tailWriter) Write(p []byte) (int, error) {
+	t.tail = append(t.tail, p...)
+
+	if len(t.tail) > t.size {
+		t.tail = t.tail[len(t.tail)-t.size:]
+	}
+
+	return len(p), nil
+}
+
+func (t *tailWriter) String() string {
+	return string(t.tail)
+}
@@ -0,0 +1,366 @@
+package devenv
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/recode-sh/agent/constants"
+	"github.com/recode-sh/agent/proto"
+)
+
+// WorkspaceHooksOptions controls how the hooks
+// of the repositories are run during builds
+type WorkspaceHooksOptions struct {
+	// Max number of hooks run concurrently
+	Parallelism   int
+	FailurePolicy proto.HookFailurePolicy
+	// Failing hooks are reported without failing the build.
+	// Implies the "continue" failure policy.
+	ContinueOnError bool
+}
+
+func NewWorkspaceHooksOptions(
+	parallelism int,
+	failurePolicy proto.HookFailurePolicy,
+	continueOnError bool,
+) (WorkspaceHooksOptions, error) {
+
+	if parallelism < 0 {
+		return WorkspaceHooksOptions{}, fmt.Errorf(
+			"invalid hooks parallelism \"%d\"",
+			parallelism,
+		)
+	}
+
+	if parallelism == 0 {
+		parallelism = constants.DevEnvHooksDefaultParallelism
+	}
+
+	return WorkspaceHooksOptions{
+		Parallelism:     parallelism,
+		FailurePolicy:   failurePolicy,
+		ContinueOnError: continueOnError,
+	}, nil
+}
+
+func (w WorkspaceHooksOptions) failFast() bool {
+	return !w.ContinueOnError &&
+		w.FailurePolicy == proto.HookFailurePolicy_HOOK_FAILURE_POLICY_FAIL_FAST
+}
+
+// workspaceHookTask represents the run of the
+// hook of one repository (for one hook type)
+type workspaceHookTask struct {
+	repoFullName string
+	// Full names of the repositories whose
+	// hooks (of the same type) must be run first
+	// (see "resolveWorkspaceHookTasksDependencies")
+	dependsOn []string
+	run       func(ctx context.Context) error
+	// Called (if set) with the reason why the task was not run
+	skip func(reason string)
+}
+
+// runWorkspaceHookTasks runs the tasks concurrently (up to the
+// parallelism set in the options), each task being started once the
+// tasks it depends on have succeeded. Tasks that are ready at the
+// same time are started in the passed order.
+//
+// Once a task has failed, the running tasks are canceled in
+// "fail fast" mode. Otherwise, the tasks that don't depend on the
+// failing one are still run. In both cases, the tasks that depend on
+// a failing task are skipped and the first error is returned.
+//
+// "workspaceReposDependencies" contains the hooks dependencies of
+// all the repositories of the workspace (with or without tasks).
+func runWorkspaceHookTasks(
+	ctx context.Context,
+	tasks []*workspaceHookTask,
+	workspaceReposDependencies map[string][]string,
+	hooksOptions WorkspaceHooksOptions,
+) error {
+
+	err := ensureWorkspaceHookTasksDependenciesValid(
+		tasks,
+		workspaceReposDependencies,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	tasksCtx, cancelTasks := context.WithCancel(ctx)
+	defer cancelTasks()
+
+	tasksByRepo := map[string]*workspaceHookTask{}
+
+	for _, task := range tasks {
+		tasksByRepo[task.repoFullName] = task
+	}
+
+	tasksDependencies := resolveWorkspaceHookTasksDependencies(
+		tasks,
+		tasksByRepo,
+		workspaceReposDependencies,
+	)
+
+	pendingDependencies := map[*workspaceHookTask]int{}
+	dependentTasks := map[*workspaceHookTask][]*workspaceHookTask{}
+
+	for _, task := range tasks {
+		for _, dependencyTask := range tasksDependencies[task] {
+			pendingDependencies[task]++
+			dependentTasks[dependencyTask] = append(
+				dependentTasks[dependencyTask],
+				task,
+			)
+		}
+	}
+
+	var mutex sync.Mutex
+	var tasksErrors []error
+	allTasksDone := make(chan struct{})
+	readyTasks := []*workspaceHookTask{}
+	runningTasks := 0
+	startedTasks := map[*workspaceHookTask]bool{}
+	succeededTasks := map[*workspaceHookTask]bool{}
+
+	for _, task := range tasks {
+		if pendingDependencies[task] == 0 {
+			readyTasks = append(readyTasks, task)
+		}
+	}
+
+	var startReadyTasks func()
+	var finishTask func(task *workspaceHookTask, taskErr error)
+
+	// Called with the mutex held
+	startReadyTasks = func() {
+		for runningTasks < hooksOptions.Parallelism &&
+			len(readyTasks) > 0 &&
+			tasksCtx.Err() == nil {
+
+			task := readyTasks[0]
+			readyTasks = readyTasks[1:]
+			runningTasks++
+			startedTasks[task] = true
+
+			go func() {
+				taskErr := task.run(tasksCtx)
+
+				mutex.Lock()
+				defer mutex.Unlock()
+
+				runningTasks--
+				finishTask(task, taskErr)
+				startReadyTasks()
+
+				if runningTasks == 0 {
+					close(allTasksDone)
+				}
+			}()
+		}
+	}
+
+	// Called with the mutex held
+	finishTask = func(task *workspaceHookTask, taskErr error) {
+		// The tasks that depend on a failing
+		// task never become ready
+		if taskErr != nil {
+			tasksErrors = append(tasksErrors, taskErr)
+
+			if hooksOptions.failFast() {
+				cancelTasks()
+			}
+
+			return
+		}
+
+		succeededTasks[task] = true
+
+		for _, dependentTask := range dependentTasks[task] {
+			pendingDependencies[dependentTask]--
+
+			if pendingDependencies[dependentTask] == 0 {
+				readyTasks = append(readyTasks, dependentTask)
+			}
+		}
+	}
+
+	mutex.Lock()
+	startReadyTasks()
+	noTaskStarted := runningTasks == 0
+	mutex.Unlock()
+
+	if !noTaskStarted {
+		<-allTasksDone
+	}
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	for _, task := range tasks {
+		if startedTasks[task] || task.skip == nil {
+			continue
+		}
+
+		task.skip(buildWorkspaceHookTaskSkipReason(
+			tasksDependencies[task],
+			succeededTasks,
+		))
+	}
+
+	if len(tasksErrors) == 0 {
+		return nil
+	}
+
+	// The errors of the tasks canceled
+	// after the first failure are not relevant
+	if len(tasksErrors) == 1 || hooksOptions.failFast() {
+		return tasksErrors[0]
+	}
+
+	return fmt.Errorf(
+		"%w (%d other hooks have failed)",
+		tasksErrors[0],
+		len(tasksErrors)-1,
+	)
+}
+
+// resolveWorkspaceHookTasksDependencies returns the tasks that must
+// succeed before each task. The repositories without task (eg: without
+// hook of the current type) are traversed: if A depends on B (without
+// task) which depends on C, the task of A depends on the task of C.
+func resolveWorkspaceHookTasksDependencies(
+	tasks []*workspaceHookTask,
+	tasksByRepo map[string]*workspaceHookTask,
+	workspaceReposDependencies map[string][]string,
+) map[*workspaceHookTask][]*workspaceHookTask {
+
+	tasksDependencies := map[*workspaceHookTask][]*workspaceHookTask{}
+
+	for _, task := range tasks {
+		visitedRepos := map[string]bool{}
+		dependencyTasks := []*workspaceHookTask{}
+
+		var visit func(dependencies []string)
+
+		visit = func(dependencies []string) {
+			for _, dependency := range dependencies {
+				if visitedRepos[dependency] {
+					continue
+				}
+
+				visitedRepos[dependency] = true
+
+				// The task waits for its own dependencies
+				if dependencyTask, hasTask := tasksByRepo[dependency]; hasTask {
+					dependencyTasks = append(dependencyTasks, dependencyTask)
+					continue
+				}
+
+				visit(workspaceReposDependencies[dependency])
+			}
+		}
+
+		visit(task.dependsOn)
+
+		tasksDependencies[task] = dependencyTasks
+	}
+
+	return tasksDependencies
+}
+
+func buildWorkspaceHookTaskSkipReason(
+	dependencyTasks []*workspaceHookTask,
+	succeededTasks map[*workspaceHookTask]bool,
+) string {
+
+	for _, dependencyTask := range dependencyTasks {
+		if !succeededTasks[dependencyTask] {
+			return fmt.Sprintf(
+				"the hook of \"%s\" has not succeeded",
+				dependencyTask.repoFullName,
+			)
+		}
+	}
+
+	return "another hook has failed"
+}
+
+// ensureWorkspaceHookTasksDependenciesValid ensures that the
+// dependencies of the tasks (direct or through other repositories)
+// are in the workspace and that there are no cycles
+func ensureWorkspaceHookTasksDependenciesValid(
+	tasks []*workspaceHookTask,
+	workspaceReposDependencies map[string][]string,
+) error {
+
+	dependencies := map[string][]string{}
+
+	for repoFullName, repoDependencies := range workspaceReposDependencies {
+		dependencies[repoFullName] = repoDependencies
+	}
+
+	for _, task := range tasks {
+		dependencies[task.repoFullName] = task.dependsOn
+	}
+
+	const (
+		notVisited = iota
+		visiting
+		visited
+	)
+
+	visitStates := map[string]int{}
+
+	var visit func(repoFullName string) error
+
+	visit = func(repoFullName string) error {
+		switch visitStates[repoFullName] {
+		case visiting:
+			return newBuildStepError(
+				proto.ErrorCode_ERROR_CODE_HOOK_FAILED,
+				fmt.Errorf(
+					"circular hooks dependency involving \"%s\"",
+					repoFullName,
+				),
+			)
+		case visited:
+			return nil
+		}
+
+		visitStates[repoFullName] = visiting
+
+		for _, dependency := range dependencies[repoFullName] {
+			if _, isInWorkspace := workspaceReposDependencies[dependency]; !isInWorkspace {
+				return newBuildStepError(
+					proto.ErrorCode_ERROR_CODE_HOOK_FAILED,
+					fmt.Errorf(
+						"the hooks of \"%s\" depend on \"%s\" which is not in the workspace",
+						repoFullName,
+						dependency,
+					),
+				)
+			}
+
+			if err := visit(dependency); err != nil {
+				return err
+			}
+		}
+
+		visitStates[repoFullName] = visited
+
+		return nil
+	}
+
+	for _, task := range tasks {
+		if err := visit(task.repoFullName); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,497 @@
+package devenv
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/recode-sh/agent/proto"
+)
+
+type testWorkspaceHookTasksRecorder struct {
+	mutex        sync.Mutex
+	startedTasks []string
+	running      int
+	maxRunning   int
+}
+
+func (r *testWorkspaceHookTasksRecorder) newTask(
+	repoFullName string,
+	dependsOn []string,
+	taskErr error,
+) *workspaceHookTask {
+
+	return &workspaceHookTask{
+		repoFullName: repoFullName,
+		dependsOn:    dependsOn,
+		run: func(ctx context.Context) error {
+			r.mutex.Lock()
+			r.startedTasks = append(r.startedTasks, repoFullName)
+			r.running++
+
+			if r.running > r.maxRunning {
+				r.maxRunning = r.running
+			}
+			r.mutex.Unlock()
+
+			defer func() {
+				r.mutex.Lock()
+				r.running--
+				r.mutex.Unlock()
+			}()
+
+			select {
+			case <-time.After(20 * time.Millisecond):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+
+			return taskErr
+		},
+	}
+}
+
+func (r *testWorkspaceHookTasksRecorder) startedIndex(repoFullName string) int {
+	for taskIndex, startedTask := range r.startedTasks {
+		if startedTask == repoFullName {
+			return taskIndex
+		}
+	}
+
+	return -1
+}
+
+func TestRunWorkspaceHookTasks(t *testing.T) {
+	workspaceReposDependencies := map[string][]string{
+		"acme/shared": nil,
+		"acme/api":    nil,
+		"acme/web":    nil,
+		"acme/docs":   nil,
+	}
+
+	errHook := errors.New("hook has failed")
+
+	testCases := []struct {
+		test                 string
+		parallelism          int
+		failurePolicy        proto.HookFailurePolicy
+		tasksDependencies    map[string][]string
+		tasksErrors          map[string]error
+		expectedStartedTasks []string
+		expectedMaxRunning   int
+		expectError          bool
+	}{
+		{
+			test:        "dependencies_are_run_first",
+			parallelism: 4,
+			tasksDependencies: map[string][]string{
+				"acme/shared": {},
+				"acme/api":    {"acme/shared"},
+				"acme/web":    {"acme/api"},
+				"acme/docs":   {},
+			},
+			expectedStartedTasks: []string{"acme/shared", "acme/docs", "acme/api", "acme/web"},
+			expectedMaxRunning:   2,
+		},
+
+		{
+			test:        "parallelism_is_honored",
+			parallelism: 1,
+			tasksDependencies: map[string][]string{
+				"acme/shared": {},
+				"acme/api":    {},
+				"acme/web":    {},
+			},
+			expectedStartedTasks: []string{"acme/shared", "acme/api", "acme/web"},
+			expectedMaxRunning:   1,
+		},
+
+		{
+			test:          "dependents_of_failing_task_are_skipped",
+			parallelism:   1,
+			failurePolicy: proto.HookFailurePolicy_HOOK_FAILURE_POLICY_CONTINUE,
+			tasksDependencies: map[string][]string{
+				"acme/shared": {},
+				"acme/api":    {"acme/shared"},
+				"acme/docs":   {},
+			},
+			tasksErrors: map[string]error{
+				"acme/shared": errHook,
+			},
+			expectedStartedTasks: []string{"acme/shared", "acme/docs"},
+			expectedMaxRunning:   1,
+			expectError:          true,
+		},
+
+		{
+			test:          "fail_fast_stops_next_tasks",
+			parallelism:   1,
+			failurePolicy: proto.HookFailurePolicy_HOOK_FAILURE_POLICY_FAIL_FAST,
+			tasksDependencies: map[string][]string{
+				"acme/shared": {},
+				"acme/docs":   {},
+			},
+			tasksErrors: map[string]error{
+				"acme/shared": errHook,
+			},
+			expectedStartedTasks: []string{"acme/shared"},
+			expectedMaxRunning:   1,
+			expectError:          true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.test, func(t *testing.T) {
+			recorder := &testWorkspaceHookTasksRecorder{}
+			tasks := []*workspaceHookTask{}
+
+			// Keep a stable order
+			for _, repoFullName := range []string{
+				"acme/shared",
+				"acme/api",
+				"acme/web",
+				"acme/docs",
+			} {
+
+				dependsOn, ok := tc.tasksDependencies[repoFullName]
+
+				if !ok {
+					continue
+				}
+
+				tasks = append(tasks, recorder.newTask(
+					repoFullName,
+					dependsOn,
+					tc.tasksErrors[repoFullName],
+				))
+			}
+
+			err := runWorkspaceHookTasks(
+				context.Background(),
+				tasks,
+				workspaceReposDependencies,
+				WorkspaceHooksOptions{
+					Parallelism:   tc.parallelism,
+					FailurePolicy: tc.failurePolicy,
+				},
+			)
+
+			if tc.expectError && !errors.Is(err, errHook) {
+				t.Fatalf("expected hook error, got '%+v'", err)
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error, got '%+v'", err)
+			}
+
+			if len(recorder.startedTasks) != len(tc.expectedStartedTasks) {
+				t.Fatalf(
+					"expected started tasks to equal '%v', got '%v'",
+					tc.expectedStartedTasks,
+					recorder.startedTasks,
+				)
+			}
+
+			// Tasks ready at the same time may be started in any order
+			// when run concurrently so only dependencies are checked
+			for repoFullName, dependsOn := range tc.tasksDependencies {
+				for _, dependency := range dependsOn {
+					if recorder.startedIndex(repoFullName) == -1 {
+						continue
+					}
+
+					if recorder.startedIndex(dependency) > recorder.startedIndex(repoFullName) {
+						t.Fatalf(
+							"expected '%s' to be started before '%s', got '%v'",
+							dependency,
+							repoFullName,
+							recorder.startedTasks,
+						)
+					}
+				}
+			}
+
+			if tc.parallelism == 1 {
+				for taskIndex, expectedTask := range tc.expectedStartedTasks {
+					if recorder.startedTasks[taskIndex] != expectedTask {
+						t.Fatalf(
+							"expected started tasks to equal '%v', got '%v'",
+							tc.expectedStartedTasks,
+							recorder.startedTasks,
+						)
+					}
+				}
+			}
+
+			if recorder.maxRunning != tc.expectedMaxRunning {
+				t.Fatalf(
+					"expected '%d' max running tasks, got '%d'",
+					tc.expectedMaxRunning,
+					recorder.maxRunning,
+				)
+			}
+		})
+	}
+}
+
+func TestRunWorkspaceHookTasksWithInvalidDependencies(t *testing.T) {
+	workspaceReposDependencies := map[string][]string{
+		"acme/shared": nil,
+		"acme/api":    nil,
+		// Without hooks
+		"acme/lib":   {"acme/shared"},
+		"acme/tools": {"acme/unknown"},
+	}
+
+	testCases := []struct {
+		test  string
+		tasks []*workspaceHookTask
+	}{
+		{
+			test: "dependency_not_in_workspace",
+			tasks: []*workspaceHookTask{
+				{repoFullName: "acme/api", dependsOn: []string{"acme/unknown"}},
+			},
+		},
+
+		{
+			test: "circular_dependencies",
+			tasks: []*workspaceHookTask{
+				{repoFullName: "acme/shared", dependsOn: []string{"acme/api"}},
+				{repoFullName: "acme/api", dependsOn: []string{"acme/shared"}},
+			},
+		},
+
+		{
+			test: "dependency_not_in_workspace_through_repo_without_hooks",
+			tasks: []*workspaceHookTask{
+				{repoFullName: "acme/api", dependsOn: []string{"acme/tools"}},
+			},
+		},
+
+		{
+			test: "circular_dependencies_through_repo_without_hooks",
+			tasks: []*workspaceHookTask{
+				{repoFullName: "acme/shared", dependsOn: []string{"acme/lib"}},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.test, func(t *testing.T) {
+			err := runWorkspaceHookTasks(
+				context.Background(),
+				tc.tasks,
+				workspaceReposDependencies,
+				WorkspaceHooksOptions{Parallelism: 1},
+			)
+
+			if err == nil {
+				t.Fatalf("expected error, got nothing")
+			}
+
+			if BuildStepErrorCode(err) != proto.ErrorCode_ERROR_CODE_HOOK_FAILED {
+				t.Fatalf(
+					"expected error code '%s', got '%s'",
+					proto.ErrorCode_ERROR_CODE_HOOK_FAILED,
+					BuildStepErrorCode(err),
+				)
+			}
+		})
+	}
+}
+
+func TestRunWorkspaceHookTasksWithTransitiveDependencies(t *testing.T) {
+	// "acme/api" -> "acme/lib" (without hooks) -> "acme/shared"
+	workspaceReposDependencies := map[string][]string{
+		"acme/shared": nil,
+		"acme/lib":    {"acme/shared"},
+		"acme/api":    {"acme/lib"},
+		"acme/docs":   nil,
+	}
+
+	recorder := &testWorkspaceHookTasksRecorder{}
+
+	tasks := []*workspaceHookTask{
+		recorder.newTask("acme/shared", nil, nil),
+		recorder.newTask("acme/api", []string{"acme/lib"}, nil),
+		recorder.newTask("acme/docs", nil, nil),
+	}
+
+	err := runWorkspaceHookTasks(
+		context.Background(),
+		tasks,
+		workspaceReposDependencies,
+		WorkspaceHooksOptions{Parallelism: 4},
+	)
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	// Started once "acme/shared" has succeeded
+	if recorder.startedIndex("acme/api") != 2 {
+		t.Fatalf(
+			"expected 'acme/api' to be started last, got '%v'",
+			recorder.startedTasks,
+		)
+	}
+
+	if recorder.maxRunning != 2 {
+		t.Fatalf("expected max running tasks '2', got '%d'", recorder.maxRunning)
+	}
+}
+
+func TestLookupHooksDependenciesInDockerfileLabels(t *testing.T) {
+	testCases := []struct {
+		test                 string
+		dockerfile           string
+		expectedDependencies []string
+	}{
+		{
+			test:                 "no_label",
+			dockerfile:           "FROM recode-user-config-image\n",
+			expectedDependencies: []string{},
+		},
+
+		{
+			test: "names_with_and_without_owner",
+			dockerfile: "FROM recode-user-config-image\n" +
+				"LABEL sh.recode.hooks.depends_on=\"shared, other/lib\"\n",
+			expectedDependencies: []string{"acme/shared", "other/lib"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.test, func(t *testing.T) {
+			dockerfilePath := filepath.Join(t.TempDir(), "dev_env.Dockerfile")
+
+			err := os.WriteFile(dockerfilePath, []byte(tc.dockerfile), os.FileMode(0644))
+
+			if err != nil {
+				t.Fatalf("expected no error, got '%+v'", err)
+			}
+
+			dependencies, err := lookupHooksDependenciesInDockerfileLabels(
+				dockerfilePath,
+				"acme",
+			)
+
+			if err != nil {
+				t.Fatalf("expected no error, got '%+v'", err)
+			}
+
+			if strings.Join(dependencies, ",") != strings.Join(tc.expectedDependencies, ",") {
+				t.Fatalf(
+					"expected dependencies to equal '%v', got '%v'",
+					tc.expectedDependencies,
+					dependencies,
+				)
+			}
+		})
+	}
+}
+
+func TestLinePrefixWriter(t *testing.T) {
+	var output bytes.Buffer
+	var writes []string
+
+	writer := newLinePrefixWriter(
+		writerFunc(func(p []byte) (int, error) {
+			writes = append(writes, string(p))
+			return output.Write(p)
+		}),
+		"[acme/api] ",
+	)
+
+	writer.Write([]byte("first li"))
+	writer.Write([]byte("ne\nsecond line\nlast"))
+
+	err := writer.Flush()
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	expectedOutput := "[acme/api] first line\n[acme/api] second line\n[acme/api] last"
+
+	if output.String() != expectedOutput {
+		t.Fatalf("expected '%s', got '%s'", expectedOutput, output.String())
+	}
+
+	// Each line must be written at once
+	if len(writes) != 3 {
+		t.Fatalf("expected '3' writes, got '%d'", len(writes))
+	}
+}
+
+type writerFunc func(p []byte) (int, error)
+
+func (w writerFunc) Write(p []byte) (int, error) {
+	return w(p)
+}
+
+func TestRunWorkspaceHookTasksSkipReasons(t *testing.T) {
+	workspaceReposDependencies := map[string][]string{
+		"acme/shared": nil,
+		"acme/api":    nil,
+		"acme/web":    nil,
+	}
+
+	skipReasons := map[string]string{}
+	recorder := &testWorkspaceHookTasksRecorder{}
+
+	tasks := []*workspaceHookTask{
+		recorder.newTask("acme/shared", []string{}, errors.New("hook has failed")),
+		recorder.newTask("acme/api", []string{"acme/shared"}, nil),
+		recorder.newTask("acme/web", []string{"acme/api"}, nil),
+	}
+
+	for _, task := range tasks {
+		repoFullName := task.repoFullName
+
+		task.skip = func(reason string) {
+			skipReasons[repoFullName] = reason
+		}
+	}
+
+	err := runWorkspaceHookTasks(
+		context.Background(),
+		tasks,
+		workspaceReposDependencies,
+		WorkspaceHooksOptions{
+			Parallelism:     2,
+			ContinueOnError: true,
+		},
+	)
+
+	if err == nil {
+		t.Fatalf("expected error, got nothing")
+	}
+
+	expectedSkipReasons := map[string]string{
+		"acme/api": "the hook of \"acme/shared\" has not succeeded",
+		"acme/web": "the hook of \"acme/api\" has not succeeded",
+	}
+
+	if len(skipReasons) != len(expectedSkipReasons) {
+		t.Fatalf("expected skip reasons to equal '%v', got '%v'", expectedSkipReasons, skipReasons)
+	}
+
+	for repoFullName, expectedSkipReason := range expectedSkipReasons {
+		if skipReasons[repoFullName] != expectedSkipReason {
+			t.Fatalf(
+				"expected '%s' skip reason to equal '%s', got '%s'",
+				repoFullName,
+				expectedSkipReason,
+				skipReasons[repoFullName],
+			)
+		}
+	}
+}
@@ -0,0 +1,453 @@
+package devenv
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/recode-sh/agent/constants"
+	"github.com/recode-sh/agent/proto"
+	"github.com/recode-sh/recode/entities"
+)
+
+func TestPrepareWorkspaceWithLifecycleHooks(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	gitReposBaseDirPath := t.TempDir()
+
+	createTestGitRepo(
+		t,
+		filepath.Join(gitReposBaseDirPath, "acme", "config"),
+		map[string]string{
+			entities.DevEnvUserConfigDockerfileFileName: "FROM recodesh/base-dev-env:latest\n",
+		},
+	)
+
+	repoHooksDirPath := filepath.Join(
+		entities.DevEnvRepositoryConfigDirectory,
+		entities.DevEnvRepositoryConfigHooksDirectory,
+	)
+
+	createTestGitRepo(
+		t,
+		filepath.Join(gitReposBaseDirPath, "acme", "api"),
+		map[string]string{
+			filepath.Join(
+				entities.DevEnvRepositoryConfigDirectory,
+				entities.DevEnvRepositoryDockerfileFileName,
+			): "FROM recode-user-config-image\n",
+			filepath.Join(
+				repoHooksDirPath,
+				constants.DevEnvRepositoryStopHookFileName,
+			): "#!/bin/bash\n",
+			filepath.Join(
+				repoHooksDirPath,
+				entities.DevEnvRepositoryInitHookFileName,
+			): "#!/bin/bash\n",
+			filepath.Join(
+				repoHooksDirPath,
+				constants.DevEnvRepositoryStartHookFileName,
+			): "#!/bin/bash\n",
+		},
+	)
+
+	gitProvider, err := NewGitProvider("file://" + gitReposBaseDirPath)
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	devEnv := newTestDevEnv(t)
+	workspaceConfig := NewWorkspaceConfig()
+
+	preparedWorkspaceMetadata, err := PrepareWorkspace(
+		context.Background(),
+		&testBuildAndStartDevEnvStream{},
+		devEnv,
+		NewGitRepository(gitProvider, "acme", "config"),
+		NewGitRepository(gitProvider, "acme", "api"),
+		workspaceConfig,
+		WorkspaceSyncOptions{},
+		ReposCloneOptions{},
+		proto.WorkspaceLayout_WORKSPACE_LAYOUT_NAME,
+	)
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	defer os.RemoveAll(preparedWorkspaceMetadata.TmpUserConfigRepoDirPath)
+	defer os.RemoveAll(preparedWorkspaceMetadata.TmpDevEnvRepoDirPath)
+
+	if len(workspaceConfig.Repositories) != 1 {
+		t.Fatalf(
+			"expected 1 repository in workspace, got '%d'",
+			len(workspaceConfig.Repositories),
+		)
+	}
+
+	// Ordered by lifecycle
+	expectedHookTypes := []WorkspaceHookType{
+		WorkspaceHookTypeInit,
+		WorkspaceHookTypeStart,
+		WorkspaceHookTypeStop,
+	}
+
+	hooks := workspaceConfig.Repositories[0].Hooks
+
+	if len(hooks) != len(expectedHookTypes) {
+		t.Fatalf(
+			"expected '%d' hooks, got '%d'",
+			len(expectedHookTypes),
+			len(hooks),
+		)
+	}
+
+	for hookIndex, hook := range hooks {
+		if hook.Type != expectedHookTypes[hookIndex] {
+			t.Fatalf(
+				"expected hook type to equal '%s', got '%s'",
+				expectedHookTypes[hookIndex],
+				hook.Type,
+			)
+		}
+
+		if filepath.Dir(hook.ScriptFilePath) != devEnv.WorkspaceConfigHooksDirPath {
+			t.Fatalf(
+				"expected hook to be installed in '%s', got '%s'",
+				devEnv.WorkspaceConfigHooksDirPath,
+				hook.ScriptFilePath,
+			)
+		}
+	}
+}
+
+func TestLoadWorkspaceConfigWithUntypedHooks(t *testing.T) {
+	workspaceConfigFilePath := filepath.Join(t.TempDir(), "recode.workspace")
+
+	err := os.WriteFile(
+		workspaceConfigFilePath,
+		[]byte(`{"repositories":[{"owner":"acme","name":"api","hooks":[{"script_file_path":"/hooks/init"}]}]}`),
+		os.FileMode(0644),
+	)
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	workspaceConfig, err := LoadWorkspaceConfig(workspaceConfigFilePath)
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	hookType := workspaceConfig.Repositories[0].Hooks[0].Type
+
+	if hookType != WorkspaceHookTypeInit {
+		t.Fatalf(
+			"expected hook type to equal '%s', got '%s'",
+			WorkspaceHookTypeInit,
+			hookType,
+		)
+	}
+}
+
+func TestWorkspaceHookResultSaveAndLoad(t *testing.T) {
+	devEnv := newTestDevEnv(t)
+
+	repo := WorkspaceConfigRepository{
+		Owner: "acme/backend",
+		Name:  "api",
+	}
+
+	hook := WorkspaceConfigRepositoryHook{
+		Type: WorkspaceHookTypeStart,
+	}
+
+	hookResult, err := LoadWorkspaceHookResult(devEnv, repo, hook)
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	if hookResult != nil {
+		t.Fatalf("expected no result, got '%+v'", hookResult)
+	}
+
+	outputTail := newTailWriter(4)
+	outputTail.Write([]byte("Installing..."))
+	outputTail.Write([]byte("done"))
+
+	err = saveWorkspaceHookResult(devEnv, repo, &WorkspaceHookResult{
+		RepoOwner:  repo.Owner,
+		RepoName:   repo.Name,
+		HookType:   hook.Type,
+		ExitCode:   constants.DevEnvHookTimeoutExitCode,
+		TimedOut:   true,
+		OutputTail: outputTail.String(),
+	})
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	hookResult, err = LoadWorkspaceHookResult(devEnv, repo, hook)
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	if hookResult == nil || !hookResult.TimedOut {
+		t.Fatalf("expected timed out result, got '%+v'", hookResult)
+	}
+
+	if hookResult.OutputTail != "done" {
+		t.Fatalf(
+			"expected output tail to equal 'done', got '%s'",
+			hookResult.OutputTail,
+		)
+	}
+
+	// Must not share the result file of "acme/backend/api"
+	otherRepo := WorkspaceConfigRepository{
+		Owner: "acme",
+		Name:  "backend-api",
+	}
+
+	hookResult, err = LoadWorkspaceHookResult(devEnv, otherRepo, hook)
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	if hookResult != nil {
+		t.Fatalf("expected no result, got '%+v'", hookResult)
+	}
+}
+
+func TestLookupHookTimeoutsInDockerfileLabels(t *testing.T) {
+	testCases := []struct {
+		test             string
+		dockerfile       string
+		expectedTimeouts map[WorkspaceHookType]time.Duration
+		expectError      bool
+	}{
+		{
+			test:             "no_labels",
+			dockerfile:       "FROM recode-user-config-image\n",
+			expectedTimeouts: map[WorkspaceHookType]time.Duration{},
+		},
+
+		{
+			test: "valid_labels",
+			dockerfile: "FROM recode-user-config-image\n" +
+				"LABEL sh.recode.hooks.init.timeout=\"20m\"\n" +
+				"LABEL sh.recode.hooks.stop.timeout=\"30s\"\n",
+			expectedTimeouts: map[WorkspaceHookType]time.Duration{
+				WorkspaceHookTypeInit: 20 * time.Minute,
+				WorkspaceHookTypeStop: 30 * time.Second,
+			},
+		},
+
+		{
+			test: "invalid_label",
+			dockerfile: "FROM recode-user-config-image\n" +
+				"LABEL sh.recode.hooks.start.timeout=\"ten minutes\"\n",
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.test, func(t *testing.T) {
+			dockerfilePath := filepath.Join(t.TempDir(), "dev_env.Dockerfile")
+
+			err := os.WriteFile(dockerfilePath, []byte(tc.dockerfile), os.FileMode(0644))
+
+			if err != nil {
+				t.Fatalf("expected no error, got '%+v'", err)
+			}
+
+			hookTimeouts, err := lookupHookTimeoutsInDockerfileLabels(dockerfilePath)
+
+			if tc.expectError {
+				if err == nil {
+					t.Fatalf("expected error, got nothing")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error, got '%+v'", err)
+			}
+
+			if len(hookTimeouts) != len(tc.expectedTimeouts) {
+				t.Fatalf(
+					"expected '%d' timeouts, got '%d'",
+					len(tc.expectedTimeouts),
+					len(hookTimeouts),
+				)
+			}
+
+			for hookType, expectedTimeout := range tc.expectedTimeouts {
+				if hookTimeouts[hookType] != expectedTimeout {
+					t.Fatalf(
+						"expected '%s' timeout to equal '%s', got '%s'",
+						hookType,
+						expectedTimeout,
+						hookTimeouts[hookType],
+					)
+				}
+			}
+		})
+	}
+}
+
+func TestWorkspaceInitHookMarker(t *testing.T) {
+	repo := WorkspaceConfigRepository{
+		Owner:       "acme",
+		Name:        "api",
+		RootDirPath: t.TempDir(),
+		CommitSHA:   "3f2a1bc",
+	}
+
+	// Not a git clone
+	err := markWorkspaceInitHookRun(repo)
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	if hasWorkspaceInitHookRun(repo) {
+		t.Fatalf("expected init hook not run, got run")
+	}
+
+	err = os.Mkdir(filepath.Join(repo.RootDirPath, ".git"), os.FileMode(0755))
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	if hasWorkspaceInitHookRun(repo) {
+		t.Fatalf("expected init hook not run, got run")
+	}
+
+	err = markWorkspaceInitHookRun(repo)
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	if !hasWorkspaceInitHookRun(repo) {
+		t.Fatalf("expected init hook run, got not run")
+	}
+}
+
+func TestSaveWorkspaceHooksRunStateKeepsConcurrentUpdates(t *testing.T) {
+	devEnv := newTestDevEnv(t)
+
+	// Saved by a build while the hooks were running
+	err := SaveWorkspaceConfigAsFile(devEnv.WorkspaceConfigFilePath, &WorkspaceConfig{
+		Repositories: []WorkspaceConfigRepository{
+			{
+				Owner:     "acme",
+				Name:      "api",
+				CommitSHA: "new-commit",
+				Hooks: []WorkspaceConfigRepositoryHook{
+					{Type: WorkspaceHookTypeStart},
+					{Type: WorkspaceHookTypeAttach},
+				},
+			},
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	err = saveWorkspaceHooksRunState(
+		devEnv,
+		WorkspaceHookTypeAttach,
+		"2022-04-01T10:00:00Z",
+		map[string]int{"acme/api": 1},
+	)
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	workspaceConfig, err := LoadWorkspaceConfig(devEnv.WorkspaceConfigFilePath)
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	repo := workspaceConfig.Repositories[0]
+
+	if repo.CommitSHA != "new-commit" {
+		t.Fatalf("expected commit 'new-commit', got '%s'", repo.CommitSHA)
+	}
+
+	if repo.Hooks[0].LastExitCode != nil {
+		t.Fatalf("expected no start hook exit code, got '%d'", *repo.Hooks[0].LastExitCode)
+	}
+
+	if repo.Hooks[1].LastExitCode == nil || *repo.Hooks[1].LastExitCode != 1 {
+		t.Fatalf("expected attach hook exit code '1', got '%v'", repo.Hooks[1].LastExitCode)
+	}
+
+	runFor := workspaceConfig.HooksRunForContainerStartedAt[WorkspaceHookTypeAttach]
+
+	if runFor != "2022-04-01T10:00:00Z" {
+		t.Fatalf("expected hooks run for '2022-04-01T10:00:00Z', got '%s'", runFor)
+	}
+}
+
+func TestRunWorkspaceHooksBeforeSessionDoesNotWait(t *testing.T) {
+	devEnv := newTestDevEnv(t)
+
+	err := LockWorkspaceHooks(context.Background(), devEnv)
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	defer UnlockWorkspaceHooks(devEnv)
+
+	err = RunWorkspaceHooksBeforeSession(
+		context.Background(),
+		nil,
+		devEnv,
+		io.Discard,
+	)
+
+	if !errors.Is(err, ErrWorkspaceHooksRunning) {
+		t.Fatalf("expected hooks running error, got '%+v'", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err = LockWorkspaceHooks(ctx, devEnv)
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected canceled error, got '%+v'", err)
+	}
+
+	// The hooks of other development environments are not locked
+	otherDevEnv := newTestDevEnv(t)
+	otherDevEnv.Name = "other"
+
+	if !tryLockWorkspaceHooks(otherDevEnv) {
+		t.Fatalf("expected hooks of '%s' not locked, got locked", otherDevEnv.Name)
+	}
+
+	UnlockWorkspaceHooks(otherDevEnv)
+}
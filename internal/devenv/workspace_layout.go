@@ -0,0 +1,97 @@
+package devenv
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/recode-sh/agent/proto"
+)
+
+// splitWorkspacePath splits repositories passed
+// as "<repository>=<path in workspace>"
+func splitWorkspacePath(repoRef string) (string, string) {
+	workspacePathIndex := strings.LastIndex(repoRef, "=")
+
+	if workspacePathIndex == -1 {
+		return repoRef, ""
+	}
+
+	return repoRef[:workspacePathIndex], repoRef[workspacePathIndex+1:]
+}
+
+// validateWorkspacePath ensures that the passed
+// path is relative to (and contained in) the workspace
+func validateWorkspacePath(workspacePath string) error {
+	cleanedPath := filepath.Clean(workspacePath)
+
+	if len(workspacePath) == 0 ||
+		filepath.IsAbs(workspacePath) ||
+		cleanedPath == "." ||
+		cleanedPath == ".." ||
+		strings.HasPrefix(cleanedPath, "../") {
+
+		return fmt.Errorf(
+			"invalid workspace path \"%s\" (must be relative to the workspace)",
+			workspacePath,
+		)
+	}
+
+	return nil
+}
+
+func buildRepoRelDirPathInWorkspace(
+	repo *GitRepository,
+	layout proto.WorkspaceLayout,
+) string {
+
+	if len(repo.WorkspacePath) > 0 {
+		return filepath.Clean(repo.WorkspacePath)
+	}
+
+	if layout == proto.WorkspaceLayout_WORKSPACE_LAYOUT_OWNER_AND_NAME {
+		return filepath.Join(repo.Owner, repo.Name)
+	}
+
+	return repo.Name
+}
+
+// buildRepoRelDirPathsInWorkspace returns the paths (relative to the
+// workspace) of the passed repositories. An error is returned if
+// multiple repositories would be cloned in the same directory
+// (or in the directory of another repository).
+func buildRepoRelDirPathsInWorkspace(
+	repos []*GitRepository,
+	layout proto.WorkspaceLayout,
+) ([]string, error) {
+
+	repoRelDirPaths := []string{}
+
+	for repoIndex, repo := range repos {
+		repoRelDirPath := buildRepoRelDirPathInWorkspace(repo, layout)
+
+		for otherRepoIndex, otherRepoRelDirPath := range repoRelDirPaths {
+			if repoRelDirPath != otherRepoRelDirPath &&
+				!strings.HasPrefix(repoRelDirPath, otherRepoRelDirPath+"/") &&
+				!strings.HasPrefix(otherRepoRelDirPath, repoRelDirPath+"/") {
+
+				continue
+			}
+
+			return nil, newBuildStepError(
+				proto.ErrorCode_ERROR_CODE_WORKSPACE_LAYOUT_CONFLICT,
+				fmt.Errorf(
+					"the repositories \"%s\" and \"%s\" would be cloned in conflicting directories (\"%s\" and \"%s\"). Use the \"owner/name\" workspace layout or set an explicit path using the \"owner/name=path\" syntax",
+					repos[otherRepoIndex].FullName(),
+					repos[repoIndex].FullName(),
+					otherRepoRelDirPath,
+					repoRelDirPath,
+				),
+			)
+		}
+
+		repoRelDirPaths = append(repoRelDirPaths, repoRelDirPath)
+	}
+
+	return repoRelDirPaths, nil
+}
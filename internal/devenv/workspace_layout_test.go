@@ -0,0 +1,118 @@
+package devenv
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/recode-sh/agent/proto"
+)
+
+func TestBuildRepoRelDirPathsInWorkspace(t *testing.T) {
+	gitProvider, err := NewGitProvider("")
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	newRepo := func(owner, name, workspacePath string) *GitRepository {
+		repo := NewGitRepository(gitProvider, owner, name)
+		repo.WorkspacePath = workspacePath
+
+		return repo
+	}
+
+	testCases := []struct {
+		test                    string
+		repos                   []*GitRepository
+		layout                  proto.WorkspaceLayout
+		expectedRepoRelDirPaths []string
+		expectError             bool
+	}{
+		{
+			test: "name_layout",
+			repos: []*GitRepository{
+				newRepo("acme", "api", ""),
+				newRepo("acme", "web", ""),
+			},
+			layout:                  proto.WorkspaceLayout_WORKSPACE_LAYOUT_NAME,
+			expectedRepoRelDirPaths: []string{"api", "web"},
+			expectError:             false,
+		},
+
+		{
+			test: "name_layout_collision",
+			repos: []*GitRepository{
+				newRepo("acme", "api", ""),
+				newRepo("other", "api", ""),
+			},
+			layout:      proto.WorkspaceLayout_WORKSPACE_LAYOUT_NAME,
+			expectError: true,
+		},
+
+		{
+			test: "owner_and_name_layout",
+			repos: []*GitRepository{
+				newRepo("acme", "api", ""),
+				newRepo("other", "api", ""),
+			},
+			layout:                  proto.WorkspaceLayout_WORKSPACE_LAYOUT_OWNER_AND_NAME,
+			expectedRepoRelDirPaths: []string{"acme/api", "other/api"},
+			expectError:             false,
+		},
+
+		{
+			test: "explicit_path",
+			repos: []*GitRepository{
+				newRepo("acme", "api", ""),
+				newRepo("other", "api", "other-api"),
+			},
+			layout:                  proto.WorkspaceLayout_WORKSPACE_LAYOUT_NAME,
+			expectedRepoRelDirPaths: []string{"api", "other-api"},
+			expectError:             false,
+		},
+
+		{
+			test: "nested_collision",
+			repos: []*GitRepository{
+				newRepo("acme", "api", ""),
+				newRepo("acme", "docs", "api/docs"),
+			},
+			layout:      proto.WorkspaceLayout_WORKSPACE_LAYOUT_NAME,
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.test, func(t *testing.T) {
+			repoRelDirPaths, err := buildRepoRelDirPathsInWorkspace(
+				tc.repos,
+				tc.layout,
+			)
+
+			if tc.expectError {
+				var buildStepErr *BuildStepError
+
+				if !errors.As(err, &buildStepErr) ||
+					buildStepErr.Code != proto.ErrorCode_ERROR_CODE_WORKSPACE_LAYOUT_CONFLICT {
+
+					t.Fatalf("expected layout conflict error, got '%+v'", err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error, got '%+v'", err)
+			}
+
+			if strings.Join(repoRelDirPaths, ",") != strings.Join(tc.expectedRepoRelDirPaths, ",") {
+				t.Fatalf(
+					"expected paths to equal '%v', got '%v'",
+					tc.expectedRepoRelDirPaths,
+					repoRelDirPaths,
+				)
+			}
+		})
+	}
+}
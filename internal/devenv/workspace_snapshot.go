@@ -0,0 +1,564 @@
+package devenv
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/docker/docker/pkg/fileutils"
+	"github.com/klauspost/compress/zstd"
+	"github.com/recode-sh/agent/internal/system"
+)
+
+// Workspace snapshots are "tar.zst" archives
+// with one top-level directory per snapshotted dir.
+const (
+	workspaceSnapshotWorkspaceDirName       = "workspace"
+	workspaceSnapshotWorkspaceConfigDirName = "workspace-config"
+)
+
+func workspaceSnapshotDirs(devEnv *DevEnv) map[string]string {
+	return map[string]string{
+		workspaceSnapshotWorkspaceDirName:       devEnv.WorkspaceDirPath,
+		workspaceSnapshotWorkspaceConfigDirName: devEnv.WorkspaceConfigDirPath,
+	}
+}
+
+// SnapshotWorkspace writes a "tar.zst" archive of the workspace
+// and of the workspace config dir to "writer". The paths matching the
+// patterns associated with a repository root dir path are excluded
+// (see "isExcludedFromWorkspaceSnapshot" for the pattern syntax).
+func SnapshotWorkspace(
+	ctx context.Context,
+	devEnv *DevEnv,
+	writer io.Writer,
+	excludePatternsByRepoDirPath map[string][]string,
+) error {
+
+	return writeWorkspaceSnapshot(
+		ctx,
+		writer,
+		workspaceSnapshotDirs(devEnv),
+		excludePatternsByRepoDirPath,
+	)
+}
+
+// RestoreWorkspace replaces the workspace and the workspace
+// config dir with the content of the passed "tar.zst" archive.
+// The archive is extracted next to these dirs first so that they
+// are left untouched if the extraction fails. Their content is
+// then swapped (not the dirs themselves, which are mounted in the
+// container).
+func RestoreWorkspace(devEnv *DevEnv, reader io.Reader) error {
+	snapshotDirs := workspaceSnapshotDirs(devEnv)
+	restoredDirPaths := map[string]string{}
+
+	defer func() {
+		for _, restoredDirPath := range restoredDirPaths {
+			os.RemoveAll(restoredDirPath)
+		}
+	}()
+
+	for archiveDirName, dirPath := range snapshotDirs {
+		// The workspace of named development
+		// environments may not exist yet
+		err := os.MkdirAll(dirPath, os.FileMode(0755))
+
+		if err != nil {
+			return err
+		}
+
+		// On the same file system to be moved using renames
+		restoredDirPath, err := os.MkdirTemp(
+			filepath.Dir(dirPath),
+			"."+filepath.Base(dirPath)+"-restore-",
+		)
+
+		if err != nil {
+			return err
+		}
+
+		restoredDirPaths[archiveDirName] = restoredDirPath
+	}
+
+	err := extractWorkspaceSnapshot(reader, restoredDirPaths)
+
+	if err != nil {
+		return err
+	}
+
+	return swapWorkspaceSnapshotDirsContent(snapshotDirs, restoredDirPaths)
+}
+
+// swapWorkspaceSnapshotDirsContent replaces the content of the
+// snapshotted dirs with the content of the restored ones. The
+// previous content is put back if a dir cannot be swapped.
+func swapWorkspaceSnapshotDirsContent(
+	dirPathsByArchiveDirName map[string]string,
+	restoredDirPathsByArchiveDirName map[string]string,
+) (returnedErr error) {
+
+	filesManager := system.NewFileManager()
+	previousContentDirPaths := map[string]string{}
+	// The dirs that contain only restored files
+	// (their previous content was fully moved)
+	restoredContentDirPaths := map[string]bool{}
+
+	defer func() {
+		for dirPath, previousContentDirPath := range previousContentDirPaths {
+			if returnedErr != nil && restoredContentDirPaths[dirPath] {
+				filesManager.RemoveDirContent(dirPath)
+			}
+
+			if returnedErr != nil {
+				rollbackErr := moveDirContent(previousContentDirPath, dirPath)
+
+				// Never remove the previous content
+				// if it cannot be put back
+				if rollbackErr != nil {
+					returnedErr = fmt.Errorf(
+						"%w (the previous content of \"%s\" was kept in \"%s\": %v)",
+						returnedErr,
+						dirPath,
+						previousContentDirPath,
+						rollbackErr,
+					)
+
+					continue
+				}
+			}
+
+			os.RemoveAll(previousContentDirPath)
+		}
+	}()
+
+	for archiveDirName, dirPath := range dirPathsByArchiveDirName {
+		previousContentDirPath, err := os.MkdirTemp(
+			filepath.Dir(dirPath),
+			"."+filepath.Base(dirPath)+"-previous-",
+		)
+
+		if err != nil {
+			return err
+		}
+
+		previousContentDirPaths[dirPath] = previousContentDirPath
+
+		err = moveDirContent(dirPath, previousContentDirPath)
+
+		if err != nil {
+			return err
+		}
+
+		restoredContentDirPaths[dirPath] = true
+
+		err = moveDirContent(restoredDirPathsByArchiveDirName[archiveDirName], dirPath)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func moveDirContent(srcDirPath, dstDirPath string) error {
+	dirEntries, err := os.ReadDir(srcDirPath)
+
+	if err != nil {
+		return err
+	}
+
+	for _, dirEntry := range dirEntries {
+		err = os.Rename(
+			filepath.Join(srcDirPath, dirEntry.Name()),
+			filepath.Join(dstDirPath, dirEntry.Name()),
+		)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writeWorkspaceSnapshot(
+	ctx context.Context,
+	writer io.Writer,
+	dirPathsByArchiveDirName map[string]string,
+	excludePatternsByRepoDirPath map[string][]string,
+) error {
+
+	zstdWriter, err := zstd.NewWriter(writer)
+
+	if err != nil {
+		return err
+	}
+
+	excludePatternMatchersByRepoDirPath := map[string]*fileutils.PatternMatcher{}
+
+	for repoDirPath, excludePatterns := range excludePatternsByRepoDirPath {
+		excludePatternMatcher, err := fileutils.NewPatternMatcher(
+			buildWorkspaceSnapshotExcludePatterns(excludePatterns),
+		)
+
+		if err != nil {
+			zstdWriter.Close()
+			return err
+		}
+
+		excludePatternMatchersByRepoDirPath[repoDirPath] = excludePatternMatcher
+	}
+
+	tarWriter := tar.NewWriter(zstdWriter)
+
+	for archiveDirName, dirPath := range dirPathsByArchiveDirName {
+		err = addDirToWorkspaceSnapshot(
+			ctx,
+			tarWriter,
+			archiveDirName,
+			dirPath,
+			excludePatternMatchersByRepoDirPath,
+		)
+
+		if err != nil {
+			zstdWriter.Close()
+			return err
+		}
+	}
+
+	err = tarWriter.Close()
+
+	if err != nil {
+		zstdWriter.Close()
+		return err
+	}
+
+	return zstdWriter.Close()
+}
+
+func addDirToWorkspaceSnapshot(
+	ctx context.Context,
+	tarWriter *tar.Writer,
+	archiveDirName string,
+	dirPath string,
+	excludePatternMatchersByRepoDirPath map[string]*fileutils.PatternMatcher,
+) error {
+
+	return filepath.Walk(dirPath, func(
+		path string,
+		fileInfo os.FileInfo,
+		err error,
+	) error {
+
+		if err != nil {
+			return err
+		}
+
+		// Snapshots of large workspaces may take a while
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		isExcluded, err := isExcludedFromWorkspaceSnapshot(
+			path,
+			excludePatternMatchersByRepoDirPath,
+		)
+
+		if err != nil {
+			return err
+		}
+
+		if isExcluded && fileInfo.IsDir() {
+			return filepath.SkipDir
+		}
+
+		if isExcluded {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(dirPath, path)
+
+		if err != nil {
+			return err
+		}
+
+		symlinkTarget := ""
+
+		if fileInfo.Mode()&os.ModeSymlink != 0 {
+			symlinkTarget, err = os.Readlink(path)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		tarHeader, err := tar.FileInfoHeader(fileInfo, symlinkTarget)
+
+		if err != nil {
+			return err
+		}
+
+		tarHeader.Name = filepath.ToSlash(filepath.Join(archiveDirName, relPath))
+
+		if fileInfo.IsDir() {
+			tarHeader.Name += "/"
+		}
+
+		err = tarWriter.WriteHeader(tarHeader)
+
+		if err != nil {
+			return err
+		}
+
+		if !fileInfo.Mode().IsRegular() {
+			return nil
+		}
+
+		file, err := os.Open(path)
+
+		if err != nil {
+			return err
+		}
+
+		defer file.Close()
+
+		_, err = io.Copy(tarWriter, file)
+
+		return err
+	})
+}
+
+// buildWorkspaceSnapshotExcludePatterns converts the passed patterns
+// to the ".dockerignore" syntax. Patterns use this syntax ("**" matches
+// any number of dirs and patterns starting with "!" re-include the files
+// they match) except that, like in ".gitignore", patterns without "/"
+// match at any depth (eg: "node_modules" matches "web/node_modules")
+// and files in excluded dirs cannot be re-included.
+func buildWorkspaceSnapshotExcludePatterns(patterns []string) []string {
+	dockerignorePatterns := make([]string, 0, len(patterns))
+
+	for _, pattern := range patterns {
+		exceptionPrefix := ""
+
+		if strings.HasPrefix(pattern, "!") {
+			exceptionPrefix = "!"
+			pattern = pattern[1:]
+		}
+
+		if !strings.Contains(strings.TrimSuffix(pattern, "/"), "/") {
+			pattern = "**/" + pattern
+		}
+
+		dockerignorePatterns = append(dockerignorePatterns, exceptionPrefix+pattern)
+	}
+
+	return dockerignorePatterns
+}
+
+// isExcludedFromWorkspaceSnapshot matches the patterns
+// against the path relative to the repository root dir.
+func isExcludedFromWorkspaceSnapshot(
+	path string,
+	excludePatternMatchersByRepoDirPath map[string]*fileutils.PatternMatcher,
+) (bool, error) {
+
+	for repoDirPath, excludePatternMatcher := range excludePatternMatchersByRepoDirPath {
+		relPath, err := filepath.Rel(repoDirPath, path)
+
+		if err != nil || relPath == "." || strings.HasPrefix(relPath, "..") {
+			continue
+		}
+
+		isExcluded, err := excludePatternMatcher.Matches(relPath)
+
+		if err != nil {
+			return false, err
+		}
+
+		// Repositories are not nested
+		return isExcluded, nil
+	}
+
+	return false, nil
+}
+
+func extractWorkspaceSnapshot(
+	reader io.Reader,
+	dirPathsByArchiveDirName map[string]string,
+) error {
+
+	zstdReader, err := zstd.NewReader(reader)
+
+	if err != nil {
+		return err
+	}
+
+	defer zstdReader.Close()
+
+	tarReader := tar.NewReader(zstdReader)
+
+	for {
+		tarHeader, err := tarReader.Next()
+
+		if err != nil && errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		targetPath, err := resolveWorkspaceSnapshotEntryPath(
+			tarHeader.Name,
+			dirPathsByArchiveDirName,
+		)
+
+		if err != nil {
+			return err
+		}
+
+		err = removeWorkspaceSnapshotSymlink(targetPath)
+
+		if err != nil {
+			return err
+		}
+
+		fileMode := os.FileMode(tarHeader.Mode).Perm()
+
+		switch tarHeader.Typeflag {
+		case tar.TypeDir:
+			err = os.MkdirAll(targetPath, fileMode)
+		case tar.TypeSymlink:
+			err = os.Symlink(tarHeader.Linkname, targetPath)
+		case tar.TypeReg:
+			err = extractWorkspaceSnapshotFile(tarReader, targetPath, fileMode)
+		default:
+			// Other file types (devices, FIFOs...)
+			// are not created in workspaces
+			continue
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func extractWorkspaceSnapshotFile(
+	tarReader *tar.Reader,
+	filePath string,
+	fileMode os.FileMode,
+) error {
+
+	err := os.MkdirAll(filepath.Dir(filePath), os.FileMode(0755))
+
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(
+		filePath,
+		// Never write through a symlink created concurrently
+		os.O_CREATE|os.O_WRONLY|os.O_TRUNC|syscall.O_NOFOLLOW,
+		fileMode,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(file, tarReader)
+
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+// removeWorkspaceSnapshotSymlink removes the symlink present
+// at the path where an archive entry needs to be extracted
+// (if any) so that the entry replaces it instead of being
+// written to (or created in) the symlink target.
+func removeWorkspaceSnapshotSymlink(targetPath string) error {
+	targetInfo, err := os.Lstat(targetPath)
+
+	if err != nil && os.IsNotExist(err) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if targetInfo.Mode()&os.ModeSymlink == 0 {
+		return nil
+	}
+
+	return os.Remove(targetPath)
+}
+
+// resolveWorkspaceSnapshotEntryPath returns the path where
+// an archive entry needs to be extracted. Entries outside
+// of the snapshotted dirs are rejected.
+func resolveWorkspaceSnapshotEntryPath(
+	entryName string,
+	dirPathsByArchiveDirName map[string]string,
+) (string, error) {
+
+	entryPathParts := strings.SplitN(
+		strings.TrimPrefix(filepath.Clean(entryName), "/"),
+		string(filepath.Separator),
+		2,
+	)
+
+	dirPath, isKnownDir := dirPathsByArchiveDirName[entryPathParts[0]]
+
+	if !isKnownDir {
+		return "", fmt.Errorf(
+			"invalid entry \"%s\" in workspace snapshot",
+			entryName,
+		)
+	}
+
+	if len(entryPathParts) == 1 {
+		return dirPath, nil
+	}
+
+	targetPath := filepath.Join(dirPath, entryPathParts[1])
+
+	if !strings.HasPrefix(targetPath, dirPath+string(filepath.Separator)) {
+		return "", fmt.Errorf(
+			"invalid entry \"%s\" in workspace snapshot",
+			entryName,
+		)
+	}
+
+	// Prevent entries from being
+	// extracted through symlinks
+	parentDirPath := filepath.Dir(targetPath)
+
+	for parentDirPath != dirPath {
+		parentDirInfo, err := os.Lstat(parentDirPath)
+
+		if err == nil && parentDirInfo.Mode()&os.ModeSymlink != 0 {
+			return "", fmt.Errorf(
+				"invalid entry \"%s\" in workspace snapshot",
+				entryName,
+			)
+		}
+
+		parentDirPath = filepath.Dir(parentDirPath)
+	}
+
+	return targetPath, nil
+}
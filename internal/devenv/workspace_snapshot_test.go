@@ -0,0 +1,460 @@
+package devenv
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+func TestWorkspaceSnapshotRoundTrip(t *testing.T) {
+	srcWorkspaceDirPath := t.TempDir()
+	srcWorkspaceConfigDirPath := t.TempDir()
+
+	srcRepoDirPath := filepath.Join(srcWorkspaceDirPath, "api")
+
+	srcFiles := map[string]string{
+		filepath.Join(srcRepoDirPath, "main.go"):                            "package main",
+		filepath.Join(srcRepoDirPath, "node_modules", "dep", "a.js"):        "a",
+		filepath.Join(srcRepoDirPath, "web", "node_modules", "dep", "b.js"): "b",
+		filepath.Join(srcRepoDirPath, "build", "api.o"):                     "o",
+		filepath.Join(srcRepoDirPath, "build", "keep.o"):                    "keep",
+		filepath.Join(srcRepoDirPath, "build", "README"):                    "readme",
+		filepath.Join(srcRepoDirPath, "logs", "2022", "01", "api.log"):      "log",
+		filepath.Join(srcWorkspaceConfigDirPath, "recode.workspace"):        "{}",
+	}
+
+	for filePath, fileContent := range srcFiles {
+		err := os.MkdirAll(filepath.Dir(filePath), os.FileMode(0755))
+
+		if err != nil {
+			t.Fatalf("expected no error, got '%+v'", err)
+		}
+
+		err = os.WriteFile(filePath, []byte(fileContent), os.FileMode(0644))
+
+		if err != nil {
+			t.Fatalf("expected no error, got '%+v'", err)
+		}
+	}
+
+	var snapshot bytes.Buffer
+
+	err := writeWorkspaceSnapshot(
+		context.Background(),
+		&snapshot,
+		map[string]string{
+			workspaceSnapshotWorkspaceDirName:       srcWorkspaceDirPath,
+			workspaceSnapshotWorkspaceConfigDirName: srcWorkspaceConfigDirPath,
+		},
+		map[string][]string{
+			srcRepoDirPath: {"node_modules", "build/*.o", "!build/keep.o", "logs/**/*.log"},
+		},
+	)
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	dstWorkspaceDirPath := t.TempDir()
+	dstWorkspaceConfigDirPath := t.TempDir()
+
+	err = extractWorkspaceSnapshot(
+		&snapshot,
+		map[string]string{
+			workspaceSnapshotWorkspaceDirName:       dstWorkspaceDirPath,
+			workspaceSnapshotWorkspaceConfigDirName: dstWorkspaceConfigDirPath,
+		},
+	)
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	expectedFiles := map[string]string{
+		filepath.Join(dstWorkspaceDirPath, "api", "main.go"):         "package main",
+		filepath.Join(dstWorkspaceDirPath, "api", "build", "README"): "readme",
+		filepath.Join(dstWorkspaceDirPath, "api", "build", "keep.o"): "keep",
+		filepath.Join(dstWorkspaceConfigDirPath, "recode.workspace"): "{}",
+	}
+
+	for filePath, expectedFileContent := range expectedFiles {
+		fileContent, err := os.ReadFile(filePath)
+
+		if err != nil {
+			t.Fatalf("expected no error, got '%+v'", err)
+		}
+
+		if string(fileContent) != expectedFileContent {
+			t.Fatalf(
+				"expected '%s' content to equal '%s', got '%s'",
+				filePath,
+				expectedFileContent,
+				string(fileContent),
+			)
+		}
+	}
+
+	excludedPaths := []string{
+		filepath.Join(dstWorkspaceDirPath, "api", "node_modules"),
+		filepath.Join(dstWorkspaceDirPath, "api", "web", "node_modules"),
+		filepath.Join(dstWorkspaceDirPath, "api", "build", "api.o"),
+		filepath.Join(dstWorkspaceDirPath, "api", "logs", "2022", "01", "api.log"),
+	}
+
+	for _, excludedPath := range excludedPaths {
+		_, err := os.Stat(excludedPath)
+
+		if !os.IsNotExist(err) {
+			t.Fatalf("expected '%s' to be excluded", excludedPath)
+		}
+	}
+}
+
+func TestResolveWorkspaceSnapshotEntryPath(t *testing.T) {
+	dirPathsByArchiveDirName := map[string]string{
+		workspaceSnapshotWorkspaceDirName: "/home/recode/workspace",
+	}
+
+	testCases := []struct {
+		test         string
+		entryName    string
+		expectedPath string
+		expectError  bool
+	}{
+		{
+			test:         "valid_entry",
+			entryName:    "workspace/api/main.go",
+			expectedPath: "/home/recode/workspace/api/main.go",
+			expectError:  false,
+		},
+
+		{
+			test:         "path_traversal",
+			entryName:    "workspace/../../.ssh/authorized_keys",
+			expectedPath: "",
+			expectError:  true,
+		},
+
+		{
+			test:         "unknown_dir",
+			entryName:    "etc/passwd",
+			expectedPath: "",
+			expectError:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.test, func(t *testing.T) {
+			path, err := resolveWorkspaceSnapshotEntryPath(
+				tc.entryName,
+				dirPathsByArchiveDirName,
+			)
+
+			if tc.expectError && err == nil {
+				t.Fatalf("expected error, got nothing")
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error, got '%+v'", err)
+			}
+
+			if path != tc.expectedPath {
+				t.Fatalf(
+					"expected path to equal '%s', got '%s'",
+					tc.expectedPath,
+					path,
+				)
+			}
+		})
+	}
+}
+
+func TestExtractWorkspaceSnapshotDoesNotFollowSymlinks(t *testing.T) {
+	outsideDirPath := t.TempDir()
+	outsideFilePaths := []string{
+		filepath.Join(outsideDirPath, "authorized_keys"),
+		filepath.Join(outsideDirPath, "bashrc"),
+	}
+
+	for _, outsideFilePath := range outsideFilePaths {
+		err := os.WriteFile(outsideFilePath, []byte("outside"), os.FileMode(0644))
+
+		if err != nil {
+			t.Fatalf("expected no error, got '%+v'", err)
+		}
+	}
+
+	dstWorkspaceDirPath := t.TempDir()
+
+	// Present in the workspace before the restore
+	err := os.Symlink(
+		outsideFilePaths[0],
+		filepath.Join(dstWorkspaceDirPath, "existing-link"),
+	)
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	var snapshot bytes.Buffer
+
+	zstdWriter, err := zstd.NewWriter(&snapshot)
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	tarWriter := tar.NewWriter(zstdWriter)
+
+	tarEntries := []struct {
+		header  *tar.Header
+		content string
+	}{
+		{
+			header: &tar.Header{
+				Name:     "workspace/existing-link",
+				Typeflag: tar.TypeReg,
+				Mode:     0644,
+				Size:     int64(len("snapshot")),
+			},
+			content: "snapshot",
+		},
+
+		// Symlink followed by a file with the same name
+		{
+			header: &tar.Header{
+				Name:     "workspace/archived-link",
+				Typeflag: tar.TypeSymlink,
+				Linkname: outsideFilePaths[1],
+			},
+		},
+
+		{
+			header: &tar.Header{
+				Name:     "workspace/archived-link",
+				Typeflag: tar.TypeReg,
+				Mode:     0644,
+				Size:     int64(len("snapshot")),
+			},
+			content: "snapshot",
+		},
+	}
+
+	for _, tarEntry := range tarEntries {
+		err = tarWriter.WriteHeader(tarEntry.header)
+
+		if err != nil {
+			t.Fatalf("expected no error, got '%+v'", err)
+		}
+
+		_, err = tarWriter.Write([]byte(tarEntry.content))
+
+		if err != nil {
+			t.Fatalf("expected no error, got '%+v'", err)
+		}
+	}
+
+	if err = tarWriter.Close(); err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	if err = zstdWriter.Close(); err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	err = extractWorkspaceSnapshot(
+		&snapshot,
+		map[string]string{
+			workspaceSnapshotWorkspaceDirName: dstWorkspaceDirPath,
+		},
+	)
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	for _, outsideFilePath := range outsideFilePaths {
+		fileContent, err := os.ReadFile(outsideFilePath)
+
+		if err != nil {
+			t.Fatalf("expected no error, got '%+v'", err)
+		}
+
+		if string(fileContent) != "outside" {
+			t.Fatalf(
+				"expected '%s' content to equal 'outside', got '%s'",
+				outsideFilePath,
+				string(fileContent),
+			)
+		}
+	}
+
+	for _, fileName := range []string{"existing-link", "archived-link"} {
+		filePath := filepath.Join(dstWorkspaceDirPath, fileName)
+		fileInfo, err := os.Lstat(filePath)
+
+		if err != nil {
+			t.Fatalf("expected no error, got '%+v'", err)
+		}
+
+		if !fileInfo.Mode().IsRegular() {
+			t.Fatalf("expected '%s' to be a regular file, got '%s'", filePath, fileInfo.Mode())
+		}
+	}
+}
+
+func TestRestoreWorkspace(t *testing.T) {
+	srcWorkspaceDirPath := t.TempDir()
+	srcWorkspaceConfigDirPath := t.TempDir()
+
+	writeTestWorkspaceSnapshotFiles(t, map[string]string{
+		filepath.Join(srcWorkspaceDirPath, "api", "main.go"):         "snapshot",
+		filepath.Join(srcWorkspaceConfigDirPath, "recode.workspace"): "snapshot",
+	})
+
+	var snapshot bytes.Buffer
+
+	err := writeWorkspaceSnapshot(
+		context.Background(),
+		&snapshot,
+		map[string]string{
+			workspaceSnapshotWorkspaceDirName:       srcWorkspaceDirPath,
+			workspaceSnapshotWorkspaceConfigDirName: srcWorkspaceConfigDirPath,
+		},
+		map[string][]string{},
+	)
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	testCases := []struct {
+		test          string
+		snapshot      []byte
+		expectedError bool
+		expectedFiles map[string]string
+	}{
+		{
+			test:          "truncated_snapshot",
+			snapshot:      snapshot.Bytes()[:snapshot.Len()/2],
+			expectedError: true,
+			expectedFiles: map[string]string{
+				filepath.Join("workspace", "api", "main.go"):          "existing",
+				filepath.Join("workspace", "web", "index.js"):         "existing",
+				filepath.Join("workspace-config", "recode.workspace"): "existing",
+			},
+		},
+
+		{
+			test:          "valid_snapshot",
+			snapshot:      snapshot.Bytes(),
+			expectedError: false,
+			expectedFiles: map[string]string{
+				filepath.Join("workspace", "api", "main.go"):          "snapshot",
+				filepath.Join("workspace", "web", "index.js"):         "",
+				filepath.Join("workspace-config", "recode.workspace"): "snapshot",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.test, func(t *testing.T) {
+			parentDirPath := t.TempDir()
+			devEnv := &DevEnv{
+				WorkspaceDirPath:       filepath.Join(parentDirPath, "workspace"),
+				WorkspaceConfigDirPath: filepath.Join(parentDirPath, "workspace-config"),
+			}
+
+			writeTestWorkspaceSnapshotFiles(t, map[string]string{
+				filepath.Join(devEnv.WorkspaceDirPath, "api", "main.go"):         "existing",
+				filepath.Join(devEnv.WorkspaceDirPath, "web", "index.js"):        "existing",
+				filepath.Join(devEnv.WorkspaceConfigDirPath, "recode.workspace"): "existing",
+			})
+
+			workspaceDirInfo, err := os.Stat(devEnv.WorkspaceDirPath)
+
+			if err != nil {
+				t.Fatalf("expected no error, got '%+v'", err)
+			}
+
+			err = RestoreWorkspace(devEnv, bytes.NewReader(tc.snapshot))
+
+			if tc.expectedError && err == nil {
+				t.Fatalf("expected error, got nothing")
+			}
+
+			if !tc.expectedError && err != nil {
+				t.Fatalf("expected no error, got '%+v'", err)
+			}
+
+			for filePath, expectedFileContent := range tc.expectedFiles {
+				fileContent, err := os.ReadFile(filepath.Join(parentDirPath, filePath))
+
+				// Empty content means that the file must not exist
+				if expectedFileContent == "" && !os.IsNotExist(err) {
+					t.Fatalf("expected '%s' to be removed, got '%+v'", filePath, err)
+				}
+
+				if expectedFileContent == "" {
+					continue
+				}
+
+				if err != nil {
+					t.Fatalf("expected no error, got '%+v'", err)
+				}
+
+				if string(fileContent) != expectedFileContent {
+					t.Fatalf(
+						"expected '%s' content to equal '%s', got '%s'",
+						filePath,
+						expectedFileContent,
+						string(fileContent),
+					)
+				}
+			}
+
+			// The workspace is mounted in the container
+			restoredWorkspaceDirInfo, err := os.Stat(devEnv.WorkspaceDirPath)
+
+			if err != nil {
+				t.Fatalf("expected no error, got '%+v'", err)
+			}
+
+			if !os.SameFile(workspaceDirInfo, restoredWorkspaceDirInfo) {
+				t.Fatalf("expected workspace dir to be kept")
+			}
+
+			// The temporary dirs are removed
+			parentDirEntries, err := os.ReadDir(parentDirPath)
+
+			if err != nil {
+				t.Fatalf("expected no error, got '%+v'", err)
+			}
+
+			if len(parentDirEntries) != 2 {
+				t.Fatalf("expected 2 dirs, got '%+v'", parentDirEntries)
+			}
+		})
+	}
+}
+
+func writeTestWorkspaceSnapshotFiles(t *testing.T, files map[string]string) {
+	for filePath, fileContent := range files {
+		err := os.MkdirAll(filepath.Dir(filePath), os.FileMode(0755))
+
+		if err != nil {
+			t.Fatalf("expected no error, got '%+v'", err)
+		}
+
+		err = os.WriteFile(filePath, []byte(fileContent), os.FileMode(0644))
+
+		if err != nil {
+			t.Fatalf("expected no error, got '%+v'", err)
+		}
+	}
+}
@@ -0,0 +1,508 @@
+package devenv
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/recode-sh/agent/internal/system"
+	"github.com/recode-sh/agent/proto"
+	"github.com/recode-sh/recode/entities"
+)
+
+type WorkspaceSyncOptions struct {
+	Mode proto.WorkspaceSyncMode
+	// Used only in incremental mode. Removed repositories
+	// are deleted when not set (unless they have local changes).
+	ArchiveRemovedRepos bool
+}
+
+func (w WorkspaceSyncOptions) isIncremental() bool {
+	return w.Mode == proto.WorkspaceSyncMode_WORKSPACE_SYNC_MODE_INCREMENTAL
+}
+
+// cloneOrSyncRepoInWorkspace clones the repository if it is not present
+// in the workspace. Otherwise, the existing clone is fetched and
+// fast-forwarded if it doesn't have any local changes.
+func cloneOrSyncRepoInWorkspace(
+	ctx context.Context,
+	stream proto.Agent_BuildAndStartDevEnvServer,
+	devEnvRepo *GitRepository,
+	repo *GitRepository,
+	repoDirPath string,
+	preparedWorkspaceMetadata *PreparedWorkspaceMetadata,
+) error {
+
+	filesManager := system.NewFileManager()
+
+	repoIsCloned, err := filesManager.DoesFileExist(
+		filepath.Join(repoDirPath, ".git"),
+	)
+
+	if err != nil {
+		return err
+	}
+
+	if repoIsCloned {
+		repoHasLocalChanges := false
+
+		err = runBuildStep(
+			stream,
+			buildStep{
+				ID:   buildStepIDSyncRepo(repo.Owner, repo.Name),
+				Type: proto.StepType_STEP_TYPE_SYNC_REPO,
+				Description: fmt.Sprintf(
+					"Syncing %s in workspace",
+					repo.FullNameWithRef(),
+				),
+				Repository: repo.FullName(),
+			},
+			func() (err error) {
+				repoHasLocalChanges, err = syncRepoInWorkspace(
+					ctx,
+					stream,
+					repo,
+					repoDirPath,
+				)
+
+				return err
+			},
+		)
+
+		// Repositories with local changes are left untouched
+		if err != nil || repoHasLocalChanges {
+			return err
+		}
+
+		return populateRepoSubmodulesAndLFSObjects(
+			ctx,
+			stream,
+			repo,
+			repoDirPath,
+		)
+	}
+
+	repoDirExists, err := filesManager.DoesFileExist(repoDirPath)
+
+	if err != nil {
+		return err
+	}
+
+	if repoDirExists {
+		repoDirEntries, err := os.ReadDir(repoDirPath)
+
+		if err != nil {
+			return err
+		}
+
+		// Never clone over (and then remove on failure)
+		// files that were not created by the agent
+		if len(repoDirEntries) > 0 {
+			return fmt.Errorf(
+				"\"%s\" cannot be cloned in \"%s\": the directory already exists and is not a git repository",
+				repo.FullName(),
+				repoDirPath,
+			)
+		}
+	}
+
+	err = runBuildStep(
+		stream,
+		buildStep{
+			ID:   buildStepIDCloneRepo(repo.Owner, repo.Name),
+			Type: proto.StepType_STEP_TYPE_CLONE_REPO,
+			Description: fmt.Sprintf(
+				"Cloning %s in workspace",
+				repo.FullNameWithRef(),
+			),
+			Repository: repo.FullName(),
+		},
+		func() error {
+			cloneProgressStream := newGitCloneProgressStream(
+				stream,
+				buildStepIDCloneRepo(repo.Owner, repo.Name),
+				repo.FullName(),
+			)
+
+			repoCloneReused, err := reuseDevEnvRepoClone(
+				ctx,
+				devEnvRepo,
+				repo,
+				repoDirPath,
+				preparedWorkspaceMetadata,
+				cloneProgressStream,
+			)
+
+			if err != nil || repoCloneReused {
+				return err
+			}
+
+			return cloneGitRepo(
+				ctx,
+				repo,
+				repoDirPath,
+				cloneProgressStream,
+			)
+		},
+	)
+
+	if err != nil {
+		// Make sure that a partial clone
+		// will not be synced during the next build
+		// (the dir is kept if it existed before)
+		var removeErr error
+
+		if repoDirExists {
+			removeErr = filesManager.RemoveDirContent(repoDirPath)
+		} else {
+			removeErr = os.RemoveAll(repoDirPath)
+		}
+
+		if removeErr != nil {
+			return fmt.Errorf(
+				"%w (the partial clone in \"%s\" could not be removed: %v)",
+				err,
+				repoDirPath,
+				removeErr,
+			)
+		}
+
+		return err
+	}
+
+	preparedWorkspaceMetadata.ClonedRepoDirPaths = append(
+		preparedWorkspaceMetadata.ClonedRepoDirPaths,
+		repoDirPath,
+	)
+
+	return populateRepoSubmodulesAndLFSObjects(
+		ctx,
+		stream,
+		repo,
+		repoDirPath,
+	)
+}
+
+// reuseDevEnvRepoClone moves the temporary clone of the dev env repository
+// to the workspace (or uses it as a reference for the workspace clone
+// when it cannot be moved) so that the dev env repository is
+// fetched from the network only once per build.
+func reuseDevEnvRepoClone(
+	ctx context.Context,
+	devEnvRepo *GitRepository,
+	repo *GitRepository,
+	repoDirPath string,
+	preparedWorkspaceMetadata *PreparedWorkspaceMetadata,
+	cloneProgressStream *gitCloneProgressStream,
+) (bool, error) {
+
+	tmpDevEnvRepoDirPath := preparedWorkspaceMetadata.TmpDevEnvRepoDirPath
+
+	if len(tmpDevEnvRepoDirPath) == 0 ||
+		repo.CloneURL() != devEnvRepo.CloneURL() ||
+		repo.Ref != devEnvRepo.Ref ||
+		!repo.CloneOptions.equals(devEnvRepo.CloneOptions) {
+
+		return false, nil
+	}
+
+	err := os.Rename(tmpDevEnvRepoDirPath, repoDirPath)
+
+	if err == nil {
+		preparedWorkspaceMetadata.TmpDevEnvRepoDirPath = ""
+		preparedWorkspaceMetadata.TmpDevEnvRepoConfigDirPath = filepath.Join(
+			repoDirPath,
+			entities.DevEnvRepositoryConfigDirectory,
+		)
+
+		if preparedWorkspaceMetadata.DevEnvRepoHasDockerfile {
+			preparedWorkspaceMetadata.TmpDevEnvRepoDockerfilePath = filepath.Join(
+				preparedWorkspaceMetadata.TmpDevEnvRepoConfigDirPath,
+				entities.DevEnvRepositoryDockerfileFileName,
+			)
+		}
+
+		return true, nil
+	}
+
+	// Shallow repositories cannot be used as reference
+	if repo.CloneOptions.Depth > 0 {
+		return false, nil
+	}
+
+	// The temporary dir may be on another device
+	return true, cloneGitRepo(
+		ctx,
+		repo,
+		repoDirPath,
+		cloneProgressStream,
+		"--reference",
+		tmpDevEnvRepoDirPath,
+		"--dissociate",
+	)
+}
+
+// syncRepoInWorkspace returns true if the repository
+// has local changes (it is left untouched in this case).
+func syncRepoInWorkspace(
+	ctx context.Context,
+	stream proto.Agent_BuildAndStartDevEnvServer,
+	repo *GitRepository,
+	repoDirPath string,
+) (bool, error) {
+
+	repoHasLocalChanges, err := gitRepoHasLocalChanges(ctx, repoDirPath)
+
+	if err != nil {
+		return false, err
+	}
+
+	if repoHasLocalChanges {
+		return true, sendWorkspaceSyncWarning(
+			stream,
+			"\"%s\" has local changes and was left untouched",
+			repo.FullName(),
+		)
+	}
+
+	err = fetchGitRepo(ctx, repoDirPath)
+
+	if err != nil {
+		return false, err
+	}
+
+	if len(repo.Ref) > 0 {
+		err = checkoutGitRef(ctx, repoDirPath, repo.Ref, 0)
+
+		if err != nil {
+			return false, err
+		}
+
+		// Tags and commit SHAs are not fast-forwarded
+		if !gitRepoIsOnBranch(ctx, repoDirPath) {
+			return false, nil
+		}
+	}
+
+	err = fastForwardGitRepo(ctx, repoDirPath)
+
+	if err != nil && ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+
+	// Diverged branches or branches without
+	// upstream are not considered as errors
+	if err != nil {
+		return false, sendWorkspaceSyncWarning(
+			stream,
+			"\"%s\" could not be fast-forwarded and was left untouched",
+			repo.FullName(),
+		)
+	}
+
+	return false, nil
+}
+
+// removeReposNoLongerInWorkspace archives (or deletes) the repositories
+// present in the previous workspace config but not in the current one.
+func removeReposNoLongerInWorkspace(
+	ctx context.Context,
+	stream proto.Agent_BuildAndStartDevEnvServer,
+	devEnv *DevEnv,
+	previousRepos []WorkspaceConfigRepository,
+	workspaceConfig *WorkspaceConfig,
+	archiveRemovedRepos bool,
+) error {
+
+	currentRepoDirPaths := map[string]bool{}
+
+	for _, repo := range workspaceConfig.Repositories {
+		currentRepoDirPaths[repo.RootDirPath] = true
+	}
+
+	filesManager := system.NewFileManager()
+
+	for _, previousRepo := range previousRepos {
+		if currentRepoDirPaths[previousRepo.RootDirPath] {
+			continue
+		}
+
+		repoExists, err := filesManager.DoesFileExist(previousRepo.RootDirPath)
+
+		if err != nil {
+			return err
+		}
+
+		if !repoExists {
+			continue
+		}
+
+		if archiveRemovedRepos {
+			archivedRepoDirPath, err := archiveRepo(devEnv, previousRepo)
+
+			if err != nil {
+				return err
+			}
+
+			removeEmptyParentDirsInWorkspace(devEnv, previousRepo.RootDirPath)
+
+			err = sendWorkspaceSyncWarning(
+				stream,
+				"\"%s/%s\" was removed from the workspace and archived in \"%s\"",
+				previousRepo.Owner,
+				previousRepo.Name,
+				archivedRepoDirPath,
+			)
+
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		repoHasLocalChanges, err := gitRepoHasLocalChanges(
+			ctx,
+			previousRepo.RootDirPath,
+		)
+
+		if err != nil {
+			return err
+		}
+
+		if repoHasLocalChanges {
+			err = sendWorkspaceSyncWarning(
+				stream,
+				"\"%s/%s\" was removed from the workspace but has local changes so it was kept in \"%s\"",
+				previousRepo.Owner,
+				previousRepo.Name,
+				previousRepo.RootDirPath,
+			)
+
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		err = os.RemoveAll(previousRepo.RootDirPath)
+
+		if err != nil {
+			return err
+		}
+
+		removeEmptyParentDirsInWorkspace(devEnv, previousRepo.RootDirPath)
+	}
+
+	return nil
+}
+
+// removeEmptyParentDirsInWorkspace removes the parent dirs of
+// the passed repository (eg: "<owner>" in the "<owner>/<name>"
+// layout) that are empty once the repository is removed.
+func removeEmptyParentDirsInWorkspace(
+	devEnv *DevEnv,
+	repoDirPath string,
+) {
+
+	workspaceDirPath := filepath.Clean(devEnv.WorkspaceDirPath)
+
+	for dirPath := filepath.Dir(repoDirPath); strings.HasPrefix(
+		dirPath,
+		workspaceDirPath+"/",
+	); dirPath = filepath.Dir(dirPath) {
+
+		// Fails if the dir is not empty
+		if err := os.Remove(dirPath); err != nil {
+			return
+		}
+	}
+}
+
+func archiveRepo(
+	devEnv *DevEnv,
+	repo WorkspaceConfigRepository,
+) (string, error) {
+
+	err := os.MkdirAll(
+		devEnv.WorkspaceArchiveDirPath,
+		os.FileMode(0755),
+	)
+
+	if err != nil {
+		return "", err
+	}
+
+	archivedRepoDirPath := filepath.Join(
+		devEnv.WorkspaceArchiveDirPath,
+		fmt.Sprintf(
+			"%s-%s-%s",
+			// GitLab subgroups
+			strings.ReplaceAll(repo.Owner, "/", "-"),
+			repo.Name,
+			time.Now().Format("20060102150405"),
+		),
+	)
+
+	err = os.Rename(repo.RootDirPath, archivedRepoDirPath)
+
+	if err != nil {
+		return "", err
+	}
+
+	return archivedRepoDirPath, nil
+}
+
+// removeUnusedWorkspaceHooks removes the hooks installed
+// in the workspace config dir that are not referenced
+// anymore by the passed workspace config.
+func removeUnusedWorkspaceHooks(
+	devEnv *DevEnv,
+	workspaceConfig *WorkspaceConfig,
+) error {
+
+	usedHookFilePaths := map[string]bool{}
+
+	for _, repo := range workspaceConfig.Repositories {
+		for _, hook := range repo.Hooks {
+			usedHookFilePaths[hook.ScriptFilePath] = true
+		}
+	}
+
+	hookFilePaths, err := filepath.Glob(
+		filepath.Join(devEnv.WorkspaceConfigHooksDirPath, "*"),
+	)
+
+	if err != nil {
+		return err
+	}
+
+	for _, hookFilePath := range hookFilePaths {
+		if usedHookFilePaths[hookFilePath] {
+			continue
+		}
+
+		err = os.RemoveAll(hookFilePath)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func sendWorkspaceSyncWarning(
+	stream proto.Agent_BuildAndStartDevEnvServer,
+	format string,
+	args ...interface{},
+) error {
+
+	return stream.Send(&proto.BuildAndStartDevEnvReply{
+		LogLine: "Warning: " + fmt.Sprintf(format, args...) + "\n",
+	})
+}
@@ -0,0 +1,787 @@
+package devenv
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/recode-sh/agent/proto"
+	"github.com/recode-sh/recode/entities"
+)
+
+type testBuildAndStartDevEnvStream struct {
+	proto.Agent_BuildAndStartDevEnvServer
+	replies []*proto.BuildAndStartDevEnvReply
+}
+
+func (t *testBuildAndStartDevEnvStream) Send(
+	reply *proto.BuildAndStartDevEnvReply,
+) error {
+
+	t.replies = append(t.replies, reply)
+	return nil
+}
+
+func newTestDevEnv(t *testing.T) *DevEnv {
+	workspaceConfigDirPath := t.TempDir()
+
+	return &DevEnv{
+		Name:                               "test",
+		WorkspaceDirPath:                   t.TempDir(),
+		WorkspaceArchiveDirPath:            t.TempDir(),
+		WorkspaceConfigDirPath:             workspaceConfigDirPath,
+		WorkspaceConfigHooksDirPath:        filepath.Join(workspaceConfigDirPath, "hooks"),
+		WorkspaceConfigHooksResultsDirPath: filepath.Join(workspaceConfigDirPath, "hooks-results"),
+		WorkspaceConfigFilePath:            filepath.Join(workspaceConfigDirPath, "recode.workspace"),
+		VSCodeWorkspaceConfigFilePath:      filepath.Join(workspaceConfigDirPath, "recode.code-workspace"),
+		// Outside of the workspace config dir (cleared during full syncs)
+		DockerImagesHistoryFilePath: filepath.Join(t.TempDir(), "images-history.json"),
+	}
+}
+
+// createTestGitRepo creates a git repository in "repoDirPath"
+// with one commit containing the passed files
+func createTestGitRepo(
+	t *testing.T,
+	repoDirPath string,
+	files map[string]string,
+) {
+
+	for filePath, fileContent := range files {
+		fullFilePath := filepath.Join(repoDirPath, filePath)
+
+		err := os.MkdirAll(filepath.Dir(fullFilePath), os.FileMode(0755))
+
+		if err != nil {
+			t.Fatalf("expected no error, got '%+v'", err)
+		}
+
+		err = os.WriteFile(fullFilePath, []byte(fileContent), os.FileMode(0644))
+
+		if err != nil {
+			t.Fatalf("expected no error, got '%+v'", err)
+		}
+	}
+
+	gitCommands := [][]string{
+		{"init", "--quiet"},
+		{"add", "--all"},
+		{
+			"-c", "user.name=Recode",
+			"-c", "user.email=test@recode.sh",
+			"commit", "--quiet", "--message", "Initial commit",
+		},
+	}
+
+	for _, gitCommand := range gitCommands {
+		runTestGitCommand(t, repoDirPath, gitCommand...)
+	}
+}
+
+func runTestGitCommand(
+	t *testing.T,
+	repoDirPath string,
+	args ...string,
+) string {
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = repoDirPath
+
+	output, err := cmd.CombinedOutput()
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v' (%s)", err, output)
+	}
+
+	return strings.TrimSpace(string(output))
+}
+
+func TestPrepareWorkspaceWithFileGitProvider(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	gitReposBaseDirPath := t.TempDir()
+
+	createTestGitRepo(
+		t,
+		filepath.Join(gitReposBaseDirPath, "acme", "config"),
+		map[string]string{
+			entities.DevEnvUserConfigDockerfileFileName: "FROM recodesh/base-dev-env:latest\n",
+		},
+	)
+
+	createTestGitRepo(
+		t,
+		filepath.Join(gitReposBaseDirPath, "acme", "api"),
+		map[string]string{
+			filepath.Join(
+				entities.DevEnvRepositoryConfigDirectory,
+				entities.DevEnvRepositoryDockerfileFileName,
+			): "FROM recode-user-config-image\n" +
+				"LABEL sh.recode.repositories=\"api, web\"\n",
+		},
+	)
+
+	createTestGitRepo(
+		t,
+		filepath.Join(gitReposBaseDirPath, "acme", "web"),
+		map[string]string{
+			"index.html": "<html></html>",
+		},
+	)
+
+	gitProvider, err := NewGitProvider("file://" + gitReposBaseDirPath)
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	devEnv := newTestDevEnv(t)
+	workspaceConfig := NewWorkspaceConfig()
+
+	preparedWorkspaceMetadata, err := PrepareWorkspace(
+		context.Background(),
+		&testBuildAndStartDevEnvStream{},
+		devEnv,
+		NewGitRepository(gitProvider, "acme", "config"),
+		NewGitRepository(gitProvider, "acme", "api"),
+		workspaceConfig,
+		WorkspaceSyncOptions{},
+		ReposCloneOptions{},
+		proto.WorkspaceLayout_WORKSPACE_LAYOUT_NAME,
+	)
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	defer os.RemoveAll(preparedWorkspaceMetadata.TmpUserConfigRepoDirPath)
+	defer os.RemoveAll(preparedWorkspaceMetadata.TmpDevEnvRepoDirPath)
+
+	if !preparedWorkspaceMetadata.DevEnvRepoHasDockerfile {
+		t.Fatalf("expected dev env repo to have a Dockerfile, got nothing")
+	}
+
+	if len(workspaceConfig.Repositories) != 2 {
+		t.Fatalf(
+			"expected 2 repositories in workspace, got '%d'",
+			len(workspaceConfig.Repositories),
+		)
+	}
+
+	for _, repo := range workspaceConfig.Repositories {
+		expectedIsDevEnvRepo := repo.Name == "api"
+
+		if repo.IsDevEnvRepo != expectedIsDevEnvRepo {
+			t.Fatalf(
+				"expected '%s' is dev env repo to equal '%t', got '%t'",
+				repo.Name,
+				expectedIsDevEnvRepo,
+				repo.IsDevEnvRepo,
+			)
+		}
+
+		_, err := os.Stat(filepath.Join(repo.RootDirPath, ".git"))
+
+		if err != nil {
+			t.Fatalf("expected '%s' to be cloned, got '%+v'", repo.Name, err)
+		}
+	}
+
+	savedWorkspaceConfig, err := LoadWorkspaceConfig(devEnv.WorkspaceConfigFilePath)
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	if len(savedWorkspaceConfig.Repositories) != 2 {
+		t.Fatalf(
+			"expected 2 repositories in saved workspace config, got '%d'",
+			len(savedWorkspaceConfig.Repositories),
+		)
+	}
+}
+
+func TestPrepareWorkspaceWithPinnedRefs(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	gitReposBaseDirPath := t.TempDir()
+
+	createTestGitRepo(
+		t,
+		filepath.Join(gitReposBaseDirPath, "acme", "config"),
+		map[string]string{
+			entities.DevEnvUserConfigDockerfileFileName: "FROM recodesh/base-dev-env:latest\n",
+		},
+	)
+
+	apiRepoDirPath := filepath.Join(gitReposBaseDirPath, "acme", "api")
+
+	createTestGitRepo(
+		t,
+		apiRepoDirPath,
+		map[string]string{
+			filepath.Join(
+				entities.DevEnvRepositoryConfigDirectory,
+				entities.DevEnvRepositoryDockerfileFileName,
+			): "FROM recode-user-config-image\n" +
+				"LABEL sh.recode.repositories=\"api, web@v1\"\n",
+		},
+	)
+
+	runTestGitCommand(t, apiRepoDirPath, "checkout", "--quiet", "-b", "feature")
+	runTestGitCommand(
+		t,
+		apiRepoDirPath,
+		"-c", "user.name=Recode",
+		"-c", "user.email=test@recode.sh",
+		"commit", "--quiet", "--allow-empty", "--message", "Feature",
+	)
+
+	apiFeatureSHA := runTestGitCommand(t, apiRepoDirPath, "rev-parse", "HEAD")
+
+	runTestGitCommand(t, apiRepoDirPath, "checkout", "--quiet", "-")
+
+	webRepoDirPath := filepath.Join(gitReposBaseDirPath, "acme", "web")
+
+	createTestGitRepo(
+		t,
+		webRepoDirPath,
+		map[string]string{
+			"index.html": "<html></html>",
+		},
+	)
+
+	runTestGitCommand(t, webRepoDirPath, "tag", "v1")
+	webV1SHA := runTestGitCommand(t, webRepoDirPath, "rev-parse", "HEAD")
+
+	runTestGitCommand(
+		t,
+		webRepoDirPath,
+		"-c", "user.name=Recode",
+		"-c", "user.email=test@recode.sh",
+		"commit", "--quiet", "--allow-empty", "--message", "After v1",
+	)
+
+	gitProvider, err := NewGitProvider("file://" + gitReposBaseDirPath)
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	devEnvRepo := NewGitRepository(gitProvider, "acme", "api")
+	devEnvRepo.Ref = "feature"
+
+	stream := &testBuildAndStartDevEnvStream{}
+	workspaceConfig := NewWorkspaceConfig()
+
+	preparedWorkspaceMetadata, err := PrepareWorkspace(
+		context.Background(),
+		stream,
+		newTestDevEnv(t),
+		NewGitRepository(gitProvider, "acme", "config"),
+		devEnvRepo,
+		workspaceConfig,
+		WorkspaceSyncOptions{},
+		ReposCloneOptions{},
+		proto.WorkspaceLayout_WORKSPACE_LAYOUT_NAME,
+	)
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	defer os.RemoveAll(preparedWorkspaceMetadata.TmpUserConfigRepoDirPath)
+	defer os.RemoveAll(preparedWorkspaceMetadata.TmpDevEnvRepoDirPath)
+
+	expectedCommitSHAs := map[string]string{
+		"api": apiFeatureSHA,
+		"web": webV1SHA,
+	}
+
+	for _, repo := range workspaceConfig.Repositories {
+		if repo.CommitSHA != expectedCommitSHAs[repo.Name] {
+			t.Fatalf(
+				"expected '%s' commit SHA to equal '%s', got '%s'",
+				repo.Name,
+				expectedCommitSHAs[repo.Name],
+				repo.CommitSHA,
+			)
+		}
+	}
+
+	resolvedRepoCommitSHAs := map[string]string{}
+
+	for _, reply := range stream.replies {
+		if reply.ResolvedRepository == nil {
+			continue
+		}
+
+		resolvedRepoCommitSHAs[reply.ResolvedRepository.Repository] = reply.ResolvedRepository.CommitSha
+	}
+
+	if resolvedRepoCommitSHAs["acme/web"] != webV1SHA {
+		t.Fatalf(
+			"expected resolved commit SHA to equal '%s', got '%s'",
+			webV1SHA,
+			resolvedRepoCommitSHAs["acme/web"],
+		)
+	}
+}
+
+func TestPrepareWorkspaceWithCloneOptions(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	gitReposBaseDirPath := t.TempDir()
+
+	createTestGitRepo(
+		t,
+		filepath.Join(gitReposBaseDirPath, "acme", "config"),
+		map[string]string{
+			entities.DevEnvUserConfigDockerfileFileName: "FROM recodesh/base-dev-env:latest\n",
+		},
+	)
+
+	monorepoDirPath := filepath.Join(gitReposBaseDirPath, "acme", "monorepo")
+
+	createTestGitRepo(
+		t,
+		monorepoDirPath,
+		map[string]string{
+			filepath.Join(
+				entities.DevEnvRepositoryConfigDirectory,
+				entities.DevEnvRepositoryDockerfileFileName,
+			): "FROM recode-user-config-image\n",
+			"services/api/main.go": "package main",
+			"services/web/main.go": "package main",
+		},
+	)
+
+	runTestGitCommand(
+		t,
+		monorepoDirPath,
+		"-c", "user.name=Recode",
+		"-c", "user.email=test@recode.sh",
+		"commit", "--quiet", "--allow-empty", "--message", "Second commit",
+	)
+
+	gitProvider, err := NewGitProvider("file://" + gitReposBaseDirPath)
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	cloneOptions, err := NewGitCloneOptions(1, "", []string{"services/api"})
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	devEnvRepo := NewGitRepository(gitProvider, "acme", "monorepo")
+	devEnvRepo.CloneOptions = cloneOptions
+
+	devEnv := newTestDevEnv(t)
+
+	preparedWorkspaceMetadata, err := PrepareWorkspace(
+		context.Background(),
+		&testBuildAndStartDevEnvStream{},
+		devEnv,
+		NewGitRepository(gitProvider, "acme", "config"),
+		devEnvRepo,
+		NewWorkspaceConfig(),
+		WorkspaceSyncOptions{},
+		ReposCloneOptions{
+			Default: cloneOptions,
+		},
+		proto.WorkspaceLayout_WORKSPACE_LAYOUT_NAME,
+	)
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	defer os.RemoveAll(preparedWorkspaceMetadata.TmpUserConfigRepoDirPath)
+	defer os.RemoveAll(preparedWorkspaceMetadata.TmpDevEnvRepoDirPath)
+
+	repoDirPath := filepath.Join(devEnv.WorkspaceDirPath, "monorepo")
+
+	// The temporary clone is moved to the
+	// workspace given that both are in the same device
+	if !strings.HasPrefix(preparedWorkspaceMetadata.TmpDevEnvRepoDockerfilePath, repoDirPath) {
+		t.Fatalf(
+			"expected Dockerfile path to be in '%s', got '%s'",
+			repoDirPath,
+			preparedWorkspaceMetadata.TmpDevEnvRepoDockerfilePath,
+		)
+	}
+
+	isShallow := runTestGitCommand(t, repoDirPath, "rev-parse", "--is-shallow-repository")
+
+	if isShallow != "true" {
+		t.Fatalf("expected repository to be shallow, got '%s'", isShallow)
+	}
+
+	expectedFilesExistence := map[string]bool{
+		preparedWorkspaceMetadata.TmpDevEnvRepoDockerfilePath:    true,
+		filepath.Join(repoDirPath, "services", "api", "main.go"): true,
+		filepath.Join(repoDirPath, "services", "web", "main.go"): false,
+	}
+
+	for filePath, expectedExistence := range expectedFilesExistence {
+		_, err := os.Stat(filePath)
+
+		if (err == nil) != expectedExistence {
+			t.Fatalf(
+				"expected '%s' existence to equal '%t', got '%+v'",
+				filePath,
+				expectedExistence,
+				err,
+			)
+		}
+	}
+}
+
+func TestPrepareWorkspaceWithSubmodules(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	// Submodules with "file://" URLs are disallowed by default
+	t.Setenv("GIT_CONFIG_COUNT", "1")
+	t.Setenv("GIT_CONFIG_KEY_0", "protocol.file.allow")
+	t.Setenv("GIT_CONFIG_VALUE_0", "always")
+
+	gitReposBaseDirPath := t.TempDir()
+
+	createTestGitRepo(
+		t,
+		filepath.Join(gitReposBaseDirPath, "acme", "config"),
+		map[string]string{
+			entities.DevEnvUserConfigDockerfileFileName: "FROM recodesh/base-dev-env:latest\n",
+		},
+	)
+
+	libRepoDirPath := filepath.Join(gitReposBaseDirPath, "acme", "lib")
+
+	createTestGitRepo(
+		t,
+		libRepoDirPath,
+		map[string]string{
+			"lib.go": "package lib",
+		},
+	)
+
+	apiRepoDirPath := filepath.Join(gitReposBaseDirPath, "acme", "api")
+
+	createTestGitRepo(
+		t,
+		apiRepoDirPath,
+		map[string]string{
+			"main.go": "package main",
+		},
+	)
+
+	runTestGitCommand(
+		t,
+		apiRepoDirPath,
+		"submodule", "--quiet", "add", "file://"+libRepoDirPath, "vendor/lib",
+	)
+
+	runTestGitCommand(
+		t,
+		apiRepoDirPath,
+		"-c", "user.name=Recode",
+		"-c", "user.email=test@recode.sh",
+		"commit", "--quiet", "--message", "Add lib",
+	)
+
+	gitProvider, err := NewGitProvider("file://" + gitReposBaseDirPath)
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	testCases := []struct {
+		test                  string
+		skipSubmodules        bool
+		expectSubmoduleCloned bool
+	}{
+		{
+			test:                  "submodules_initialized",
+			skipSubmodules:        false,
+			expectSubmoduleCloned: true,
+		},
+
+		{
+			test:                  "submodules_skipped",
+			skipSubmodules:        true,
+			expectSubmoduleCloned: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.test, func(t *testing.T) {
+			cloneOptions := GitCloneOptions{
+				SkipSubmodules: tc.skipSubmodules,
+			}
+
+			devEnvRepo := NewGitRepository(gitProvider, "acme", "api")
+			devEnvRepo.CloneOptions = cloneOptions
+
+			devEnv := newTestDevEnv(t)
+			stream := &testBuildAndStartDevEnvStream{}
+
+			preparedWorkspaceMetadata, err := PrepareWorkspace(
+				context.Background(),
+				stream,
+				devEnv,
+				NewGitRepository(gitProvider, "acme", "config"),
+				devEnvRepo,
+				NewWorkspaceConfig(),
+				WorkspaceSyncOptions{},
+				ReposCloneOptions{
+					Default: cloneOptions,
+				},
+				proto.WorkspaceLayout_WORKSPACE_LAYOUT_NAME,
+			)
+
+			if err != nil {
+				t.Fatalf("expected no error, got '%+v'", err)
+			}
+
+			defer os.RemoveAll(preparedWorkspaceMetadata.TmpUserConfigRepoDirPath)
+			defer os.RemoveAll(preparedWorkspaceMetadata.TmpDevEnvRepoDirPath)
+
+			_, err = os.Stat(
+				filepath.Join(devEnv.WorkspaceDirPath, "api", "vendor", "lib", "lib.go"),
+			)
+
+			if (err == nil) != tc.expectSubmoduleCloned {
+				t.Fatalf(
+					"expected submodule cloned to equal '%t', got '%+v'",
+					tc.expectSubmoduleCloned,
+					err,
+				)
+			}
+
+			submoduleStepFinished := false
+
+			for _, reply := range stream.replies {
+				if reply.StepEvent != nil &&
+					reply.StepEvent.StepId == "init_submodule:acme/api:vendor/lib" &&
+					reply.StepEvent.Status == proto.StepStatus_STEP_STATUS_FINISHED {
+
+					submoduleStepFinished = true
+				}
+			}
+
+			if submoduleStepFinished != tc.expectSubmoduleCloned {
+				t.Fatalf(
+					"expected submodule step finished to equal '%t', got '%t'",
+					tc.expectSubmoduleCloned,
+					submoduleStepFinished,
+				)
+			}
+		})
+	}
+}
+
+func TestCloneRepoInWorkspaceKeepsExistingDirs(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	gitProvider, err := NewGitProvider("file://" + t.TempDir())
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	// Cannot be cloned
+	repo := NewGitRepository(gitProvider, "acme", "missing")
+	repo.RetryPolicy = RetryPolicy{MaxAttempts: 1}
+
+	workspaceDirPath := t.TempDir()
+
+	testCases := []struct {
+		test           string
+		repoDirPath    string
+		repoDirFiles   []string
+		expectedExists bool
+	}{
+		{
+			test:           "not existing dir",
+			repoDirPath:    filepath.Join(workspaceDirPath, "not-existing"),
+			expectedExists: false,
+		},
+
+		{
+			test:           "empty dir",
+			repoDirPath:    filepath.Join(workspaceDirPath, "empty"),
+			repoDirFiles:   []string{},
+			expectedExists: true,
+		},
+
+		{
+			test:           "not empty dir",
+			repoDirPath:    filepath.Join(workspaceDirPath, "not-empty"),
+			repoDirFiles:   []string{"notes.txt"},
+			expectedExists: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.test, func(t *testing.T) {
+			if tc.repoDirFiles != nil {
+				err := os.Mkdir(tc.repoDirPath, os.FileMode(0755))
+
+				if err != nil {
+					t.Fatalf("expected no error, got '%+v'", err)
+				}
+			}
+
+			for _, repoDirFile := range tc.repoDirFiles {
+				err := os.WriteFile(
+					filepath.Join(tc.repoDirPath, repoDirFile),
+					[]byte("not versioned"),
+					os.FileMode(0644),
+				)
+
+				if err != nil {
+					t.Fatalf("expected no error, got '%+v'", err)
+				}
+			}
+
+			err := cloneOrSyncRepoInWorkspace(
+				context.Background(),
+				&testBuildAndStartDevEnvStream{},
+				repo,
+				repo,
+				tc.repoDirPath,
+				&PreparedWorkspaceMetadata{},
+			)
+
+			if err == nil {
+				t.Fatalf("expected error, got nothing")
+			}
+
+			_, err = os.Stat(tc.repoDirPath)
+
+			if tc.expectedExists && err != nil {
+				t.Fatalf("expected '%s' to exist, got '%+v'", tc.repoDirPath, err)
+			}
+
+			if !tc.expectedExists && !os.IsNotExist(err) {
+				t.Fatalf("expected '%s' to be removed, got '%+v'", tc.repoDirPath, err)
+			}
+
+			for _, repoDirFile := range tc.repoDirFiles {
+				_, err = os.Stat(filepath.Join(tc.repoDirPath, repoDirFile))
+
+				if err != nil {
+					t.Fatalf("expected '%s' to be kept, got '%+v'", repoDirFile, err)
+				}
+			}
+		})
+	}
+}
+
+func TestRemoveReposNoLongerInWorkspaceKeepsLocalChanges(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	srcRepoDirPath := t.TempDir()
+
+	createTestGitRepo(t, srcRepoDirPath, map[string]string{
+		"main.go": "package main",
+	})
+
+	devEnv := newTestDevEnv(t)
+
+	testCases := []struct {
+		test           string
+		repoName       string
+		gitCommands    [][]string
+		expectedExists bool
+	}{
+		{
+			test:           "clean clone",
+			repoName:       "clean",
+			expectedExists: false,
+		},
+
+		{
+			test:     "unpushed commit",
+			repoName: "unpushed-commit",
+			gitCommands: [][]string{
+				{
+					"-c", "user.name=Recode",
+					"-c", "user.email=test@recode.sh",
+					"commit", "--quiet", "--allow-empty", "--message", "Local commit",
+				},
+			},
+			expectedExists: true,
+		},
+
+		{
+			test:     "local-only branch",
+			repoName: "local-branch",
+			gitCommands: [][]string{
+				{"checkout", "--quiet", "-b", "feature"},
+				{
+					"-c", "user.name=Recode",
+					"-c", "user.email=test@recode.sh",
+					"commit", "--quiet", "--allow-empty", "--message", "Local commit",
+				},
+				{"checkout", "--quiet", "-"},
+			},
+			expectedExists: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.test, func(t *testing.T) {
+			repoDirPath := filepath.Join(devEnv.WorkspaceDirPath, tc.repoName)
+
+			runTestGitCommand(t, devEnv.WorkspaceDirPath, "clone", "--quiet", srcRepoDirPath, repoDirPath)
+
+			for _, gitCommand := range tc.gitCommands {
+				runTestGitCommand(t, repoDirPath, gitCommand...)
+			}
+
+			err := removeReposNoLongerInWorkspace(
+				context.Background(),
+				&testBuildAndStartDevEnvStream{},
+				devEnv,
+				[]WorkspaceConfigRepository{
+					{Owner: "acme", Name: tc.repoName, RootDirPath: repoDirPath},
+				},
+				&WorkspaceConfig{},
+				false,
+			)
+
+			if err != nil {
+				t.Fatalf("expected no error, got '%+v'", err)
+			}
+
+			_, err = os.Stat(repoDirPath)
+
+			if tc.expectedExists && err != nil {
+				t.Fatalf("expected '%s' to be kept, got '%+v'", repoDirPath, err)
+			}
+
+			if !tc.expectedExists && !os.IsNotExist(err) {
+				t.Fatalf("expected '%s' to be removed, got '%+v'", repoDirPath, err)
+			}
+		})
+	}
+}
@@ -15,6 +15,8 @@ type VSCodeWorkspaceConfig struct {
 
 type VSCodeWorkspaceConfigFolder struct {
 	Path string `json:"path"`
+	// Displayed instead of the folder base name
+	Name string `json:"name,omitempty"`
 }
 
 type VSCodeWorkspaceConfigExtensions struct {
@@ -5,24 +5,44 @@ import (
 	"encoding/json"
 	"errors"
 	"io"
+	"regexp"
+	"strconv"
+	"strings"
 )
 
 type BuildOutput struct {
+	ID          string           `json:"id"`
 	Stream      string           `json:"stream"`
 	Error       string           `json:"error"`
 	ErrorDetail BuildErrorDetail `json:"errorDetail"`
+	// Contains the progress of BuildKit builds
+	Aux json.RawMessage `json:"aux"`
 }
 
 type BuildErrorDetail struct {
 	Message string `json:"message"`
 }
 
+// BuildStep represents a "Step 2/10 : RUN ..." build output line.
+type BuildStep struct {
+	Current     int
+	Total       int
+	Instruction string
+}
+
+var buildStepRegExp = regexp.MustCompile(`^Step (\d+)/(\d+) : (.*)`)
+
 func HandleBuildOutput(
 	buildOutputReader io.Reader,
 	streamHandler func(logLine string) error,
+	stepHandler func(step BuildStep) error,
 ) error {
 
 	scanner := bufio.NewScanner(buildOutputReader)
+	// BuildKit traces may exceed the default max line size
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	buildKitDisplay := newBuildKitTraceDisplay()
 
 	for scanner.Scan() {
 		buildOutputJSON := scanner.Text()
@@ -35,13 +55,43 @@ func HandleBuildOutput(
 		}
 
 		if buildOutput.Error != "" {
+			// The last lines of the failing vertex may
+			// not end with a newline (eg: "exit code: 1")
+			err = buildKitDisplay.flush(streamHandler)
+
+			if err != nil {
+				return err
+			}
+
 			return errors.New(buildOutput.ErrorDetail.Message)
 		}
 
+		if buildOutput.ID == buildKitTraceID && len(buildOutput.Aux) > 0 {
+			err = buildKitDisplay.handleTrace(
+				buildOutput.Aux,
+				streamHandler,
+				stepHandler,
+			)
+
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
 		if buildOutput.Stream == "" {
 			continue
 		}
 
+		if buildStep, isBuildStep := ParseBuildStep(buildOutput.Stream); isBuildStep {
+			err = stepHandler(*buildStep)
+
+			if err != nil {
+				return err
+			}
+		}
+
 		err = streamHandler(
 			buildOutput.Stream,
 		)
@@ -51,5 +101,32 @@ func HandleBuildOutput(
 		}
 	}
 
-	return scanner.Err()
+	err := scanner.Err()
+
+	if err != nil {
+		return err
+	}
+
+	return buildKitDisplay.flush(streamHandler)
+}
+
+func ParseBuildStep(logLine string) (*BuildStep, bool) {
+	matches := buildStepRegExp.FindStringSubmatch(
+		strings.TrimSpace(logLine),
+	)
+
+	if matches == nil {
+		return nil, false
+	}
+
+	// Errors are ignored given that
+	// the regexp only matches digits
+	current, _ := strconv.Atoi(matches[1])
+	total, _ := strconv.Atoi(matches[2])
+
+	return &BuildStep{
+		Current:     current,
+		Total:       total,
+		Instruction: matches[3],
+	}, true
 }
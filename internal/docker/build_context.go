@@ -0,0 +1,116 @@
+package docker
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/docker/docker/pkg/fileutils"
+	"github.com/moby/buildkit/frontend/dockerfile/dockerignore"
+)
+
+const dockerignoreFileName = ".dockerignore"
+
+// ReadDockerignorePatterns returns the exclusion patterns set in the
+// ".dockerignore" file of the build context (if any). Like the Docker
+// CLI, the Dockerfile and the ".dockerignore" file are always sent.
+func ReadDockerignorePatterns(
+	buildContextDirPath string,
+	dockerfilePath string,
+) ([]string, error) {
+
+	dockerignoreFile, err := os.Open(
+		filepath.Join(buildContextDirPath, dockerignoreFileName),
+	)
+
+	if err != nil && os.IsNotExist(err) {
+		return []string{}, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer dockerignoreFile.Close()
+
+	excludePatterns, err := dockerignore.ReadAll(dockerignoreFile)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, buildFilePath := range []string{
+		dockerignoreFileName,
+		// Relative to the build context
+		filepath.Clean(dockerfilePath),
+	} {
+
+		isExcluded, err := fileutils.Matches(buildFilePath, excludePatterns)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if isExcluded {
+			excludePatterns = append(excludePatterns, "!"+buildFilePath)
+		}
+	}
+
+	return excludePatterns, nil
+}
+
+// ComputeBuildContextSize returns the total size of the
+// files of the build context that are not excluded
+func ComputeBuildContextSize(
+	buildContextDirPath string,
+	excludePatterns []string,
+) (int64, error) {
+
+	patternMatcher, err := fileutils.NewPatternMatcher(excludePatterns)
+
+	if err != nil {
+		return 0, err
+	}
+
+	var buildContextSize int64
+
+	err = filepath.Walk(
+		buildContextDirPath,
+		func(filePath string, fileInfo os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+
+			relFilePath, err := filepath.Rel(buildContextDirPath, filePath)
+
+			if err != nil || relFilePath == "." {
+				return err
+			}
+
+			isExcluded, err := patternMatcher.Matches(relFilePath)
+
+			if err != nil {
+				return err
+			}
+
+			// Files in excluded directories may be
+			// re-included by exception patterns
+			if isExcluded && fileInfo.IsDir() && !patternMatcher.Exclusions() {
+				return filepath.SkipDir
+			}
+
+			if isExcluded || !fileInfo.Mode().IsRegular() {
+				return nil
+			}
+
+			buildContextSize += fileInfo.Size()
+
+			return nil
+		},
+	)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return buildContextSize, nil
+}
@@ -0,0 +1,117 @@
+package docker
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadDockerignorePatternsAndComputeBuildContextSize(t *testing.T) {
+	testCases := []struct {
+		test                    string
+		dockerignore            *string
+		expectedExcludePatterns []string
+		expectedSize            int64
+	}{
+		{
+			test:                    "no_dockerignore",
+			dockerignore:            nil,
+			expectedExcludePatterns: []string{},
+			// Dockerfile + ".git" + "node_modules" + "src"
+			expectedSize: 10 + 100 + 1000 + 5,
+		},
+
+		{
+			test:                    "excluded_directories",
+			dockerignore:            stringPtr(".git\nnode_modules\n"),
+			expectedExcludePatterns: []string{".git", "node_modules"},
+			// Dockerfile + "src" + ".dockerignore"
+			expectedSize: 10 + 5 + 18,
+		},
+
+		{
+			test:         "dockerfile_and_dockerignore_are_kept",
+			dockerignore: stringPtr("*\n!src\n"),
+			expectedExcludePatterns: []string{
+				"*",
+				"!src",
+				"!.dockerignore",
+				"!dev_env.Dockerfile",
+			},
+			// Dockerfile + "src" + ".dockerignore"
+			expectedSize: 10 + 5 + 7,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.test, func(t *testing.T) {
+			buildContextDirPath := t.TempDir()
+
+			files := map[string]string{
+				"dev_env.Dockerfile":        strings.Repeat("d", 10),
+				".git/objects/pack":         strings.Repeat("g", 100),
+				"node_modules/lib/index.js": strings.Repeat("n", 1000),
+				"src/a":                     strings.Repeat("s", 5),
+			}
+
+			if tc.dockerignore != nil {
+				files[dockerignoreFileName] = *tc.dockerignore
+			}
+
+			for filePath, fileContent := range files {
+				fullFilePath := filepath.Join(buildContextDirPath, filePath)
+
+				err := os.MkdirAll(filepath.Dir(fullFilePath), os.FileMode(0755))
+
+				if err != nil {
+					t.Fatalf("expected no error, got '%+v'", err)
+				}
+
+				err = os.WriteFile(fullFilePath, []byte(fileContent), os.FileMode(0644))
+
+				if err != nil {
+					t.Fatalf("expected no error, got '%+v'", err)
+				}
+			}
+
+			excludePatterns, err := ReadDockerignorePatterns(
+				buildContextDirPath,
+				"dev_env.Dockerfile",
+			)
+
+			if err != nil {
+				t.Fatalf("expected no error, got '%+v'", err)
+			}
+
+			if strings.Join(excludePatterns, ",") != strings.Join(tc.expectedExcludePatterns, ",") {
+				t.Fatalf(
+					"expected exclude patterns to equal '%v', got '%v'",
+					tc.expectedExcludePatterns,
+					excludePatterns,
+				)
+			}
+
+			buildContextSize, err := ComputeBuildContextSize(
+				buildContextDirPath,
+				excludePatterns,
+			)
+
+			if err != nil {
+				t.Fatalf("expected no error, got '%+v'", err)
+			}
+
+			if buildContextSize != tc.expectedSize {
+				t.Fatalf(
+					"expected build context size to equal '%d', got '%d'",
+					tc.expectedSize,
+					buildContextSize,
+				)
+			}
+		})
+	}
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
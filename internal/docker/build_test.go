@@ -0,0 +1,260 @@
+package docker
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	controlapi "github.com/moby/buildkit/api/services/control"
+)
+
+func TestParseBuildStep(t *testing.T) {
+	testCases := []struct {
+		test            string
+		logLine         string
+		expectedStep    *BuildStep
+		expectedIsAStep bool
+	}{
+		{
+			test:    "step",
+			logLine: "Step 2/10 : RUN apt-get update\n",
+			expectedStep: &BuildStep{
+				Current:     2,
+				Total:       10,
+				Instruction: "RUN apt-get update",
+			},
+			expectedIsAStep: true,
+		},
+
+		{
+			test:            "not_a_step",
+			logLine:         " ---> Running in 5a2e0c0d6b3f\n",
+			expectedStep:    nil,
+			expectedIsAStep: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.test, func(t *testing.T) {
+			step, isAStep := ParseBuildStep(tc.logLine)
+
+			if isAStep != tc.expectedIsAStep {
+				t.Fatalf(
+					"expected is a step to equal '%t', got '%t'",
+					tc.expectedIsAStep,
+					isAStep,
+				)
+			}
+
+			if tc.expectedStep == nil {
+				return
+			}
+
+			if *step != *tc.expectedStep {
+				t.Fatalf(
+					"expected step to equal '%+v', got '%+v'",
+					*tc.expectedStep,
+					*step,
+				)
+			}
+		})
+	}
+}
+
+func TestHandleBuildOutput(t *testing.T) {
+	buildOutput := strings.Join([]string{
+		`{"stream":"Step 1/2 : FROM ubuntu\n"}`,
+		`{"stream":" ---> 54c9d81cbb44\n"}`,
+		`{"stream":"Step 2/2 : RUN exit 1\n"}`,
+		`{"errorDetail":{"message":"The command returned a non-zero code: 1"},"error":"The command returned a non-zero code: 1"}`,
+	}, "\n")
+
+	logLines := []string{}
+	steps := []BuildStep{}
+
+	err := HandleBuildOutput(
+		strings.NewReader(buildOutput),
+		func(logLine string) error {
+			logLines = append(logLines, logLine)
+			return nil
+		},
+		func(step BuildStep) error {
+			steps = append(steps, step)
+			return nil
+		},
+	)
+
+	if err == nil || err.Error() != "The command returned a non-zero code: 1" {
+		t.Fatalf("expected build error, got '%+v'", err)
+	}
+
+	if len(logLines) != 3 {
+		t.Fatalf("expected 3 log lines, got %d", len(logLines))
+	}
+
+	if len(steps) != 2 || steps[1].Current != 2 || steps[1].Total != 2 {
+		t.Fatalf("expected 2 build steps, got '%+v'", steps)
+	}
+}
+
+func TestHandleBuildOutputWithBuildKitTraces(t *testing.T) {
+	startedAt := time.Now()
+	completedAt := startedAt.Add(1500 * time.Millisecond)
+
+	traces := []*controlapi.StatusResponse{
+		{
+			Vertexes: []*controlapi.Vertex{
+				{Digest: "sha256:a", Name: "[1/2] FROM docker.io/library/ubuntu", Cached: true, Completed: &completedAt},
+				{Digest: "sha256:b", Name: "[2/2] RUN make", Started: &startedAt},
+			},
+		},
+
+		{
+			Vertexes: []*controlapi.Vertex{
+				{Digest: "sha256:b", Name: "[2/2] RUN make", Started: &startedAt, Completed: &completedAt},
+			},
+			Logs: []*controlapi.VertexLog{
+				{Vertex: "sha256:b", Msg: []byte("building...\ndone")},
+			},
+		},
+	}
+
+	logLines := []string{}
+	steps := []BuildStep{}
+
+	err := HandleBuildOutput(
+		strings.NewReader(buildBuildKitTracesOutput(t, traces)),
+		func(logLine string) error {
+			logLines = append(logLines, logLine)
+			return nil
+		},
+		func(step BuildStep) error {
+			steps = append(steps, step)
+			return nil
+		},
+	)
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	expectedLogLines := []string{
+		"#1 [1/2] FROM docker.io/library/ubuntu\n",
+		"#1 CACHED\n",
+		"#2 [2/2] RUN make\n",
+		"#2 building...\n",
+		"#2 done\n",
+		"#2 DONE 1.5s\n",
+	}
+
+	if strings.Join(logLines, "") != strings.Join(expectedLogLines, "") {
+		t.Fatalf("expected log lines '%q', got '%q'", expectedLogLines, logLines)
+	}
+
+	if len(steps) != 2 || steps[1].Current != 2 || steps[1].Instruction != "RUN make" {
+		t.Fatalf("expected 2 build steps, got '%+v'", steps)
+	}
+}
+
+func TestHandleBuildOutputWithSplitBuildKitLogs(t *testing.T) {
+	startedAt := time.Now()
+	completedAt := startedAt.Add(time.Second)
+
+	traces := []*controlapi.StatusResponse{
+		{
+			Vertexes: []*controlapi.Vertex{
+				{Digest: "sha256:a", Name: "[1/2] RUN npm ci", Started: &startedAt},
+				{Digest: "sha256:b", Name: "[2/2] RUN make", Started: &startedAt},
+			},
+			Logs: []*controlapi.VertexLog{
+				{Vertex: "sha256:a", Msg: []byte("npm ERR! token npm-")},
+				{Vertex: "sha256:b", Msg: []byte("building")},
+			},
+		},
+
+		{
+			Logs: []*controlapi.VertexLog{
+				{Vertex: "sha256:a", Msg: []byte("secret is invalid\nnpm ERR! exit")},
+				{Vertex: "sha256:b", Msg: []byte("...\n")},
+			},
+		},
+
+		{
+			Vertexes: []*controlapi.Vertex{
+				{Digest: "sha256:a", Name: "[1/2] RUN npm ci", Started: &startedAt, Completed: &completedAt},
+			},
+		},
+
+		// Never completed (eg: canceled build)
+		{
+			Logs: []*controlapi.VertexLog{
+				{Vertex: "sha256:b", Msg: []byte("linking")},
+			},
+		},
+	}
+
+	logLines := []string{}
+
+	err := HandleBuildOutput(
+		strings.NewReader(buildBuildKitTracesOutput(t, traces)),
+		func(logLine string) error {
+			logLines = append(logLines, logLine)
+			return nil
+		},
+		func(step BuildStep) error {
+			return nil
+		},
+	)
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	expectedLogLines := []string{
+		"#1 [1/2] RUN npm ci\n",
+		"#2 [2/2] RUN make\n",
+		// Sent in one log line to let secrets be redacted
+		"#1 npm ERR! token npm-secret is invalid\n",
+		"#2 building...\n",
+		"#1 npm ERR! exit\n",
+		"#1 DONE 1.0s\n",
+		"#2 linking\n",
+	}
+
+	if strings.Join(logLines, "") != strings.Join(expectedLogLines, "") {
+		t.Fatalf("expected log lines '%q', got '%q'", expectedLogLines, logLines)
+	}
+}
+
+// buildBuildKitTracesOutput returns the build output
+// (one JSON message per line) containing the passed traces
+func buildBuildKitTracesOutput(
+	t *testing.T,
+	traces []*controlapi.StatusResponse,
+) string {
+
+	buildOutputLines := []string{}
+
+	for _, trace := range traces {
+		traceAsBytes, err := trace.Marshal()
+
+		if err != nil {
+			t.Fatalf("expected no error, got '%+v'", err)
+		}
+
+		// "[]byte" are encoded in base64
+		traceAsJSON, err := json.Marshal(traceAsBytes)
+
+		if err != nil {
+			t.Fatalf("expected no error, got '%+v'", err)
+		}
+
+		buildOutputLines = append(
+			buildOutputLines,
+			`{"id":"moby.buildkit.trace","aux":`+string(traceAsJSON)+`}`,
+		)
+	}
+
+	return strings.Join(buildOutputLines, "\n")
+}
@@ -0,0 +1,329 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/client"
+	controlapi "github.com/moby/buildkit/api/services/control"
+	"github.com/moby/buildkit/session"
+)
+
+// ID of the build output messages that contain
+// the BuildKit progress (encoded in "aux")
+const buildKitTraceID = "moby.buildkit.trace"
+
+// Partial log lines (without newline) are sent
+// as is once they exceed this size
+const buildKitMaxPartialLogLineSize = 64 * 1024
+
+// Matches the names of the BuildKit vertexes that correspond
+// to Dockerfile instructions (eg: "[builder 2/5] RUN make")
+var buildKitStepRegExp = regexp.MustCompile(`^\[(?:[^\]]+ )?(\d+)/(\d+)\] (.*)`)
+
+// NewBuildKitSession starts a session that lets the
+// BuildKit builder of the daemon call the passed attachables
+// (eg: secrets providers). The session must be closed once
+// the build is finished.
+func NewBuildKitSession(
+	ctx context.Context,
+	dockerClient *client.Client,
+	attachables ...session.Attachable,
+) (*session.Session, error) {
+
+	buildSession, err := session.NewSession(ctx, "recode-agent", "")
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, attachable := range attachables {
+		buildSession.Allow(attachable)
+	}
+
+	go func() {
+		// Returns once the session is closed. Errors are
+		// reported by the builds that use the session.
+		_ = buildSession.Run(
+			ctx,
+			func(
+				ctx context.Context,
+				proto string,
+				meta map[string][]string,
+			) (net.Conn, error) {
+
+				return dockerClient.DialHijack(ctx, "/session", proto, meta)
+			},
+		)
+	}()
+
+	return buildSession, nil
+}
+
+// buildKitTraceDisplay converts the BuildKit progress
+// into log lines similar to the "plain" progress of the
+// Docker CLI (eg: "#5 [2/4] RUN make"). The logs of each vertex
+// are sent line by line: BuildKit log messages may end mid-line
+// (a secret split across two messages must be sent in one line
+// to be redacted).
+type buildKitTraceDisplay struct {
+	vertexIndexes     map[string]int
+	startedVertexes   map[string]bool
+	completedVertexes map[string]bool
+	// Indexed by vertex digest
+	partialLogLines map[string]string
+	// Vertexes digests in the order of their first partial log line
+	partialLogLinesOrder []string
+}
+
+func newBuildKitTraceDisplay() *buildKitTraceDisplay {
+	return &buildKitTraceDisplay{
+		vertexIndexes:     map[string]int{},
+		startedVertexes:   map[string]bool{},
+		completedVertexes: map[string]bool{},
+		partialLogLines:   map[string]string{},
+	}
+}
+
+func (b *buildKitTraceDisplay) vertexIndex(vertexDigest string) int {
+	if vertexIndex, ok := b.vertexIndexes[vertexDigest]; ok {
+		return vertexIndex
+	}
+
+	b.vertexIndexes[vertexDigest] = len(b.vertexIndexes) + 1
+
+	return b.vertexIndexes[vertexDigest]
+}
+
+func (b *buildKitTraceDisplay) handleTrace(
+	traceAsJSON json.RawMessage,
+	streamHandler func(logLine string) error,
+	stepHandler func(step BuildStep) error,
+) error {
+
+	// The trace is a protobuf message encoded
+	// in base64 (like all "[]byte" in JSON)
+	var traceAsBytes []byte
+	err := json.Unmarshal(traceAsJSON, &traceAsBytes)
+
+	if err != nil {
+		return err
+	}
+
+	var trace controlapi.StatusResponse
+	err = trace.Unmarshal(traceAsBytes)
+
+	if err != nil {
+		return err
+	}
+
+	// The completion of the vertexes that have logs
+	// in the trace is handled after their logs
+	vertexesWithLogs := map[string]bool{}
+
+	for _, vertexLog := range trace.Logs {
+		vertexesWithLogs[string(vertexLog.Vertex)] = true
+	}
+
+	logLines := []string{}
+
+	for _, vertex := range trace.Vertexes {
+		vertexDigest := string(vertex.Digest)
+		vertexIndex := b.vertexIndex(vertexDigest)
+
+		// Cached vertexes may be completed without being started
+		if (vertex.Started != nil || vertex.Completed != nil) &&
+			!b.startedVertexes[vertexDigest] {
+
+			b.startedVertexes[vertexDigest] = true
+
+			if buildStep, isBuildStep := parseBuildKitStep(vertex.Name); isBuildStep {
+				err = stepHandler(*buildStep)
+
+				if err != nil {
+					return err
+				}
+			}
+
+			logLines = append(logLines, fmt.Sprintf("#%d %s\n", vertexIndex, vertex.Name))
+		}
+
+		if !vertexesWithLogs[vertexDigest] {
+			logLines = append(logLines, b.completeVertex(vertex)...)
+		}
+	}
+
+	for _, vertexLog := range trace.Logs {
+		logLines = append(
+			logLines,
+			b.bufferVertexLog(string(vertexLog.Vertex), string(vertexLog.Msg))...,
+		)
+	}
+
+	for _, vertex := range trace.Vertexes {
+		if vertexesWithLogs[string(vertex.Digest)] {
+			logLines = append(logLines, b.completeVertex(vertex)...)
+		}
+	}
+
+	return sendBuildKitLogLines(logLines, streamHandler)
+}
+
+// completeVertex returns the log lines sent once the vertex
+// is completed (preceded by its partial log line, if any)
+func (b *buildKitTraceDisplay) completeVertex(
+	vertex *controlapi.Vertex,
+) []string {
+
+	vertexDigest := string(vertex.Digest)
+	vertexIndex := b.vertexIndex(vertexDigest)
+
+	if vertex.Completed == nil || b.completedVertexes[vertexDigest] {
+		return []string{}
+	}
+
+	b.completedVertexes[vertexDigest] = true
+
+	logLines := []string{}
+
+	if partialLogLine, hasPartialLogLine := b.takePartialLogLine(vertexDigest); hasPartialLogLine {
+		logLines = append(logLines, fmt.Sprintf("#%d %s\n", vertexIndex, partialLogLine))
+	}
+
+	if len(vertex.Error) > 0 {
+		return append(logLines, fmt.Sprintf("#%d ERROR: %s\n", vertexIndex, vertex.Error))
+	}
+
+	if vertex.Cached {
+		return append(logLines, fmt.Sprintf("#%d CACHED\n", vertexIndex))
+	}
+
+	vertexDuration := 0.0
+
+	if vertex.Started != nil {
+		vertexDuration = vertex.Completed.Sub(*vertex.Started).Seconds()
+	}
+
+	return append(logLines, fmt.Sprintf("#%d DONE %.1fs\n", vertexIndex, vertexDuration))
+}
+
+// bufferVertexLog returns the complete log lines of the vertex
+// (prefixed with its index). The end of the passed log message
+// is kept until the next one if it doesn't end with a newline.
+func (b *buildKitTraceDisplay) bufferVertexLog(
+	vertexDigest string,
+	vertexLog string,
+) []string {
+
+	vertexIndex := b.vertexIndex(vertexDigest)
+	partialLogLine, _ := b.takePartialLogLine(vertexDigest)
+	logLines := []string{}
+
+	for _, line := range strings.SplitAfter(partialLogLine+vertexLog, "\n") {
+		if len(line) == 0 {
+			continue
+		}
+
+		if !strings.HasSuffix(line, "\n") &&
+			len(line) <= buildKitMaxPartialLogLineSize {
+
+			b.partialLogLines[vertexDigest] = line
+			b.partialLogLinesOrder = append(b.partialLogLinesOrder, vertexDigest)
+			continue
+		}
+
+		if !strings.HasSuffix(line, "\n") {
+			line += "\n"
+		}
+
+		logLines = append(logLines, fmt.Sprintf("#%d %s", vertexIndex, line))
+	}
+
+	return logLines
+}
+
+// takePartialLogLine returns (and forgets)
+// the partial log line kept for the vertex
+func (b *buildKitTraceDisplay) takePartialLogLine(vertexDigest string) (string, bool) {
+	partialLogLine, hasPartialLogLine := b.partialLogLines[vertexDigest]
+
+	if !hasPartialLogLine {
+		return "", false
+	}
+
+	delete(b.partialLogLines, vertexDigest)
+
+	for orderIndex, orderedVertexDigest := range b.partialLogLinesOrder {
+		if orderedVertexDigest == vertexDigest {
+			b.partialLogLinesOrder = append(
+				b.partialLogLinesOrder[:orderIndex],
+				b.partialLogLinesOrder[orderIndex+1:]...,
+			)
+			break
+		}
+	}
+
+	return partialLogLine, true
+}
+
+// flush sends the partial log lines of the
+// vertexes that were not completed (eg: failed builds)
+func (b *buildKitTraceDisplay) flush(
+	streamHandler func(logLine string) error,
+) error {
+
+	logLines := []string{}
+
+	for len(b.partialLogLinesOrder) > 0 {
+		vertexDigest := b.partialLogLinesOrder[0]
+		partialLogLine, _ := b.takePartialLogLine(vertexDigest)
+
+		logLines = append(logLines, fmt.Sprintf(
+			"#%d %s\n",
+			b.vertexIndex(vertexDigest),
+			partialLogLine,
+		))
+	}
+
+	return sendBuildKitLogLines(logLines, streamHandler)
+}
+
+func sendBuildKitLogLines(
+	logLines []string,
+	streamHandler func(logLine string) error,
+) error {
+
+	for _, logLine := range logLines {
+		err := streamHandler(logLine)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func parseBuildKitStep(vertexName string) (*BuildStep, bool) {
+	matches := buildKitStepRegExp.FindStringSubmatch(vertexName)
+
+	if matches == nil {
+		return nil, false
+	}
+
+	// Errors are ignored given that
+	// the regexp only matches digits
+	current, _ := strconv.Atoi(matches[1])
+	total, _ := strconv.Atoi(matches[2])
+
+	return &BuildStep{
+		Current:     current,
+		Total:       total,
+		Instruction: matches[3],
+	}, true
+}
@@ -66,3 +66,31 @@ func IsContainerRunning(
 
 	return isContainerRunning, nil
 }
+
+// LookupContainerStartedAt returns the time at which the running
+// container was started (or an empty string if not running).
+// Used to know if the container was restarted.
+func LookupContainerStartedAt(
+	dockerClient *client.Client,
+	containerName string,
+) (string, error) {
+
+	containerJSON, err := dockerClient.ContainerInspect(
+		context.TODO(),
+		containerName,
+	)
+
+	if err != nil && client.IsErrNotFound(err) {
+		return "", nil
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	if containerJSON.State == nil || !containerJSON.State.Running {
+		return "", nil
+	}
+
+	return containerJSON.State.StartedAt, nil
+}
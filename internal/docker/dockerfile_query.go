@@ -102,3 +102,30 @@ func LookupDockerfileLabelValue(
 
 	return lastLabelValueThatMatch, nil
 }
+
+// LookupDockerfileArgNames returns the names of the
+// build args declared using "ARG" instructions
+// (including the ones declared before "FROM")
+func LookupDockerfileArgNames(dockerfilePath string) ([]string, error) {
+	dockerfileCmds, err := parseDockerfile(dockerfilePath)
+
+	if err != nil {
+		return nil, err
+	}
+
+	argNames := []string{}
+
+	for _, dockerfileCmd := range dockerfileCmds {
+		if dockerfileCmd.cmd != "ARG" {
+			continue
+		}
+
+		// eg: ["NODE_VERSION=18"] or ["PYTHON_VERSION"]
+		for _, argNameAndDefaultValue := range dockerfileCmd.value {
+			argName := strings.SplitN(argNameAndDefaultValue, "=", 2)[0]
+			argNames = append(argNames, argName)
+		}
+	}
+
+	return argNames, nil
+}
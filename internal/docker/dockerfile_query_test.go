@@ -2,6 +2,7 @@ package docker
 
 import (
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -109,3 +110,43 @@ func TestLookupDockerfileLabelValue(t *testing.T) {
 		})
 	}
 }
+
+func TestLookupDockerfileArgNames(t *testing.T) {
+	testCases := []struct {
+		test             string
+		dockerfilePath   string
+		expectedArgNames []string
+	}{
+		{
+			test:             "no_args",
+			dockerfilePath:   "base.Dockerfile",
+			expectedArgNames: []string{},
+		},
+
+		{
+			test:             "args",
+			dockerfilePath:   "args.Dockerfile",
+			expectedArgNames: []string{"BASE_IMAGE", "NODE_VERSION", "PYTHON_VERSION"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.test, func(t *testing.T) {
+			argNames, err := LookupDockerfileArgNames(
+				filepath.Join("./testdata", tc.dockerfilePath),
+			)
+
+			if err != nil {
+				t.Fatalf("expected no error, got '%+v'", err)
+			}
+
+			if strings.Join(argNames, ",") != strings.Join(tc.expectedArgNames, ",") {
+				t.Fatalf(
+					"expected arg names to equal '%v', got '%v'",
+					tc.expectedArgNames,
+					argNames,
+				)
+			}
+		})
+	}
+}
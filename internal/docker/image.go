@@ -0,0 +1,29 @@
+package docker
+
+import (
+	"context"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+)
+
+func LookupImage(
+	dockerClient *client.Client,
+	imageName string,
+) (*types.ImageInspect, error) {
+
+	image, _, err := dockerClient.ImageInspectWithRaw(
+		context.TODO(),
+		imageName,
+	)
+
+	if err != nil && client.IsErrNotFound(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &image, nil
+}
@@ -0,0 +1,162 @@
+package grpcserver
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/recode-sh/agent/constants"
+	"github.com/recode-sh/agent/internal/devenv"
+	"github.com/recode-sh/agent/internal/docker"
+	"github.com/recode-sh/agent/proto"
+)
+
+func (s *agentServer) GetDevEnvStatus(
+	ctx context.Context,
+	req *proto.GetDevEnvStatusRequest,
+) (*proto.GetDevEnvStatusReply, error) {
+
+	devEnv, err := newDevEnvFromRequest(req.DevEnvName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	dockerClient, err := docker.NewDefaultClient()
+
+	if err != nil {
+		return nil, err
+	}
+
+	reply := &proto.GetDevEnvStatusReply{
+		DevEnvName:   devEnv.Name,
+		Repositories: []*proto.DevEnvRepositoryStatus{},
+		AgentVersion: constants.AgentVersion,
+		CurrentOperation: buildOperationStatus(
+			s.operationManager.Current(),
+		),
+	}
+
+	dockerContainer, err := docker.LookupContainer(
+		dockerClient,
+		devEnv.DockerContainerName,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if dockerContainer != nil {
+		reply.ContainerState = dockerContainer.State
+	}
+
+	dockerImage, err := docker.LookupImage(
+		dockerClient,
+		devEnv.DockerImageName,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if dockerImage != nil {
+		reply.ImageId = dockerImage.ID
+		reply.ImageBuiltAt = dockerImage.Created
+	}
+
+	devEnvImages, err := devenv.LoadDevEnvImages(devEnv)
+
+	if err != nil {
+		return nil, err
+	}
+
+	reply.Images = []*proto.DevEnvImage{}
+
+	for _, devEnvImage := range devEnvImages {
+		if dockerImage != nil && devEnvImage.ImageID == dockerImage.ID {
+			reply.ImageTag = devEnvImage.Tag
+		}
+
+		reply.Images = append(reply.Images, buildDevEnvImageStatus(devEnvImage))
+	}
+
+	workspaceConfig, err := devenv.LoadWorkspaceConfig(
+		devEnv.WorkspaceConfigFilePath,
+	)
+
+	// The workspace config is created during instance init
+	// (or during the first build for named development environments)
+	if err != nil && os.IsNotExist(err) {
+		return reply, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	reply.Repositories, err = buildDevEnvRepositoriesStatus(
+		devEnv,
+		workspaceConfig,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return reply, nil
+}
+
+func buildDevEnvRepositoriesStatus(
+	devEnv *devenv.DevEnv,
+	workspaceConfig *devenv.WorkspaceConfig,
+) ([]*proto.DevEnvRepositoryStatus, error) {
+
+	reposStatus := []*proto.DevEnvRepositoryStatus{}
+
+	for _, repo := range workspaceConfig.Repositories {
+		hooksStatus := []*proto.DevEnvRepositoryHookStatus{}
+
+		for _, hook := range repo.Hooks {
+			hookStatus := &proto.DevEnvRepositoryHookStatus{
+				ScriptFilePath: hook.ScriptFilePath,
+				Type:           string(hook.Type),
+				TimeoutSeconds: int64(hook.Timeout().Seconds()),
+			}
+
+			if hook.LastExitCode != nil {
+				lastExitCode := int32(*hook.LastExitCode)
+				hookStatus.LastExitCode = &lastExitCode
+			}
+
+			hookResult, err := devenv.LoadWorkspaceHookResult(devEnv, repo, hook)
+
+			if err != nil {
+				return nil, err
+			}
+
+			if hookResult != nil {
+				hookStatus.LastRun = &proto.HookRunResult{
+					ExitCode:   int32(hookResult.ExitCode),
+					TimedOut:   hookResult.TimedOut,
+					StartedAt:  hookResult.StartedAt.Format(time.RFC3339),
+					DurationMs: hookResult.DurationMs,
+					OutputTail: hookResult.OutputTail,
+				}
+			}
+
+			hooksStatus = append(hooksStatus, hookStatus)
+		}
+
+		reposStatus = append(reposStatus, &proto.DevEnvRepositoryStatus{
+			Owner:        repo.Owner,
+			Name:         repo.Name,
+			RootDirPath:  repo.RootDirPath,
+			IsDevEnvRepo: repo.IsDevEnvRepo,
+			Hooks:        hooksStatus,
+			Ref:          repo.Ref,
+			CommitSha:    repo.CommitSHA,
+		})
+	}
+
+	return reposStatus, nil
+}
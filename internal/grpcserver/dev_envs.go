@@ -0,0 +1,80 @@
+package grpcserver
+
+import (
+	"context"
+
+	"github.com/recode-sh/agent/internal/devenv"
+	"github.com/recode-sh/agent/internal/docker"
+	"github.com/recode-sh/agent/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *agentServer) ListDevEnvs(
+	ctx context.Context,
+	req *proto.ListDevEnvsRequest,
+) (*proto.ListDevEnvsReply, error) {
+
+	dockerClient, err := docker.NewDefaultClient()
+
+	if err != nil {
+		return nil, err
+	}
+
+	devEnvs, err := devenv.ListDevEnvs()
+
+	if err != nil {
+		return nil, err
+	}
+
+	reply := &proto.ListDevEnvsReply{
+		DevEnvs: []*proto.DevEnvSummary{},
+	}
+
+	for _, devEnv := range devEnvs {
+		devEnvSummary := &proto.DevEnvSummary{
+			Name:             devEnv.Name,
+			WorkspaceDirPath: devEnv.WorkspaceDirPath,
+		}
+
+		dockerContainer, err := docker.LookupContainer(
+			dockerClient,
+			devEnv.DockerContainerName,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if dockerContainer != nil {
+			devEnvSummary.ContainerState = dockerContainer.State
+		}
+
+		dockerImage, err := docker.LookupImage(
+			dockerClient,
+			devEnv.DockerImageName,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if dockerImage != nil {
+			devEnvSummary.ImageId = dockerImage.ID
+		}
+
+		reply.DevEnvs = append(reply.DevEnvs, devEnvSummary)
+	}
+
+	return reply, nil
+}
+
+func newDevEnvFromRequest(devEnvName string) (*devenv.DevEnv, error) {
+	devEnv, err := devenv.NewDevEnv(devEnvName)
+
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return devEnv, nil
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/recode-sh/agent/constants"
 	"github.com/recode-sh/agent/internal/devenv"
+	"github.com/recode-sh/agent/internal/operations"
 	"github.com/recode-sh/agent/proto"
 )
 
@@ -23,6 +24,56 @@ func (s *agentServer) InitInstance(
 	stream proto.Agent_InitInstanceServer,
 ) error {
 
+	operation, inProgressOperation, err := s.acquireOperation(
+		stream.Context(),
+		operations.TypeInitInstance,
+		"", // Instance-wide operation
+		req.OnConflict,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	if inProgressOperation != nil {
+		err = sendOperationIDHeader(stream, inProgressOperation)
+
+		if err != nil {
+			return err
+		}
+
+		return attachToInitInstanceOperation(inProgressOperation, stream)
+	}
+
+	err = sendOperationIDHeader(stream, operation)
+
+	if err != nil {
+		s.operationManager.Finish(operation, err)
+		return err
+	}
+
+	err = initInstance(
+		req,
+		operationInitInstanceStream{
+			Agent_InitInstanceServer: stream,
+			client: newOperationClient(
+				stream.Context(),
+				operation,
+				req.ContinueOnDisconnect,
+			),
+		},
+	)
+
+	s.operationManager.Finish(operation, err)
+
+	return err
+}
+
+func initInstance(
+	req *proto.InitInstanceRequest,
+	stream proto.Agent_InitInstanceServer,
+) error {
+
 	err := stream.Send(&proto.InitInstanceReply{
 		LogLineHeader: fmt.Sprintf(
 			"Executing %s",
@@ -24,7 +24,7 @@ sudo hostnamectl set-hostname "${DEV_ENV_NAME_SLUG}"
 
 log "Installing system and Docker dependencies"
 
-sudo apt-get --assume-yes --quiet --quiet install wget git vim apt-transport-https ca-certificates gnupg lsb-release
+sudo apt-get --assume-yes --quiet --quiet install wget git git-lfs vim apt-transport-https ca-certificates gnupg lsb-release
 
 # -- Recode volume configuration
 
@@ -187,4 +187,7 @@ git config --global user.email "${GITHUB_USER_EMAIL}"
 git config --global user.signingkey "${GIT_GPG_KEY_ID}"
 git config --global commit.gpgsign true
 
+# LFS objects are pulled after clone by the agent
+git lfs install --skip-repo > /dev/null
+
 EOF
@@ -0,0 +1,294 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/recode-sh/agent/constants"
+	"github.com/recode-sh/agent/internal/operations"
+	"github.com/recode-sh/agent/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	protobuf "google.golang.org/protobuf/proto"
+)
+
+// acquireOperation starts a new operation according to the passed
+// conflict policy. When the policy is "attach" and an operation of the same
+// type (for the same development environment) is in progress, the operation
+// in progress is returned as second value.
+func (s *agentServer) acquireOperation(
+	ctx context.Context,
+	operationType operations.Type,
+	devEnvName string,
+	onConflict proto.OperationConflictPolicy,
+) (*operations.Operation, *operations.Operation, error) {
+
+	if onConflict == proto.OperationConflictPolicy_OPERATION_CONFLICT_POLICY_QUEUE {
+		operation, err := s.operationManager.WaitAndStart(
+			ctx,
+			operationType,
+			devEnvName,
+			constants.GRPCServerOperationQueueTimeout,
+		)
+
+		if err != nil && errors.Is(err, operations.ErrQueueTimeout) {
+			return nil, nil, status.Error(codes.Unavailable, err.Error())
+		}
+
+		if err != nil && ctx.Err() != nil {
+			return nil, nil, status.Error(codes.Canceled, err.Error())
+		}
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return operation, nil, nil
+	}
+
+	operation, err := s.operationManager.Start(operationType, devEnvName)
+
+	if err == nil {
+		return operation, nil, nil
+	}
+
+	if !errors.Is(err, operations.ErrOperationInProgress) {
+		return nil, nil, err
+	}
+
+	// "operation" is the operation in progress here
+	if onConflict == proto.OperationConflictPolicy_OPERATION_CONFLICT_POLICY_ATTACH &&
+		operation.Type == operationType &&
+		operation.DevEnvName == devEnvName {
+
+		return nil, operation, nil
+	}
+
+	return nil, nil, status.Errorf(
+		codes.Aborted,
+		"the operation \"%s\" (%s) is in progress. Please retry later",
+		operation.Type,
+		operation.ID,
+	)
+}
+
+// sendOperationIDHeader lets clients know the ID of the
+// operation to watch in case of disconnection.
+func sendOperationIDHeader(
+	stream grpc.ServerStream,
+	operation *operations.Operation,
+) error {
+
+	return stream.SendHeader(metadata.Pairs(
+		constants.GRPCServerOperationIDMetadataKey,
+		operation.ID,
+	))
+}
+
+func buildOperationStatus(operation *operations.Operation) *proto.Operation {
+	if operation == nil {
+		return nil
+	}
+
+	return &proto.Operation{
+		Id:         operation.ID,
+		Type:       string(operation.Type),
+		StartedAt:  operation.StartedAt.Format(time.RFC3339),
+		DevEnvName: operation.DevEnvName,
+	}
+}
+
+// Attached clients receive all the replies sent since the start of
+// the operation. Replies are guaranteed to be of the expected type given
+// that clients can only attach to operations of the same type.
+
+func attachToInitInstanceOperation(
+	operation *operations.Operation,
+	stream proto.Agent_InitInstanceServer,
+) error {
+
+	return operation.Watch(
+		stream.Context(),
+		func(reply protobuf.Message) error {
+			return stream.Send(reply.(*proto.InitInstanceReply))
+		},
+	)
+}
+
+func attachToBuildAndStartDevEnvOperation(
+	operation *operations.Operation,
+	stream proto.Agent_BuildAndStartDevEnvServer,
+) error {
+
+	return operation.Watch(
+		stream.Context(),
+		func(reply protobuf.Message) error {
+			return stream.Send(reply.(*proto.BuildAndStartDevEnvReply))
+		},
+	)
+}
+
+func attachToStopDevEnvOperation(
+	operation *operations.Operation,
+	stream proto.Agent_StopDevEnvServer,
+) error {
+
+	return operation.Watch(
+		stream.Context(),
+		func(reply protobuf.Message) error {
+			return stream.Send(reply.(*proto.StopDevEnvReply))
+		},
+	)
+}
+
+func attachToRollbackDevEnvOperation(
+	operation *operations.Operation,
+	stream proto.Agent_RollbackDevEnvServer,
+) error {
+
+	return operation.Watch(
+		stream.Context(),
+		func(reply protobuf.Message) error {
+			return stream.Send(reply.(*proto.RollbackDevEnvReply))
+		},
+	)
+}
+
+// operationClient sends the replies of an operation to the
+// client that started it. Replies are always published in the
+// operation. By default, the operation is canceled when its client
+// cancels the call or disconnects. When "continueOnDisconnect" is
+// set, sends to the client are best-effort: once the client is gone
+// (eg: the SSH tunnel dropped), the operation continues and its
+// replies may be followed using "WatchOperation".
+type operationClient struct {
+	operation            *operations.Operation
+	continueOnDisconnect bool
+
+	mutex    sync.Mutex
+	detached bool
+}
+
+func newOperationClient(
+	clientCtx context.Context,
+	operation *operations.Operation,
+	continueOnDisconnect bool,
+) *operationClient {
+
+	if !continueOnDisconnect {
+		go func() {
+			select {
+			case <-clientCtx.Done():
+				operation.Cancel()
+			case <-operation.Context().Done():
+			}
+		}()
+	}
+
+	return &operationClient{
+		operation:            operation,
+		continueOnDisconnect: continueOnDisconnect,
+	}
+}
+
+func (o *operationClient) send(
+	reply protobuf.Message,
+	sendToClient func() error,
+) error {
+
+	o.operation.Publish(reply)
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if o.detached {
+		return nil
+	}
+
+	err := sendToClient()
+
+	if err == nil || !o.continueOnDisconnect {
+		return err
+	}
+
+	o.detached = true
+
+	log.Printf(
+		"client of operation %s is gone, the operation continues: %v",
+		o.operation.ID,
+		err,
+	)
+
+	return nil
+}
+
+// Streams below record the sent replies in the
+// operation to let other clients attach to it.
+// Their context is the one of the operation (canceled when
+// the client that started it cancels the call or disconnects,
+// unless "continue_on_disconnect" is set).
+
+type operationInitInstanceStream struct {
+	proto.Agent_InitInstanceServer
+	client *operationClient
+}
+
+func (o operationInitInstanceStream) Send(reply *proto.InitInstanceReply) error {
+	return o.client.send(reply, func() error {
+		return o.Agent_InitInstanceServer.Send(reply)
+	})
+}
+
+func (o operationInitInstanceStream) Context() context.Context {
+	return o.client.operation.Context()
+}
+
+type operationBuildAndStartDevEnvStream struct {
+	proto.Agent_BuildAndStartDevEnvServer
+	client *operationClient
+}
+
+func (o operationBuildAndStartDevEnvStream) Send(reply *proto.BuildAndStartDevEnvReply) error {
+	return o.client.send(reply, func() error {
+		return o.Agent_BuildAndStartDevEnvServer.Send(reply)
+	})
+}
+
+func (o operationBuildAndStartDevEnvStream) Context() context.Context {
+	return o.client.operation.Context()
+}
+
+type operationStopDevEnvStream struct {
+	proto.Agent_StopDevEnvServer
+	client *operationClient
+}
+
+func (o operationStopDevEnvStream) Send(reply *proto.StopDevEnvReply) error {
+	return o.client.send(reply, func() error {
+		return o.Agent_StopDevEnvServer.Send(reply)
+	})
+}
+
+func (o operationStopDevEnvStream) Context() context.Context {
+	return o.client.operation.Context()
+}
+
+type operationRollbackDevEnvStream struct {
+	proto.Agent_RollbackDevEnvServer
+	client *operationClient
+}
+
+func (o operationRollbackDevEnvStream) Send(reply *proto.RollbackDevEnvReply) error {
+	return o.client.send(reply, func() error {
+		return o.Agent_RollbackDevEnvServer.Send(reply)
+	})
+}
+
+func (o operationRollbackDevEnvStream) Context() context.Context {
+	return o.client.operation.Context()
+}
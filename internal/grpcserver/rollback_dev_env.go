@@ -0,0 +1,174 @@
+package grpcserver
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/recode-sh/agent/internal/devenv"
+	"github.com/recode-sh/agent/internal/docker"
+	"github.com/recode-sh/agent/internal/operations"
+	"github.com/recode-sh/agent/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *agentServer) RollbackDevEnv(
+	req *proto.RollbackDevEnvRequest,
+	stream proto.Agent_RollbackDevEnvServer,
+) error {
+
+	devEnv, err := newDevEnvFromRequest(req.DevEnvName)
+
+	if err != nil {
+		return err
+	}
+
+	operation, inProgressOperation, err := s.acquireOperation(
+		stream.Context(),
+		operations.TypeRollbackDevEnv,
+		devEnv.Name,
+		req.OnConflict,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	if inProgressOperation != nil {
+		err = sendOperationIDHeader(stream, inProgressOperation)
+
+		if err != nil {
+			return err
+		}
+
+		return attachToRollbackDevEnvOperation(inProgressOperation, stream)
+	}
+
+	err = sendOperationIDHeader(stream, operation)
+
+	if err != nil {
+		s.operationManager.Finish(operation, err)
+		return err
+	}
+
+	err = rollbackDevEnv(
+		req,
+		devEnv,
+		operationRollbackDevEnvStream{
+			Agent_RollbackDevEnvServer: stream,
+			client: newOperationClient(
+				stream.Context(),
+				operation,
+				req.ContinueOnDisconnect,
+			),
+		},
+	)
+
+	s.operationManager.Finish(operation, err)
+
+	return err
+}
+
+// rollbackDevEnv recreates the container from a previous
+// image (without rebuilding) and runs the "init" and "start"
+// hooks. The workspace is kept as is.
+func rollbackDevEnv(
+	req *proto.RollbackDevEnvRequest,
+	devEnv *devenv.DevEnv,
+	stream proto.Agent_RollbackDevEnvServer,
+) error {
+
+	ctx := stream.Context()
+	dockerClient, err := docker.NewDefaultClient()
+
+	if err != nil {
+		return err
+	}
+
+	rollbackImage, err := devenv.RollbackDockerImage(
+		ctx,
+		dockerClient,
+		devEnv,
+		req.ImageTag,
+	)
+
+	if err != nil && errors.Is(err, devenv.ErrDevEnvImageNotFound) {
+		return status.Error(codes.NotFound, err.Error())
+	}
+
+	if err != nil && errors.Is(err, devenv.ErrNoPreviousDevEnvImage) {
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+
+	if err != nil {
+		return err
+	}
+
+	err = stream.Send(&proto.RollbackDevEnvReply{
+		LogLineHeader: fmt.Sprintf(
+			"Recreating %s from %s:%s",
+			devEnv.DockerContainerName,
+			devEnv.DockerImageName,
+			rollbackImage.Tag,
+		),
+	})
+
+	if err != nil {
+		return err
+	}
+
+	// SSH sessions must not run the "start" and "attach"
+	// hooks of the new container before the "init" hooks
+	err = devenv.LockWorkspaceHooks(ctx, devEnv)
+
+	if err != nil {
+		return err
+	}
+
+	defer devenv.UnlockWorkspaceHooks(devEnv)
+
+	err = devenv.EnsureDockerContainerRemoved(dockerClient, devEnv)
+
+	if err != nil {
+		return err
+	}
+
+	err = devenv.EnsureDockerContainerRunning(dockerClient, devEnv)
+
+	if err != nil {
+		return err
+	}
+
+	err = stream.Send(&proto.RollbackDevEnvReply{
+		LogLineHeader: "Running hooks",
+	})
+
+	if err != nil {
+		return err
+	}
+
+	err = devenv.RunWorkspaceHooksAfterRollback(
+		ctx,
+		dockerClient,
+		devEnv,
+		devenv.NewGRPCRollbackDevEnvStreamWriter(stream),
+	)
+
+	if err != nil {
+		return err
+	}
+
+	return stream.Send(&proto.RollbackDevEnvReply{
+		Image: buildDevEnvImageStatus(rollbackImage),
+	})
+}
+
+func buildDevEnvImageStatus(image devenv.DevEnvImage) *proto.DevEnvImage {
+	return &proto.DevEnvImage{
+		Tag:                 image.Tag,
+		ImageId:             image.ImageID,
+		BuiltAt:             image.BuiltAt.Format(time.RFC3339),
+		DevEnvRepoCommitSha: image.DevEnvRepoCommitSHA,
+	}
+}
@@ -3,14 +3,18 @@ package grpcserver
 import (
 	_ "embed"
 	"fmt"
+	"log"
 	"net"
 
+	"github.com/recode-sh/agent/constants"
+	"github.com/recode-sh/agent/internal/operations"
 	"github.com/recode-sh/agent/proto"
 	"google.golang.org/grpc"
 )
 
 type agentServer struct {
 	proto.UnimplementedAgentServer
+	operationManager *operations.Manager
 }
 
 func ListenAndServe(serverAddrProtocol, serverAddr string) error {
@@ -20,9 +24,30 @@ func ListenAndServe(serverAddrProtocol, serverAddr string) error {
 		return fmt.Errorf("failed to listen: %v", err)
 	}
 
+	operationsJournal, err := operations.NewJournal(
+		constants.GRPCServerOperationsJournalDirPath,
+		constants.GRPCServerOperationsJournalMaxAge,
+		constants.GRPCServerOperationsJournalMaxTotalSize,
+	)
+
+	// Operations can run without journal
+	// so we don't prevent the server from starting
+	if err != nil {
+		log.Printf("failed to create operations journal: %v", err)
+		operationsJournal = nil
+	}
+
 	grpcServer := grpc.NewServer()
 
-	proto.RegisterAgentServer(grpcServer, &agentServer{})
+	server := &agentServer{
+		operationManager: operations.NewManager(operationsJournal),
+	}
+
+	// The containers are restarted by Docker
+	// when the instance reboots
+	go server.runWorkspaceStartHooksAfterRestart()
+
+	proto.RegisterAgentServer(grpcServer, server)
 
 	return grpcServer.Serve(tcpServer)
 }
@@ -1,12 +1,19 @@
 package grpcserver
 
 import (
+	"context"
+	"fmt"
+	"log"
 	"os"
+	"time"
 
 	"github.com/recode-sh/agent/constants"
 	"github.com/recode-sh/agent/internal/devenv"
 	"github.com/recode-sh/agent/internal/docker"
+	"github.com/recode-sh/agent/internal/operations"
 	"github.com/recode-sh/agent/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func (s *agentServer) BuildAndStartDevEnv(
@@ -14,34 +21,240 @@ func (s *agentServer) BuildAndStartDevEnv(
 	stream proto.Agent_BuildAndStartDevEnvServer,
 ) error {
 
-	dockerClient, err := docker.NewDefaultClient()
+	devEnv, err := newDevEnvFromRequest(req.DevEnvName)
 
 	if err != nil {
 		return err
 	}
 
-	// The method "BuildAndStartDevEnv" may be run multiple times
-	// so we need to ensure idempotency
-	err = devenv.EnsureDockerContainerRemoved(dockerClient)
+	operation, inProgressOperation, err := s.acquireOperation(
+		stream.Context(),
+		operations.TypeBuildAndStartDevEnv,
+		devEnv.Name,
+		req.OnConflict,
+	)
 
 	if err != nil {
 		return err
 	}
 
-	workspaceConfig, err := devenv.LoadWorkspaceConfig(
-		constants.DevEnvWorkspaceConfigFilePath,
+	if inProgressOperation != nil {
+		err = sendOperationIDHeader(stream, inProgressOperation)
+
+		if err != nil {
+			return err
+		}
+
+		return attachToBuildAndStartDevEnvOperation(inProgressOperation, stream)
+	}
+
+	err = sendOperationIDHeader(stream, operation)
+
+	if err != nil {
+		s.operationManager.Finish(operation, err)
+		return err
+	}
+
+	err = handleBuildAndStartDevEnv(
+		req,
+		devEnv,
+		operationBuildAndStartDevEnvStream{
+			Agent_BuildAndStartDevEnvServer: stream,
+			client: newOperationClient(
+				stream.Context(),
+				operation,
+				req.ContinueOnDisconnect,
+			),
+		},
+	)
+
+	s.operationManager.Finish(operation, err)
+
+	return err
+}
+
+func handleBuildAndStartDevEnv(
+	req *proto.BuildAndStartDevEnvRequest,
+	devEnv *devenv.DevEnv,
+	stream proto.Agent_BuildAndStartDevEnvServer,
+) error {
+
+	// The context of the operation (canceled when the client cancels
+	// the call or disconnects, unless "continue_on_disconnect" is set).
+	// Used to abort clones, builds and hooks.
+	ctx := stream.Context()
+	startedAt := time.Now()
+	stepsRecorder := devenv.NewBuildStepsRecorder(stream)
+
+	err := buildAndStartDevEnv(ctx, req, devEnv, stepsRecorder)
+
+	result := devenv.NewBuildAndStartDevEnvResult(
+		startedAt,
+		err,
+		stepsRecorder.Steps(),
 	)
 
+	// Hooks may have failed in "continue on hook error" mode.
+	// The summary is sent as a log line for CLIs that
+	// don't handle structured events.
+	if err == nil && len(result.Summary) > 0 {
+		err = stream.Send(&proto.BuildAndStartDevEnvReply{
+			LogLine: result.Summary + "\n",
+		})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	// Sent for CLIs that handle structured events.
+	// The error is still returned for the others.
+	resultSendErr := stream.Send(&proto.BuildAndStartDevEnvReply{
+		Result: result,
+	})
+
+	if err == nil {
+		return resultSendErr
+	}
+
+	if ctx.Err() != nil {
+		log.Printf("BuildAndStartDevEnv canceled: %v", err)
+
+		return status.Error(
+			codes.Canceled,
+			"the development environment build was canceled",
+		)
+	}
+
+	return err
+}
+
+func buildAndStartDevEnv(
+	ctx context.Context,
+	req *proto.BuildAndStartDevEnvRequest,
+	devEnv *devenv.DevEnv,
+	stream proto.Agent_BuildAndStartDevEnvServer,
+) (returnedErr error) {
+
+	buildSecrets, err := newBuildSecretsFromRequest(req, stream)
+
 	if err != nil {
 		return err
 	}
 
-	preparedWorkspaceMetadata, err := devenv.PrepareWorkspace(
+	// Secrets may appear in the build output and errors
+	secretsRedactingStream := devenv.NewSecretsRedactingBuildAndStartDevEnvStream(
+		stream,
+		buildSecrets,
+	)
+
+	stream = secretsRedactingStream
+
+	defer func() {
+		flushErr := secretsRedactingStream.Flush()
+
+		if returnedErr == nil {
+			returnedErr = flushErr
+		}
+
+		returnedErr = buildSecrets.RedactError(returnedErr)
+	}()
+
+	userConfigRepo, err := newGitRepositoryFromRequest(
+		req.UserConfigRepoGitProvider,
 		req.UserConfigRepoOwner,
 		req.UserConfigRepoName,
+		req.UserConfigRepoRef,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	devEnvRepo, err := newGitRepositoryFromRequest(
+		req.DevEnvRepoGitProvider,
 		req.DevEnvRepoOwner,
 		req.DevEnvRepoName,
+		req.DevEnvRepoRef,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	reposCloneOptions, err := newReposCloneOptionsFromRequest(req)
+
+	if err != nil {
+		return err
+	}
+
+	userConfigRepo.CloneOptions = reposCloneOptions.Lookup(userConfigRepo.FullName())
+	devEnvRepo.CloneOptions = reposCloneOptions.Lookup(devEnvRepo.FullName())
+
+	retryPolicy, err := newRetryPolicyFromRequest(req.RetryPolicy)
+
+	if err != nil {
+		return err
+	}
+
+	userConfigRepo.RetryPolicy = retryPolicy
+	devEnvRepo.RetryPolicy = retryPolicy
+
+	hooksOptions, err := devenv.NewWorkspaceHooksOptions(
+		int(req.HooksParallelism),
+		req.HooksFailurePolicy,
+		req.ContinueOnHookError,
+	)
+
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	imageBuildOptions, err := newImageBuildOptionsFromRequest(
+		req.ImageBuildOptions,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	imageBuildOptions.Secrets = buildSecrets
+
+	err = devenv.ValidateBuildArgs(req.BuildArgs)
+
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	imageBuildOptions.BuildArgs = req.BuildArgs
+
+	dockerClient, err := docker.NewDefaultClient()
+
+	if err != nil {
+		return err
+	}
+
+	workspaceConfig, err := devEnv.LoadWorkspaceConfig()
+
+	if err != nil {
+		return err
+	}
+
+	// On error (or cancellation) the workspace is reset
+	// (or restored to its previous state in incremental mode)
+	preparedWorkspaceMetadata, err := devenv.PrepareWorkspace(
+		ctx,
+		stream,
+		devEnv,
+		userConfigRepo,
+		devEnvRepo,
 		workspaceConfig,
+		devenv.WorkspaceSyncOptions{
+			Mode:                req.WorkspaceSyncMode,
+			ArchiveRemovedRepos: req.ArchiveRemovedRepos,
+		},
+		reposCloneOptions,
+		req.WorkspaceLayout,
 	)
 
 	if err != nil {
@@ -52,28 +265,259 @@ func (s *agentServer) BuildAndStartDevEnv(
 	defer os.RemoveAll(preparedWorkspaceMetadata.TmpDevEnvRepoDirPath)
 
 	err = devenv.Build(
+		ctx,
 		dockerClient,
 		stream,
+		devEnv,
 		req.UserConfigRepoOwner,
 		req.UserConfigRepoName,
 		req.DevEnvRepoOwner,
 		req.DevEnvRepoName,
 		preparedWorkspaceMetadata,
+		retryPolicy,
+		imageBuildOptions,
 	)
 
 	if err != nil {
 		return err
 	}
 
-	err = devenv.EnsureDockerContainerRunning(dockerClient)
+	err = devenv.TagBuiltDockerImage(
+		ctx,
+		dockerClient,
+		stream,
+		devEnv,
+		workspaceConfig,
+		imageBuildOptions.KeepImages,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	// SSH sessions must not run the "start" and "attach"
+	// hooks of the new container before the "init" hooks
+	err = devenv.LockWorkspaceHooks(ctx, devEnv)
+
+	if err != nil {
+		return err
+	}
+
+	defer devenv.UnlockWorkspaceHooks(devEnv)
+
+	// The previous container is kept running until the new
+	// image is built. The method "BuildAndStartDevEnv" may be
+	// run multiple times so we need to ensure idempotency.
+	err = devenv.EnsureDockerContainerRemoved(dockerClient, devEnv)
+
+	if err != nil {
+		return err
+	}
+
+	err = devenv.StartBuiltDockerContainer(
+		ctx,
+		dockerClient,
+		stream,
+		devEnv,
+		retryPolicy,
+	)
 
 	if err != nil {
 		return err
 	}
 
 	return devenv.RunWorkspaceHooks(
+		ctx,
 		dockerClient,
 		stream,
+		devEnv,
 		workspaceConfig,
+		hooksOptions,
 	)
 }
+
+func newGitRepositoryFromRequest(
+	gitProviderSpec string,
+	repoOwner string,
+	repoName string,
+	repoRef string,
+) (*devenv.GitRepository, error) {
+
+	gitProvider, err := devenv.NewGitProvider(gitProviderSpec)
+
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	err = devenv.ValidateGitRef(repoRef)
+
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	repo := devenv.NewGitRepository(gitProvider, repoOwner, repoName)
+	repo.Ref = repoRef
+
+	return repo, nil
+}
+
+func newReposCloneOptionsFromRequest(
+	req *proto.BuildAndStartDevEnvRequest,
+) (devenv.ReposCloneOptions, error) {
+
+	reposCloneOptions := devenv.ReposCloneOptions{
+		ByRepoFullName: map[string]devenv.GitCloneOptions{},
+	}
+
+	defaultCloneOptions, err := newGitCloneOptionsFromRequest(
+		req.DefaultCloneOptions,
+	)
+
+	if err != nil {
+		return devenv.ReposCloneOptions{}, err
+	}
+
+	reposCloneOptions.Default = defaultCloneOptions
+
+	for _, repoCloneOptions := range req.RepositoriesCloneOptions {
+		cloneOptions, err := newGitCloneOptionsFromRequest(
+			repoCloneOptions.Options,
+		)
+
+		if err != nil {
+			return devenv.ReposCloneOptions{}, err
+		}
+
+		reposCloneOptions.ByRepoFullName[repoCloneOptions.Repository] = cloneOptions
+	}
+
+	return reposCloneOptions, nil
+}
+
+func newGitCloneOptionsFromRequest(
+	cloneOptions *proto.CloneOptions,
+) (devenv.GitCloneOptions, error) {
+
+	if cloneOptions == nil {
+		return devenv.GitCloneOptions{}, nil
+	}
+
+	gitCloneOptions, err := devenv.NewGitCloneOptions(
+		cloneOptions.Depth,
+		cloneOptions.Filter,
+		cloneOptions.SparseCheckoutPaths,
+	)
+
+	if err != nil {
+		return devenv.GitCloneOptions{}, status.Error(
+			codes.InvalidArgument,
+			err.Error(),
+		)
+	}
+
+	gitCloneOptions.SkipSubmodules = cloneOptions.SkipSubmodules
+	gitCloneOptions.SkipLFS = cloneOptions.SkipLfs
+
+	return gitCloneOptions, nil
+}
+
+func newRetryPolicyFromRequest(
+	retryPolicy *proto.RetryPolicy,
+) (devenv.RetryPolicy, error) {
+
+	if retryPolicy == nil {
+		return devenv.DefaultRetryPolicy(), nil
+	}
+
+	devEnvRetryPolicy, err := devenv.NewRetryPolicy(
+		int(retryPolicy.MaxAttempts),
+		time.Duration(retryPolicy.InitialIntervalMs)*time.Millisecond,
+		time.Duration(retryPolicy.MaxIntervalMs)*time.Millisecond,
+		time.Duration(retryPolicy.MaxElapsedTimeMs)*time.Millisecond,
+	)
+
+	if err != nil {
+		return devenv.RetryPolicy{}, status.Error(
+			codes.InvalidArgument,
+			err.Error(),
+		)
+	}
+
+	return devEnvRetryPolicy, nil
+}
+
+func newImageBuildOptionsFromRequest(
+	imageBuildOptions *proto.ImageBuildOptions,
+) (devenv.ImageBuildOptions, error) {
+
+	if imageBuildOptions == nil {
+		return devenv.DefaultImageBuildOptions(), nil
+	}
+
+	devEnvImageBuildOptions, err := devenv.NewImageBuildOptions(
+		imageBuildOptions.UseBuildkit,
+		int(imageBuildOptions.CacheKeepBuilds),
+		int(imageBuildOptions.KeepImages),
+	)
+
+	if err != nil {
+		return devenv.ImageBuildOptions{}, status.Error(
+			codes.InvalidArgument,
+			err.Error(),
+		)
+	}
+
+	return devEnvImageBuildOptions, nil
+}
+
+// newBuildSecretsFromRequest warns users when secrets are stored
+// on the instance but are not used (BuildKit disabled). They are
+// still returned to be redacted from the replies.
+func newBuildSecretsFromRequest(
+	req *proto.BuildAndStartDevEnvRequest,
+	stream proto.Agent_BuildAndStartDevEnvServer,
+) (devenv.BuildSecrets, error) {
+
+	useBuildKit := req.ImageBuildOptions != nil &&
+		req.ImageBuildOptions.UseBuildkit
+
+	if len(req.BuildSecrets) > 0 && !useBuildKit {
+		return nil, status.Error(
+			codes.InvalidArgument,
+			"build secrets require BuildKit (see \"image_build_options.use_buildkit\")",
+		)
+	}
+
+	secretsOverrides := map[string]string{}
+
+	for _, buildSecret := range req.BuildSecrets {
+		secretsOverrides[buildSecret.Id] = buildSecret.Value
+	}
+
+	buildSecrets, err := devenv.LoadBuildSecrets(
+		constants.DevEnvBuildSecretsDirPath,
+		secretsOverrides,
+	)
+
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if len(buildSecrets) == 0 || useBuildKit {
+		return buildSecrets, nil
+	}
+
+	err = stream.Send(&proto.BuildAndStartDevEnvReply{
+		LogLine: fmt.Sprintf(
+			"Warning: the build secrets stored in \"%s\" are not used "+
+				"without BuildKit (see \"image_build_options.use_buildkit\")\n",
+			constants.DevEnvBuildSecretsDirPath,
+		),
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return buildSecrets, nil
+}
@@ -0,0 +1,72 @@
+package grpcserver
+
+import (
+	"context"
+	"log"
+
+	"github.com/recode-sh/agent/constants"
+	"github.com/recode-sh/agent/internal/devenv"
+	"github.com/recode-sh/agent/internal/docker"
+	"github.com/recode-sh/agent/internal/operations"
+)
+
+// runWorkspaceStartHooksAfterRestart runs the "start" hooks of the
+// development environments whose container was restarted while
+// the agent was not running. Run as an operation to prevent
+// conflicts with the RPCs received during startup.
+func (s *agentServer) runWorkspaceStartHooksAfterRestart() {
+	operation, err := s.operationManager.WaitAndStart(
+		context.Background(),
+		operations.TypeRunStartHooksAfterRestart,
+		"",
+		constants.GRPCServerOperationQueueTimeout,
+	)
+
+	if err != nil {
+		log.Printf("error while running start hooks after restart: %v", err)
+		return
+	}
+
+	err = runWorkspaceStartHooksAfterRestart(operation.Context())
+
+	s.operationManager.Finish(operation, err)
+
+	if err != nil {
+		log.Printf("error while running start hooks after restart: %v", err)
+	}
+}
+
+func runWorkspaceStartHooksAfterRestart(ctx context.Context) error {
+	dockerClient, err := docker.NewDefaultClient()
+
+	if err != nil {
+		return err
+	}
+
+	devEnvs, err := devenv.ListDevEnvs()
+
+	if err != nil {
+		return err
+	}
+
+	for _, devEnv := range devEnvs {
+		err = devenv.RunWorkspaceStartHooksAfterRestart(
+			ctx,
+			dockerClient,
+			devEnv,
+			log.Writer(),
+		)
+
+		// A failing development environment
+		// must not prevent the others from starting
+		if err != nil {
+			log.Printf(
+				"error while running start hooks of \"%s\": %v",
+				devEnv.Name,
+				err,
+			)
+		}
+	}
+
+	return nil
+}
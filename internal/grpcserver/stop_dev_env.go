@@ -0,0 +1,143 @@
+package grpcserver
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/recode-sh/agent/constants"
+	"github.com/recode-sh/agent/internal/devenv"
+	"github.com/recode-sh/agent/internal/docker"
+	"github.com/recode-sh/agent/internal/operations"
+	"github.com/recode-sh/agent/proto"
+)
+
+func (s *agentServer) StopDevEnv(
+	req *proto.StopDevEnvRequest,
+	stream proto.Agent_StopDevEnvServer,
+) error {
+
+	devEnv, err := newDevEnvFromRequest(req.DevEnvName)
+
+	if err != nil {
+		return err
+	}
+
+	operation, inProgressOperation, err := s.acquireOperation(
+		stream.Context(),
+		operations.TypeStopDevEnv,
+		devEnv.Name,
+		req.OnConflict,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	if inProgressOperation != nil {
+		err = sendOperationIDHeader(stream, inProgressOperation)
+
+		if err != nil {
+			return err
+		}
+
+		return attachToStopDevEnvOperation(inProgressOperation, stream)
+	}
+
+	err = sendOperationIDHeader(stream, operation)
+
+	if err != nil {
+		s.operationManager.Finish(operation, err)
+		return err
+	}
+
+	err = stopDevEnv(
+		req,
+		devEnv,
+		operationStopDevEnvStream{
+			Agent_StopDevEnvServer: stream,
+			client: newOperationClient(
+				stream.Context(),
+				operation,
+				req.ContinueOnDisconnect,
+			),
+		},
+	)
+
+	s.operationManager.Finish(operation, err)
+
+	return err
+}
+
+func stopDevEnv(
+	req *proto.StopDevEnvRequest,
+	devEnv *devenv.DevEnv,
+	stream proto.Agent_StopDevEnvServer,
+) error {
+
+	dockerClient, err := docker.NewDefaultClient()
+
+	if err != nil {
+		return err
+	}
+
+	isContainerRunning, err := docker.IsContainerRunning(
+		dockerClient,
+		devEnv.DockerContainerName,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	// The method "StopDevEnv" may be run multiple times
+	// so we need to ensure idempotency
+	if !isContainerRunning {
+		return stream.Send(&proto.StopDevEnvReply{
+			LogLineHeader: fmt.Sprintf(
+				"%s is already stopped",
+				devEnv.DockerContainerName,
+			),
+		})
+	}
+
+	workspaceConfig, err := devEnv.LoadWorkspaceConfig()
+
+	if err != nil {
+		return err
+	}
+
+	err = devenv.RunWorkspaceStopHooks(
+		stream.Context(),
+		dockerClient,
+		stream,
+		devEnv,
+		workspaceConfig,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	stopTimeout := constants.DevEnvDockerContainerStopTimeout
+
+	if req.StopTimeoutSeconds != nil {
+		stopTimeout = time.Duration(*req.StopTimeoutSeconds) * time.Second
+	}
+
+	err = stream.Send(&proto.StopDevEnvReply{
+		LogLineHeader: fmt.Sprintf(
+			"Stopping %s",
+			devEnv.DockerContainerName,
+		),
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return devenv.EnsureDockerContainerStopped(
+		dockerClient,
+		devEnv,
+		stopTimeout,
+	)
+}
@@ -0,0 +1,72 @@
+package grpcserver
+
+import (
+	"errors"
+
+	"github.com/recode-sh/agent/internal/operations"
+	"github.com/recode-sh/agent/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	protobuf "google.golang.org/protobuf/proto"
+)
+
+func (s *agentServer) WatchOperation(
+	req *proto.WatchOperationRequest,
+	stream proto.Agent_WatchOperationServer,
+) error {
+
+	// Past replies are replayed and, if the
+	// operation is in progress, live ones are tailed
+	err := s.operationManager.Watch(
+		stream.Context(),
+		req.OperationId,
+		func(reply protobuf.Message) error {
+			return stream.Send(buildWatchOperationReply(reply))
+		},
+	)
+
+	if err != nil && errors.Is(err, operations.ErrOperationNotFound) {
+		return status.Error(codes.NotFound, err.Error())
+	}
+
+	if err != nil && errors.Is(err, operations.ErrOperationInterrupted) {
+		return status.Error(codes.Aborted, err.Error())
+	}
+
+	// Operation error (already a gRPC status)
+	return err
+}
+
+func buildWatchOperationReply(
+	reply protobuf.Message,
+) *proto.WatchOperationReply {
+
+	switch typedReply := reply.(type) {
+	case *proto.InitInstanceReply:
+		return &proto.WatchOperationReply{
+			Reply: &proto.WatchOperationReply_InitInstanceReply{
+				InitInstanceReply: typedReply,
+			},
+		}
+	case *proto.BuildAndStartDevEnvReply:
+		return &proto.WatchOperationReply{
+			Reply: &proto.WatchOperationReply_BuildAndStartDevEnvReply{
+				BuildAndStartDevEnvReply: typedReply,
+			},
+		}
+	case *proto.StopDevEnvReply:
+		return &proto.WatchOperationReply{
+			Reply: &proto.WatchOperationReply_StopDevEnvReply{
+				StopDevEnvReply: typedReply,
+			},
+		}
+	case *proto.RollbackDevEnvReply:
+		return &proto.WatchOperationReply{
+			Reply: &proto.WatchOperationReply_RollbackDevEnvReply{
+				RollbackDevEnvReply: typedReply,
+			},
+		}
+	}
+
+	return &proto.WatchOperationReply{}
+}
@@ -0,0 +1,250 @@
+package grpcserver
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+
+	"github.com/recode-sh/agent/constants"
+	"github.com/recode-sh/agent/internal/devenv"
+	"github.com/recode-sh/agent/internal/operations"
+	"github.com/recode-sh/agent/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Given their size, snapshot and restore chunks are not published
+// to operations so clients cannot attach to them. Only the start
+// and the result of these operations are journaled (to let
+// "WatchOperation" return their result after a disconnection).
+var errAttachToWorkspaceSnapshotOperation = status.Error(
+	codes.InvalidArgument,
+	"attaching to workspace snapshot or restore operations is not supported",
+)
+
+func (s *agentServer) SnapshotWorkspace(
+	req *proto.SnapshotWorkspaceRequest,
+	stream proto.Agent_SnapshotWorkspaceServer,
+) error {
+
+	if req.OnConflict == proto.OperationConflictPolicy_OPERATION_CONFLICT_POLICY_ATTACH {
+		return errAttachToWorkspaceSnapshotOperation
+	}
+
+	devEnv, err := newDevEnvFromRequest(req.DevEnvName)
+
+	if err != nil {
+		return err
+	}
+
+	// Prevent workspaces from being
+	// snapshotted while they are being built
+	operation, _, err := s.acquireOperation(
+		stream.Context(),
+		operations.TypeSnapshotWorkspace,
+		devEnv.Name,
+		req.OnConflict,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	err = sendOperationIDHeader(stream, operation)
+
+	if err != nil {
+		s.operationManager.Finish(operation, err)
+		return err
+	}
+
+	err = snapshotWorkspace(req, devEnv, stream)
+
+	s.operationManager.Finish(operation, err)
+
+	return err
+}
+
+func snapshotWorkspace(
+	req *proto.SnapshotWorkspaceRequest,
+	devEnv *devenv.DevEnv,
+	stream proto.Agent_SnapshotWorkspaceServer,
+) error {
+
+	workspaceConfig, err := devenv.LoadWorkspaceConfig(
+		devEnv.WorkspaceConfigFilePath,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	excludePatternsByRepoDirPath := map[string][]string{}
+
+	for _, repoExcludes := range req.RepositoriesExcludes {
+		for _, repo := range workspaceConfig.Repositories {
+			if repo.Owner+"/"+repo.Name != repoExcludes.Repository {
+				continue
+			}
+
+			excludePatternsByRepoDirPath[repo.RootDirPath] = append(
+				excludePatternsByRepoDirPath[repo.RootDirPath],
+				repoExcludes.Patterns...,
+			)
+		}
+	}
+
+	snapshotHash := sha256.New()
+
+	// Avoid sending one reply per (small) write
+	chunkWriter := bufio.NewWriterSize(
+		devenv.NewGRPCSnapshotWorkspaceStreamWriter(stream),
+		constants.GRPCServerWorkspaceSnapshotChunkSize,
+	)
+
+	err = devenv.SnapshotWorkspace(
+		stream.Context(),
+		devEnv,
+		io.MultiWriter(chunkWriter, snapshotHash),
+		excludePatternsByRepoDirPath,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	err = chunkWriter.Flush()
+
+	if err != nil {
+		return err
+	}
+
+	return stream.Send(&proto.SnapshotWorkspaceReply{
+		Checksum: hex.EncodeToString(snapshotHash.Sum(nil)),
+	})
+}
+
+func (s *agentServer) RestoreWorkspace(
+	stream proto.Agent_RestoreWorkspaceServer,
+) error {
+
+	// The conflict policy and the development
+	// environment are sent in the first request
+	firstReq, err := stream.Recv()
+
+	if err != nil {
+		return err
+	}
+
+	if firstReq.OnConflict == proto.OperationConflictPolicy_OPERATION_CONFLICT_POLICY_ATTACH {
+		return errAttachToWorkspaceSnapshotOperation
+	}
+
+	devEnv, err := newDevEnvFromRequest(firstReq.DevEnvName)
+
+	if err != nil {
+		return err
+	}
+
+	operation, _, err := s.acquireOperation(
+		stream.Context(),
+		operations.TypeRestoreWorkspace,
+		devEnv.Name,
+		firstReq.OnConflict,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	err = sendOperationIDHeader(stream, operation)
+
+	if err != nil {
+		s.operationManager.Finish(operation, err)
+		return err
+	}
+
+	err = restoreWorkspace(firstReq, devEnv, stream)
+
+	s.operationManager.Finish(operation, err)
+
+	return err
+}
+
+func restoreWorkspace(
+	firstReq *proto.RestoreWorkspaceRequest,
+	devEnv *devenv.DevEnv,
+	stream proto.Agent_RestoreWorkspaceServer,
+) error {
+
+	if len(firstReq.Checksum) == 0 {
+		return status.Error(
+			codes.InvalidArgument,
+			"the checksum of the workspace snapshot is required",
+		)
+	}
+
+	// The snapshot is fully received (and verified)
+	// before touching the workspace
+	snapshotFile, err := os.CreateTemp("", "recode-workspace-snapshot-*")
+
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(snapshotFile.Name())
+	defer snapshotFile.Close()
+
+	snapshotHash := sha256.New()
+	snapshotWriter := io.MultiWriter(snapshotFile, snapshotHash)
+	snapshotSize := int64(0)
+
+	for req := firstReq; ; {
+		_, err = snapshotWriter.Write(req.Chunk)
+
+		if err != nil {
+			return err
+		}
+
+		snapshotSize += int64(len(req.Chunk))
+
+		req, err = stream.Recv()
+
+		if err != nil && errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	snapshotChecksum := hex.EncodeToString(snapshotHash.Sum(nil))
+
+	if snapshotChecksum != firstReq.Checksum {
+		return status.Errorf(
+			codes.DataLoss,
+			"the workspace snapshot checksum doesn't match (expected \"%s\", got \"%s\")",
+			firstReq.Checksum,
+			snapshotChecksum,
+		)
+	}
+
+	_, err = snapshotFile.Seek(0, io.SeekStart)
+
+	if err != nil {
+		return err
+	}
+
+	err = devenv.RestoreWorkspace(devEnv, snapshotFile)
+
+	if err != nil {
+		return err
+	}
+
+	return stream.SendAndClose(&proto.RestoreWorkspaceReply{
+		RestoredBytes: snapshotSize,
+	})
+}
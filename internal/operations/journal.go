@@ -0,0 +1,318 @@
+package operations
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/recode-sh/agent/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	protobuf "google.golang.org/protobuf/proto"
+)
+
+const journalFileExt = ".journal"
+
+var (
+	ErrOperationNotFound    = errors.New("operation not found")
+	ErrOperationInterrupted = errors.New("operation was interrupted before its end")
+)
+
+// Journal persists the replies sent during operations
+// (one JSON lines file per operation) to let clients
+// replay them after a disconnection.
+type Journal struct {
+	dirPath      string
+	maxAge       time.Duration
+	maxTotalSize int64
+}
+
+type journalEntry struct {
+	Operation *journalEntryOperation `json:"operation,omitempty"`
+	Reply     json.RawMessage        `json:"reply,omitempty"`
+	Result    *journalEntryResult    `json:"result,omitempty"`
+}
+
+type journalEntryOperation struct {
+	ID         string    `json:"id"`
+	Type       Type      `json:"type"`
+	DevEnvName string    `json:"dev_env_name,omitempty"`
+	StartedAt  time.Time `json:"started_at"`
+}
+
+type journalEntryResult struct {
+	FinishedAt    time.Time `json:"finished_at"`
+	StatusCode    uint32    `json:"status_code"`
+	StatusMessage string    `json:"status_message"`
+}
+
+func NewJournal(
+	dirPath string,
+	maxAge time.Duration,
+	maxTotalSize int64,
+) (*Journal, error) {
+
+	err := os.MkdirAll(dirPath, os.FileMode(0700))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Journal{
+		dirPath:      dirPath,
+		maxAge:       maxAge,
+		maxTotalSize: maxTotalSize,
+	}, nil
+}
+
+func (j *Journal) journalFilePath(operationID string) string {
+	return filepath.Join(j.dirPath, operationID+journalFileExt)
+}
+
+func (j *Journal) create(operation *Operation) (*os.File, error) {
+	// Old journals are removed before
+	// creating a new one to bound disk usage
+	err := j.rotate()
+
+	if err != nil {
+		return nil, err
+	}
+
+	journalFile, err := os.OpenFile(
+		j.journalFilePath(operation.ID),
+		os.O_CREATE|os.O_WRONLY|os.O_APPEND,
+		os.FileMode(0600),
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = appendJournalEntry(journalFile, journalEntry{
+		Operation: &journalEntryOperation{
+			ID:         operation.ID,
+			Type:       operation.Type,
+			DevEnvName: operation.DevEnvName,
+			StartedAt:  operation.StartedAt,
+		},
+	})
+
+	if err != nil {
+		journalFile.Close()
+		return nil, err
+	}
+
+	return journalFile, nil
+}
+
+func appendReplyToJournal(
+	journalFile *os.File,
+	reply protobuf.Message,
+) error {
+
+	replyAsJSON, err := protojson.Marshal(reply)
+
+	if err != nil {
+		return err
+	}
+
+	return appendJournalEntry(journalFile, journalEntry{
+		Reply: replyAsJSON,
+	})
+}
+
+func appendResultToJournal(
+	journalFile *os.File,
+	operationErr error,
+) error {
+
+	// Errors returned by RPCs are
+	// converted to gRPC status by the server
+	operationStatus := status.Convert(operationErr)
+
+	return appendJournalEntry(journalFile, journalEntry{
+		Result: &journalEntryResult{
+			FinishedAt:    time.Now(),
+			StatusCode:    uint32(operationStatus.Code()),
+			StatusMessage: operationStatus.Message(),
+		},
+	})
+}
+
+func appendJournalEntry(
+	journalFile *os.File,
+	entry journalEntry,
+) error {
+
+	entryAsJSON, err := json.Marshal(entry)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = journalFile.Write(append(entryAsJSON, '\n'))
+
+	return err
+}
+
+// Replay passes all the replies of a finished operation
+// to "replyHandler" and returns the operation error.
+func (j *Journal) Replay(
+	operationID string,
+	replyHandler func(reply protobuf.Message) error,
+) error {
+
+	// Prevent path traversal given that
+	// the ID is passed by clients
+	if strings.ContainsAny(operationID, `/\.`) || len(operationID) == 0 {
+		return ErrOperationNotFound
+	}
+
+	journalFile, err := os.Open(j.journalFilePath(operationID))
+
+	if err != nil && os.IsNotExist(err) {
+		return ErrOperationNotFound
+	}
+
+	if err != nil {
+		return err
+	}
+
+	defer journalFile.Close()
+
+	journalReader := bufio.NewReader(journalFile)
+	var operationType Type
+
+	for {
+		// Log lines may be longer than the
+		// "bufio.Scanner" max token size
+		entryAsJSON, err := journalReader.ReadBytes('\n')
+
+		if err != nil && errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return err
+		}
+
+		var entry journalEntry
+		err = json.Unmarshal(entryAsJSON, &entry)
+
+		if err != nil {
+			return err
+		}
+
+		if entry.Operation != nil {
+			operationType = entry.Operation.Type
+			continue
+		}
+
+		if entry.Result != nil {
+			if codes.Code(entry.Result.StatusCode) == codes.OK {
+				return nil
+			}
+
+			return status.Error(
+				codes.Code(entry.Result.StatusCode),
+				entry.Result.StatusMessage,
+			)
+		}
+
+		reply, err := newReplyForOperationType(operationType)
+
+		if err != nil {
+			return err
+		}
+
+		err = protojson.Unmarshal(entry.Reply, reply)
+
+		if err != nil {
+			return err
+		}
+
+		err = replyHandler(reply)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	// No result: the agent was stopped during the operation
+	return ErrOperationInterrupted
+}
+
+func newReplyForOperationType(operationType Type) (protobuf.Message, error) {
+	switch operationType {
+	case TypeInitInstance:
+		return &proto.InitInstanceReply{}, nil
+	case TypeBuildAndStartDevEnv:
+		return &proto.BuildAndStartDevEnvReply{}, nil
+	case TypeStopDevEnv:
+		return &proto.StopDevEnvReply{}, nil
+	case TypeRollbackDevEnv:
+		return &proto.RollbackDevEnvReply{}, nil
+	}
+
+	return nil, fmt.Errorf("unknown operation type \"%s\"", operationType)
+}
+
+// rotate removes the journals older than "maxAge" and then the oldest
+// ones until the total size of the journals is less than "maxTotalSize".
+func (j *Journal) rotate() error {
+	journalFilePaths, err := filepath.Glob(
+		filepath.Join(j.dirPath, "*"+journalFileExt),
+	)
+
+	if err != nil {
+		return err
+	}
+
+	journalFilesInfo := []os.FileInfo{}
+
+	for _, journalFilePath := range journalFilePaths {
+		journalFileInfo, err := os.Stat(journalFilePath)
+
+		if err != nil {
+			return err
+		}
+
+		journalFilesInfo = append(journalFilesInfo, journalFileInfo)
+	}
+
+	// Newest first
+	sort.Slice(journalFilesInfo, func(i, k int) bool {
+		return journalFilesInfo[i].ModTime().After(journalFilesInfo[k].ModTime())
+	})
+
+	journalsTotalSize := int64(0)
+
+	for _, journalFileInfo := range journalFilesInfo {
+		journalsTotalSize += journalFileInfo.Size()
+
+		isTooOld := time.Since(journalFileInfo.ModTime()) > j.maxAge
+		exceedsMaxTotalSize := journalsTotalSize > j.maxTotalSize
+
+		if !isTooOld && !exceedsMaxTotalSize {
+			continue
+		}
+
+		err = os.Remove(
+			filepath.Join(j.dirPath, journalFileInfo.Name()),
+		)
+
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	return nil
+}
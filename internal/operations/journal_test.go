@@ -0,0 +1,157 @@
+package operations
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/recode-sh/agent/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	protobuf "google.golang.org/protobuf/proto"
+)
+
+func TestJournalReplay(t *testing.T) {
+	journal, err := NewJournal(t.TempDir(), time.Hour, 1024*1024)
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	manager := NewManager(journal)
+
+	operation, err := manager.Start(TypeBuildAndStartDevEnv, "")
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	operation.Publish(&proto.BuildAndStartDevEnvReply{LogLineHeader: "Building"})
+	operation.Publish(&proto.BuildAndStartDevEnvReply{LogLine: "Step 1/2 : FROM ubuntu\n"})
+
+	manager.Finish(
+		operation,
+		status.Error(codes.Canceled, "the development environment build was canceled"),
+	)
+
+	replies := []*proto.BuildAndStartDevEnvReply{}
+
+	err = manager.Watch(
+		context.Background(),
+		operation.ID,
+		func(reply protobuf.Message) error {
+			replies = append(replies, reply.(*proto.BuildAndStartDevEnvReply))
+			return nil
+		},
+	)
+
+	if status.Code(err) != codes.Canceled {
+		t.Fatalf("expected canceled status, got '%+v'", err)
+	}
+
+	if len(replies) != 2 ||
+		replies[0].LogLineHeader != "Building" ||
+		replies[1].LogLine != "Step 1/2 : FROM ubuntu\n" {
+
+		t.Fatalf("expected all replies to be replayed, got '%+v'", replies)
+	}
+}
+
+func TestJournalReplayErrors(t *testing.T) {
+	journal, err := NewJournal(t.TempDir(), time.Hour, 1024*1024)
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	noopReplyHandler := func(reply protobuf.Message) error { return nil }
+
+	err = journal.Replay("unknown", noopReplyHandler)
+
+	if !errors.Is(err, ErrOperationNotFound) {
+		t.Fatalf("expected operation not found error, got '%+v'", err)
+	}
+
+	err = journal.Replay("../journal", noopReplyHandler)
+
+	if !errors.Is(err, ErrOperationNotFound) {
+		t.Fatalf("expected operation not found error, got '%+v'", err)
+	}
+
+	// Operation never finished (eg: agent restarted)
+	operation, err := newOperation(TypeStopDevEnv, "")
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	journalFile, err := journal.create(operation)
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	journalFile.Close()
+
+	err = journal.Replay(operation.ID, noopReplyHandler)
+
+	if !errors.Is(err, ErrOperationInterrupted) {
+		t.Fatalf("expected operation interrupted error, got '%+v'", err)
+	}
+}
+
+func TestJournalRotate(t *testing.T) {
+	journalDirPath := t.TempDir()
+	journal, err := NewJournal(journalDirPath, time.Hour, 10)
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	oldJournalFilePath := filepath.Join(journalDirPath, "old"+journalFileExt)
+	bigJournalFilePath := filepath.Join(journalDirPath, "big"+journalFileExt)
+	recentJournalFilePath := filepath.Join(journalDirPath, "recent"+journalFileExt)
+
+	for filePath, content := range map[string]string{
+		oldJournalFilePath:    "old",
+		bigJournalFilePath:    "big journal",
+		recentJournalFilePath: "recent",
+	} {
+		err = os.WriteFile(filePath, []byte(content), os.FileMode(0600))
+
+		if err != nil {
+			t.Fatalf("expected no error, got '%+v'", err)
+		}
+	}
+
+	now := time.Now()
+
+	os.Chtimes(oldJournalFilePath, now.Add(-2*time.Hour), now.Add(-2*time.Hour))
+	os.Chtimes(bigJournalFilePath, now.Add(-time.Minute), now.Add(-time.Minute))
+
+	err = journal.rotate()
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	for filePath, shouldExist := range map[string]bool{
+		oldJournalFilePath:    false, // Too old
+		bigJournalFilePath:    false, // Exceeds max total size
+		recentJournalFilePath: true,
+	} {
+		_, err := os.Stat(filePath)
+
+		if exists := err == nil; exists != shouldExist {
+			t.Fatalf(
+				"expected \"%s\" existence to equal '%t', got '%t'",
+				filePath,
+				shouldExist,
+				exists,
+			)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package operations
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	protobuf "google.golang.org/protobuf/proto"
+)
+
+var (
+	ErrOperationInProgress = errors.New("an operation is already in progress")
+	ErrQueueTimeout        = errors.New("timed out while waiting for the operation in progress")
+)
+
+// Manager ensures that only one operation
+// runs at a time in the agent.
+type Manager struct {
+	mutex   sync.Mutex
+	current *Operation
+	// Nil if operations are not journaled
+	journal *Journal
+}
+
+func NewManager(journal *Journal) *Manager {
+	return &Manager{
+		journal: journal,
+	}
+}
+
+// Start starts a new operation or returns the
+// operation in progress with "ErrOperationInProgress".
+func (m *Manager) Start(
+	operationType Type,
+	devEnvName string,
+) (*Operation, error) {
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.current != nil {
+		return m.current, ErrOperationInProgress
+	}
+
+	operation, err := newOperation(operationType, devEnvName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if m.journal != nil {
+		journalFile, err := m.journal.create(operation)
+
+		// Journal errors must not prevent operations from running
+		if err != nil {
+			log.Printf("error while creating journal for operation %s: %v", operation.ID, err)
+		}
+
+		operation.journalFile = journalFile
+	}
+
+	m.current = operation
+
+	return operation, nil
+}
+
+// WaitAndStart waits for the operation in progress
+// (if any) to finish before starting a new one.
+func (m *Manager) WaitAndStart(
+	ctx context.Context,
+	operationType Type,
+	devEnvName string,
+	timeout time.Duration,
+) (*Operation, error) {
+
+	timeoutTimer := time.NewTimer(timeout)
+	defer timeoutTimer.Stop()
+
+	for {
+		operation, err := m.Start(operationType, devEnvName)
+
+		if err == nil || !errors.Is(err, ErrOperationInProgress) {
+			return operation, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-timeoutTimer.C:
+			return nil, ErrQueueTimeout
+		case <-operation.Done():
+			// Another queued operation may start
+			// before us so we need to loop
+		}
+	}
+}
+
+// Finish releases the lock held by the operation.
+func (m *Manager) Finish(operation *Operation, err error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	operation.finish(err)
+
+	if m.current == operation {
+		m.current = nil
+	}
+}
+
+// Current returns the operation in progress or nil.
+func (m *Manager) Current() *Operation {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.current
+}
+
+// Watch passes all the replies (past and future) of the operation
+// identified by "operationID" to "replyHandler" and returns the
+// operation error once finished. Finished operations are replayed
+// from the journal.
+func (m *Manager) Watch(
+	ctx context.Context,
+	operationID string,
+	replyHandler func(reply protobuf.Message) error,
+) error {
+
+	m.mutex.Lock()
+	operation := m.current
+	m.mutex.Unlock()
+
+	if operation != nil && operation.ID == operationID {
+		return operation.Watch(ctx, replyHandler)
+	}
+
+	if m.journal == nil {
+		return ErrOperationNotFound
+	}
+
+	return m.journal.Replay(operationID, replyHandler)
+}
@@ -0,0 +1,134 @@
+package operations
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/recode-sh/agent/proto"
+	protobuf "google.golang.org/protobuf/proto"
+)
+
+func TestManagerStart(t *testing.T) {
+	manager := NewManager(nil)
+
+	operation, err := manager.Start(TypeBuildAndStartDevEnv, "")
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	inProgressOperation, err := manager.Start(TypeStopDevEnv, "")
+
+	if !errors.Is(err, ErrOperationInProgress) {
+		t.Fatalf("expected operation in progress error, got '%+v'", err)
+	}
+
+	if inProgressOperation != operation {
+		t.Fatalf(
+			"expected operation in progress to equal '%s', got '%s'",
+			operation.ID,
+			inProgressOperation.ID,
+		)
+	}
+
+	manager.Finish(operation, nil)
+
+	if manager.Current() != nil {
+		t.Fatalf("expected no operation in progress, got '%s'", manager.Current().ID)
+	}
+
+	_, err = manager.Start(TypeStopDevEnv, "")
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+}
+
+func TestManagerWaitAndStart(t *testing.T) {
+	manager := NewManager(nil)
+
+	operation, err := manager.Start(TypeBuildAndStartDevEnv, "")
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	_, err = manager.WaitAndStart(
+		context.Background(),
+		TypeStopDevEnv,
+		"",
+		10*time.Millisecond,
+	)
+
+	if !errors.Is(err, ErrQueueTimeout) {
+		t.Fatalf("expected queue timeout error, got '%+v'", err)
+	}
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		manager.Finish(operation, nil)
+	}()
+
+	queuedOperation, err := manager.WaitAndStart(
+		context.Background(),
+		TypeStopDevEnv,
+		"",
+		time.Second,
+	)
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	if queuedOperation.Type != TypeStopDevEnv {
+		t.Fatalf(
+			"expected operation type to equal '%s', got '%s'",
+			TypeStopDevEnv,
+			queuedOperation.Type,
+		)
+	}
+}
+
+func TestOperationWatch(t *testing.T) {
+	manager := NewManager(nil)
+
+	operation, err := manager.Start(TypeBuildAndStartDevEnv, "")
+
+	if err != nil {
+		t.Fatalf("expected no error, got '%+v'", err)
+	}
+
+	operation.Publish(&proto.BuildAndStartDevEnvReply{LogLine: "first"})
+
+	operationErr := errors.New("build error")
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		operation.Publish(&proto.BuildAndStartDevEnvReply{LogLine: "second"})
+		manager.Finish(operation, operationErr)
+	}()
+
+	logLines := []string{}
+
+	err = operation.Watch(
+		context.Background(),
+		func(reply protobuf.Message) error {
+			logLines = append(
+				logLines,
+				reply.(*proto.BuildAndStartDevEnvReply).LogLine,
+			)
+
+			return nil
+		},
+	)
+
+	if !errors.Is(err, operationErr) {
+		t.Fatalf("expected operation error, got '%+v'", err)
+	}
+
+	if len(logLines) != 2 || logLines[0] != "first" || logLines[1] != "second" {
+		t.Fatalf("expected all replies to be watched, got '%+v'", logLines)
+	}
+}
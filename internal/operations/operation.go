@@ -0,0 +1,199 @@
+package operations
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	protobuf "google.golang.org/protobuf/proto"
+)
+
+type Type string
+
+const (
+	TypeInitInstance        Type = "InitInstance"
+	TypeBuildAndStartDevEnv Type = "BuildAndStartDevEnv"
+	TypeStopDevEnv          Type = "StopDevEnv"
+	TypeRollbackDevEnv      Type = "RollbackDevEnv"
+	TypeSnapshotWorkspace   Type = "SnapshotWorkspace"
+	TypeRestoreWorkspace    Type = "RestoreWorkspace"
+	// Started by the agent itself (not by an RPC)
+	TypeRunStartHooksAfterRestart Type = "RunStartHooksAfterRestart"
+)
+
+// Operation represents a running RPC invocation.
+// The replies sent during the operation are kept
+// to let other clients attach to it.
+type Operation struct {
+	ID   string
+	Type Type
+	// Empty for operations that are not
+	// related to a development environment
+	DevEnvName string
+	StartedAt  time.Time
+
+	// Owned by the operation (not by the client that started it)
+	// to let operations continue after client disconnections (when
+	// requested, the client cancellation is forwarded otherwise)
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mutex       sync.Mutex
+	replies     []protobuf.Message
+	updatedChan chan struct{}
+	doneChan    chan struct{}
+	err         error
+	// Nil if the operation is not journaled
+	journalFile *os.File
+}
+
+func newOperation(operationType Type, devEnvName string) (*Operation, error) {
+	operationID, err := generateOperationID()
+
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Operation{
+		ID:          operationID,
+		Type:        operationType,
+		DevEnvName:  devEnvName,
+		StartedAt:   time.Now(),
+		ctx:         ctx,
+		cancel:      cancel,
+		replies:     []protobuf.Message{},
+		updatedChan: make(chan struct{}),
+		doneChan:    make(chan struct{}),
+	}, nil
+}
+
+func generateOperationID() (string, error) {
+	randomBytes := make([]byte, 8)
+	_, err := rand.Read(randomBytes)
+
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(randomBytes), nil
+}
+
+// Context returns the context used to run the operation.
+// It is canceled once the operation is finished or canceled.
+func (o *Operation) Context() context.Context {
+	return o.ctx
+}
+
+// Cancel cancels the context of the operation
+// (used to abort clones, builds and hooks).
+func (o *Operation) Cancel() {
+	o.cancel()
+}
+
+// Publish records a reply sent during the
+// operation and notifies the watchers.
+func (o *Operation) Publish(reply protobuf.Message) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	o.replies = append(o.replies, reply)
+
+	if o.journalFile != nil {
+		err := appendReplyToJournal(o.journalFile, reply)
+
+		// Journal errors must not abort the operation
+		if err != nil {
+			log.Printf("error while journaling operation %s: %v", o.ID, err)
+		}
+	}
+
+	// Closing the channel wakes up all the
+	// watchers that wait for new replies
+	close(o.updatedChan)
+	o.updatedChan = make(chan struct{})
+}
+
+// Done returns a channel that's closed
+// when the operation is finished.
+func (o *Operation) Done() <-chan struct{} {
+	return o.doneChan
+}
+
+// Watch passes all the replies (past and future) of the
+// operation to "replyHandler" and returns the operation error
+// once finished.
+func (o *Operation) Watch(
+	ctx context.Context,
+	replyHandler func(reply protobuf.Message) error,
+) error {
+
+	nextReplyIndex := 0
+
+	for {
+		o.mutex.Lock()
+
+		repliesToHandle := o.replies[nextReplyIndex:]
+		nextReplyIndex = len(o.replies)
+		updatedChan := o.updatedChan
+
+		o.mutex.Unlock()
+
+		for _, reply := range repliesToHandle {
+			err := replyHandler(reply)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-updatedChan:
+		case <-o.doneChan:
+			// Replies may have been published
+			// between the last read and the end
+			if o.hasRepliesFrom(nextReplyIndex) {
+				continue
+			}
+
+			return o.err
+		}
+	}
+}
+
+func (o *Operation) hasRepliesFrom(replyIndex int) bool {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	return len(o.replies) > replyIndex
+}
+
+func (o *Operation) finish(err error) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	o.err = err
+
+	// The result is journaled before the operation
+	// is marked as done to let watchers that missed
+	// the end replay the journal
+	if o.journalFile != nil {
+		journalErr := appendResultToJournal(o.journalFile, err)
+
+		if journalErr != nil {
+			log.Printf("error while journaling operation %s: %v", o.ID, journalErr)
+		}
+
+		o.journalFile.Close()
+	}
+
+	close(o.doneChan)
+	o.cancel()
+}
@@ -90,8 +90,12 @@ func (a *Auth) CheckPublicKeyValidity(
 	passedKey ssh.PublicKey,
 ) (bool, error) {
 
+	// Users may suffix their user name with
+	// the development environment to connect to
+	userName, _ := parseSSHUserName(username)
+
 	authorizedKeys, err := a.lookupAuthorizedKeysForUser(
-		username,
+		userName,
 	)
 
 	if err != nil {
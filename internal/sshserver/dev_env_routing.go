@@ -0,0 +1,45 @@
+package sshserver
+
+import (
+	"strings"
+
+	"github.com/gliderlabs/ssh"
+	"github.com/recode-sh/agent/constants"
+)
+
+// parseSSHUserName splits SSH user names like "recode+api"
+// into the user name and the development environment name.
+func parseSSHUserName(sshUserName string) (string, string) {
+	userNameParts := strings.SplitN(
+		sshUserName,
+		constants.SSHServerDevEnvUserNameSeparator,
+		2,
+	)
+
+	if len(userNameParts) == 1 {
+		return userNameParts[0], ""
+	}
+
+	return userNameParts[0], userNameParts[1]
+}
+
+// resolveSessionDevEnvName returns the name of the development environment
+// passed in the user name or, if any, in the session environment variables.
+// An empty name corresponds to the default development environment.
+func resolveSessionDevEnvName(sshSession ssh.Session) string {
+	_, devEnvName := parseSSHUserName(sshSession.User())
+
+	if len(devEnvName) > 0 {
+		return devEnvName
+	}
+
+	devEnvNameEnvVarPrefix := constants.SSHServerDevEnvNameEnvVar + "="
+
+	for _, envVar := range sshSession.Environ() {
+		if strings.HasPrefix(envVar, devEnvNameEnvVarPrefix) {
+			return strings.TrimPrefix(envVar, devEnvNameEnvVarPrefix)
+		}
+	}
+
+	return ""
+}
@@ -5,6 +5,7 @@ import (
 	"os/user"
 
 	"github.com/gliderlabs/ssh"
+	"github.com/recode-sh/agent/internal/devenv"
 )
 
 type ServerAuther interface {
@@ -54,7 +55,18 @@ func (s ServerBuilder) Build() (*ssh.Server, error) {
 		},
 
 		Handler: func(sshSession ssh.Session) {
-			user, err := user.Lookup(sshSession.User())
+			userName, _ := parseSSHUserName(sshSession.User())
+			user, err := user.Lookup(userName)
+
+			if err != nil {
+				log.Println(err)
+				sshSession.Close()
+				return
+			}
+
+			devEnv, err := devenv.NewDevEnv(
+				resolveSessionDevEnvName(sshSession),
+			)
 
 			if err != nil {
 				log.Println(err)
@@ -67,7 +79,7 @@ func (s ServerBuilder) Build() (*ssh.Server, error) {
 				NewPTYManager(),
 			)
 
-			session := NewSession(sessionManager)
+			session := NewSession(sessionManager, devEnv)
 			session.Start(sshSession)
 		},
 
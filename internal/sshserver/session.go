@@ -1,17 +1,19 @@
 package sshserver
 
 import (
+	"errors"
 	"log"
 
+	"github.com/docker/docker/client"
 	"github.com/gliderlabs/ssh"
-	"github.com/recode-sh/agent/constants"
+	"github.com/recode-sh/agent/internal/devenv"
 	"github.com/recode-sh/agent/internal/docker"
 )
 
 type SessionExecShellManager interface {
-	ManageShellInDevEnv(sshSession ssh.Session) error
-	ManageShellPTYInDevEnv(sshSession ssh.Session) error
-	ManageExecInDevEnv(sshSession ssh.Session) error
+	ManageShellInDevEnv(sshSession ssh.Session, devEnv *devenv.DevEnv) error
+	ManageShellPTYInDevEnv(sshSession ssh.Session, devEnv *devenv.DevEnv) error
+	ManageExecInDevEnv(sshSession ssh.Session, devEnv *devenv.DevEnv) error
 	ManageShellPTY(sshSession ssh.Session) error
 	ManageShell(sshSession ssh.Session) error
 	ManageExec(sshSession ssh.Session) error
@@ -19,14 +21,17 @@ type SessionExecShellManager interface {
 
 type Session struct {
 	manager SessionExecShellManager
+	devEnv  *devenv.DevEnv
 }
 
 func NewSession(
 	manager SessionExecShellManager,
+	devEnv *devenv.DevEnv,
 ) Session {
 
 	return Session{
 		manager: manager,
+		devEnv:  devEnv,
 	}
 }
 
@@ -54,7 +59,7 @@ func (s Session) Start(sshSession ssh.Session) {
 
 	isContainerRunning, err := docker.IsContainerRunning(
 		dockerClient,
-		constants.DevEnvDockerContainerName,
+		s.devEnv.DockerContainerName,
 	)
 
 	// Same than previous comment
@@ -62,6 +67,10 @@ func (s Session) Start(sshSession ssh.Session) {
 		log.Println(err)
 	}
 
+	if isContainerRunning {
+		runWorkspaceHooksBeforeSession(sshSession, dockerClient, s.devEnv)
+	}
+
 	if len(sshSession.Command()) == 0 { // "shell" session
 		_, _, hasPTY := sshSession.Pty()
 
@@ -71,7 +80,7 @@ func (s Session) Start(sshSession ssh.Session) {
 			// 	return
 			// }
 
-			sessionError = s.manager.ManageShellPTYInDevEnv(sshSession)
+			sessionError = s.manager.ManageShellPTYInDevEnv(sshSession, s.devEnv)
 			return
 		}
 
@@ -80,7 +89,7 @@ func (s Session) Start(sshSession ssh.Session) {
 		// 	return
 		// }
 
-		sessionError = s.manager.ManageShellInDevEnv(sshSession)
+		sessionError = s.manager.ManageShellInDevEnv(sshSession, s.devEnv)
 		return
 	}
 
@@ -90,5 +99,39 @@ func (s Session) Start(sshSession ssh.Session) {
 	}
 
 	// "exec" session
-	sessionError = s.manager.ManageExecInDevEnv(sshSession)
+	sessionError = s.manager.ManageExecInDevEnv(sshSession, s.devEnv)
+}
+
+// runWorkspaceHooksBeforeSession runs the "attach" hooks during the first
+// session following each container start (see "RunWorkspaceHooksBeforeSession").
+// The hooks are stopped if the session ends. Their output is sent to
+// the "shell" sessions only (to not break the output of "exec" sessions).
+// Errors are logged to let users login even if a hook cannot be run.
+func runWorkspaceHooksBeforeSession(
+	sshSession ssh.Session,
+	dockerClient *client.Client,
+	devEnv *devenv.DevEnv,
+) {
+
+	hooksOutputWriter := log.Writer()
+
+	if len(sshSession.Command()) == 0 { // "shell" session
+		hooksOutputWriter = sshSession.Stderr()
+	}
+
+	err := devenv.RunWorkspaceHooksBeforeSession(
+		sshSession.Context(),
+		dockerClient,
+		devEnv,
+		hooksOutputWriter,
+	)
+
+	// Run by another session. Don't wait for them.
+	if err != nil && errors.Is(err, devenv.ErrWorkspaceHooksRunning) {
+		return
+	}
+
+	if err != nil {
+		log.Println(err)
+	}
 }
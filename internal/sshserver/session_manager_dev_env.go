@@ -17,7 +17,11 @@ import (
 	"github.com/recode-sh/agent/internal/docker"
 )
 
-func (s SessionManager) ManageShellInDevEnv(sshSession ssh.Session) error {
+func (s SessionManager) ManageShellInDevEnv(
+	sshSession ssh.Session,
+	devEnv *devenv.DevEnv,
+) error {
+
 	_, _, hasPTY := sshSession.Pty()
 
 	if hasPTY {
@@ -31,7 +35,7 @@ func (s SessionManager) ManageShellInDevEnv(sshSession ssh.Session) error {
 	}
 
 	vscodeWorkspaceConfig, err := devenv.LoadVSCodeWorkspaceConfig(
-		constants.DevEnvVSCodeWorkspaceConfigFilePath,
+		devEnv.VSCodeWorkspaceConfigFilePath,
 	)
 
 	if err != nil {
@@ -46,7 +50,7 @@ func (s SessionManager) ManageShellInDevEnv(sshSession ssh.Session) error {
 
 	exec, err := dockerClient.ContainerExecCreate(
 		context.TODO(),
-		constants.DevEnvDockerContainerName,
+		devEnv.DockerContainerName,
 		types.ExecConfig{
 			AttachStdin:  true,
 			AttachStdout: true,
@@ -55,7 +59,7 @@ func (s SessionManager) ManageShellInDevEnv(sshSession ssh.Session) error {
 			Tty:          false,
 			Cmd:          []string{"/bin/bash"},
 			Env:          []string{},
-			WorkingDir:   constants.DevEnvWorkspaceDirPath,
+			WorkingDir:   devEnv.WorkspaceDirPath,
 			User:         constants.DevEnvRecodeUserName,
 			Privileged:   true,
 		},
@@ -150,7 +154,11 @@ func (s SessionManager) ManageShellInDevEnv(sshSession ssh.Session) error {
 	}
 }
 
-func (s SessionManager) ManageShellPTYInDevEnv(sshSession ssh.Session) error {
+func (s SessionManager) ManageShellPTYInDevEnv(
+	sshSession ssh.Session,
+	devEnv *devenv.DevEnv,
+) error {
+
 	ptyReq, windowChan, hasPTY := sshSession.Pty()
 
 	if !hasPTY {
@@ -171,7 +179,7 @@ func (s SessionManager) ManageShellPTYInDevEnv(sshSession ssh.Session) error {
 
 	exec, err := dockerClient.ContainerExecCreate(
 		context.TODO(),
-		constants.DevEnvDockerContainerName,
+		devEnv.DockerContainerName,
 		types.ExecConfig{
 			AttachStdin:  true,
 			AttachStdout: true,
@@ -190,7 +198,7 @@ func (s SessionManager) ManageShellPTYInDevEnv(sshSession ssh.Session) error {
 			Env: []string{
 				fmt.Sprintf("TERM=%s", ptyReq.Term),
 			},
-			WorkingDir: constants.DevEnvWorkspaceDirPath,
+			WorkingDir: devEnv.WorkspaceDirPath,
 			User:       constants.DevEnvRecodeUserName,
 			Privileged: true,
 		},
@@ -264,7 +272,11 @@ func (s SessionManager) ManageShellPTYInDevEnv(sshSession ssh.Session) error {
 	}
 }
 
-func (s SessionManager) ManageExecInDevEnv(sshSession ssh.Session) error {
+func (s SessionManager) ManageExecInDevEnv(
+	sshSession ssh.Session,
+	devEnv *devenv.DevEnv,
+) error {
+
 	passedCmd := sshSession.Command()
 
 	if len(passedCmd) == 0 {
@@ -285,7 +297,7 @@ func (s SessionManager) ManageExecInDevEnv(sshSession ssh.Session) error {
 
 	exec, err := dockerClient.ContainerExecCreate(
 		context.TODO(),
-		constants.DevEnvDockerContainerName,
+		devEnv.DockerContainerName,
 		types.ExecConfig{
 			AttachStdin:  true,
 			AttachStdout: true,
@@ -294,7 +306,7 @@ func (s SessionManager) ManageExecInDevEnv(sshSession ssh.Session) error {
 			Tty:          false,
 			Cmd:          passedCmd,
 			Env:          []string{},
-			WorkingDir:   constants.DevEnvWorkspaceDirPath,
+			WorkingDir:   devEnv.WorkspaceDirPath,
 			User:         constants.DevEnvRecodeUserName,
 			Privileged:   true,
 		},
@@ -0,0 +1,103 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+type ProcessManager struct{}
+
+func NewProcessManager() ProcessManager {
+	return ProcessManager{}
+}
+
+// KillProcessTree sends a "SIGKILL" signal to the
+// process identified by "pid" and to all its descendants.
+func (p ProcessManager) KillProcessTree(pid int) error {
+	childrenByParent, err := p.lookupProcessesChildren()
+
+	if err != nil {
+		return err
+	}
+
+	processesToKill := []int{pid}
+
+	// Descendants are looked up before any kill
+	// to prevent orphans from being reparented
+	for index := 0; index < len(processesToKill); index++ {
+		processesToKill = append(
+			processesToKill,
+			childrenByParent[processesToKill[index]]...,
+		)
+	}
+
+	for _, processToKill := range processesToKill {
+		err = syscall.Kill(processToKill, syscall.SIGKILL)
+
+		// Process may have exited in the meantime
+		if err != nil && err != syscall.ESRCH {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (ProcessManager) lookupProcessesChildren() (map[int][]int, error) {
+	processStatFilePaths, err := filepath.Glob("/proc/[0-9]*/stat")
+
+	if err != nil {
+		return nil, err
+	}
+
+	childrenByParent := map[int][]int{}
+
+	for _, processStatFilePath := range processStatFilePaths {
+		processStat, err := os.ReadFile(processStatFilePath)
+
+		// Process may have exited in the meantime
+		if err != nil {
+			continue
+		}
+
+		// The process name (2nd field) is enclosed in
+		// parentheses and may contain spaces so we only
+		// parse the fields after the last closing one.
+		// eg: 1234 (bash) S 1233 ...
+		processStatAsString := string(processStat)
+		processNameEnd := strings.LastIndex(processStatAsString, ")")
+
+		if processNameEnd == -1 {
+			continue
+		}
+
+		processStatFields := strings.Fields(
+			processStatAsString[processNameEnd+1:],
+		)
+
+		if len(processStatFields) < 2 {
+			continue
+		}
+
+		pid, err := strconv.Atoi(
+			strings.Fields(processStatAsString)[0],
+		)
+
+		if err != nil {
+			continue
+		}
+
+		ppid, err := strconv.Atoi(processStatFields[1])
+
+		if err != nil {
+			continue
+		}
+
+		childrenByParent[ppid] = append(childrenByParent[ppid], pid)
+	}
+
+	return childrenByParent, nil
+}
@@ -21,20 +21,486 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type OperationConflictPolicy int32
+
+const (
+	OperationConflictPolicy_OPERATION_CONFLICT_POLICY_REJECT OperationConflictPolicy = 0
+	OperationConflictPolicy_OPERATION_CONFLICT_POLICY_QUEUE  OperationConflictPolicy = 1
+	OperationConflictPolicy_OPERATION_CONFLICT_POLICY_ATTACH OperationConflictPolicy = 2
+)
+
+// Enum value maps for OperationConflictPolicy.
+var (
+	OperationConflictPolicy_name = map[int32]string{
+		0: "OPERATION_CONFLICT_POLICY_REJECT",
+		1: "OPERATION_CONFLICT_POLICY_QUEUE",
+		2: "OPERATION_CONFLICT_POLICY_ATTACH",
+	}
+	OperationConflictPolicy_value = map[string]int32{
+		"OPERATION_CONFLICT_POLICY_REJECT": 0,
+		"OPERATION_CONFLICT_POLICY_QUEUE":  1,
+		"OPERATION_CONFLICT_POLICY_ATTACH": 2,
+	}
+)
+
+func (x OperationConflictPolicy) Enum() *OperationConflictPolicy {
+	p := new(OperationConflictPolicy)
+	*p = x
+	return p
+}
+
+func (x OperationConflictPolicy) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (OperationConflictPolicy) Descriptor() protoreflect.EnumDescriptor {
+	return file_agent_proto_enumTypes[0].Descriptor()
+}
+
+func (OperationConflictPolicy) Type() protoreflect.EnumType {
+	return &file_agent_proto_enumTypes[0]
+}
+
+func (x OperationConflictPolicy) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use OperationConflictPolicy.Descriptor instead.
+func (OperationConflictPolicy) EnumDescriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{0}
+}
+
+type HookFailurePolicy int32
+
+const (
+	// The running hooks are canceled once a hook has failed
+	HookFailurePolicy_HOOK_FAILURE_POLICY_FAIL_FAST HookFailurePolicy = 0
+	// The hooks that don't depend on the failing one are still run
+	HookFailurePolicy_HOOK_FAILURE_POLICY_CONTINUE HookFailurePolicy = 1
+)
+
+// Enum value maps for HookFailurePolicy.
+var (
+	HookFailurePolicy_name = map[int32]string{
+		0: "HOOK_FAILURE_POLICY_FAIL_FAST",
+		1: "HOOK_FAILURE_POLICY_CONTINUE",
+	}
+	HookFailurePolicy_value = map[string]int32{
+		"HOOK_FAILURE_POLICY_FAIL_FAST": 0,
+		"HOOK_FAILURE_POLICY_CONTINUE":  1,
+	}
+)
+
+func (x HookFailurePolicy) Enum() *HookFailurePolicy {
+	p := new(HookFailurePolicy)
+	*p = x
+	return p
+}
+
+func (x HookFailurePolicy) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (HookFailurePolicy) Descriptor() protoreflect.EnumDescriptor {
+	return file_agent_proto_enumTypes[1].Descriptor()
+}
+
+func (HookFailurePolicy) Type() protoreflect.EnumType {
+	return &file_agent_proto_enumTypes[1]
+}
+
+func (x HookFailurePolicy) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use HookFailurePolicy.Descriptor instead.
+func (HookFailurePolicy) EnumDescriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{1}
+}
+
+type WorkspaceLayout int32
+
+const (
+	// "/home/recode/workspace/<name>"
+	WorkspaceLayout_WORKSPACE_LAYOUT_NAME WorkspaceLayout = 0
+	// "/home/recode/workspace/<owner>/<name>"
+	WorkspaceLayout_WORKSPACE_LAYOUT_OWNER_AND_NAME WorkspaceLayout = 1
+)
+
+// Enum value maps for WorkspaceLayout.
+var (
+	WorkspaceLayout_name = map[int32]string{
+		0: "WORKSPACE_LAYOUT_NAME",
+		1: "WORKSPACE_LAYOUT_OWNER_AND_NAME",
+	}
+	WorkspaceLayout_value = map[string]int32{
+		"WORKSPACE_LAYOUT_NAME":           0,
+		"WORKSPACE_LAYOUT_OWNER_AND_NAME": 1,
+	}
+)
+
+func (x WorkspaceLayout) Enum() *WorkspaceLayout {
+	p := new(WorkspaceLayout)
+	*p = x
+	return p
+}
+
+func (x WorkspaceLayout) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (WorkspaceLayout) Descriptor() protoreflect.EnumDescriptor {
+	return file_agent_proto_enumTypes[2].Descriptor()
+}
+
+func (WorkspaceLayout) Type() protoreflect.EnumType {
+	return &file_agent_proto_enumTypes[2]
+}
+
+func (x WorkspaceLayout) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use WorkspaceLayout.Descriptor instead.
+func (WorkspaceLayout) EnumDescriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{2}
+}
+
+type WorkspaceSyncMode int32
+
+const (
+	WorkspaceSyncMode_WORKSPACE_SYNC_MODE_RESET       WorkspaceSyncMode = 0
+	WorkspaceSyncMode_WORKSPACE_SYNC_MODE_INCREMENTAL WorkspaceSyncMode = 1
+)
+
+// Enum value maps for WorkspaceSyncMode.
+var (
+	WorkspaceSyncMode_name = map[int32]string{
+		0: "WORKSPACE_SYNC_MODE_RESET",
+		1: "WORKSPACE_SYNC_MODE_INCREMENTAL",
+	}
+	WorkspaceSyncMode_value = map[string]int32{
+		"WORKSPACE_SYNC_MODE_RESET":       0,
+		"WORKSPACE_SYNC_MODE_INCREMENTAL": 1,
+	}
+)
+
+func (x WorkspaceSyncMode) Enum() *WorkspaceSyncMode {
+	p := new(WorkspaceSyncMode)
+	*p = x
+	return p
+}
+
+func (x WorkspaceSyncMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (WorkspaceSyncMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_agent_proto_enumTypes[3].Descriptor()
+}
+
+func (WorkspaceSyncMode) Type() protoreflect.EnumType {
+	return &file_agent_proto_enumTypes[3]
+}
+
+func (x WorkspaceSyncMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use WorkspaceSyncMode.Descriptor instead.
+func (WorkspaceSyncMode) EnumDescriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{3}
+}
+
+type StepType int32
+
+const (
+	StepType_STEP_TYPE_UNSPECIFIED              StepType = 0
+	StepType_STEP_TYPE_CLONE_USER_CONFIG_REPO   StepType = 1
+	StepType_STEP_TYPE_CLONE_DEV_ENV_REPO       StepType = 2
+	StepType_STEP_TYPE_CLONE_REPO               StepType = 3
+	StepType_STEP_TYPE_BUILD_USER_CONFIG_IMAGE  StepType = 4
+	StepType_STEP_TYPE_BUILD_DEV_ENV_REPO_IMAGE StepType = 5
+	StepType_STEP_TYPE_START_CONTAINER          StepType = 6
+	StepType_STEP_TYPE_RUN_HOOK                 StepType = 7
+	StepType_STEP_TYPE_SYNC_REPO                StepType = 8
+	StepType_STEP_TYPE_INIT_SUBMODULE           StepType = 9
+	StepType_STEP_TYPE_PULL_LFS_OBJECTS         StepType = 10
+)
+
+// Enum value maps for StepType.
+var (
+	StepType_name = map[int32]string{
+		0:  "STEP_TYPE_UNSPECIFIED",
+		1:  "STEP_TYPE_CLONE_USER_CONFIG_REPO",
+		2:  "STEP_TYPE_CLONE_DEV_ENV_REPO",
+		3:  "STEP_TYPE_CLONE_REPO",
+		4:  "STEP_TYPE_BUILD_USER_CONFIG_IMAGE",
+		5:  "STEP_TYPE_BUILD_DEV_ENV_REPO_IMAGE",
+		6:  "STEP_TYPE_START_CONTAINER",
+		7:  "STEP_TYPE_RUN_HOOK",
+		8:  "STEP_TYPE_SYNC_REPO",
+		9:  "STEP_TYPE_INIT_SUBMODULE",
+		10: "STEP_TYPE_PULL_LFS_OBJECTS",
+	}
+	StepType_value = map[string]int32{
+		"STEP_TYPE_UNSPECIFIED":              0,
+		"STEP_TYPE_CLONE_USER_CONFIG_REPO":   1,
+		"STEP_TYPE_CLONE_DEV_ENV_REPO":       2,
+		"STEP_TYPE_CLONE_REPO":               3,
+		"STEP_TYPE_BUILD_USER_CONFIG_IMAGE":  4,
+		"STEP_TYPE_BUILD_DEV_ENV_REPO_IMAGE": 5,
+		"STEP_TYPE_START_CONTAINER":          6,
+		"STEP_TYPE_RUN_HOOK":                 7,
+		"STEP_TYPE_SYNC_REPO":                8,
+		"STEP_TYPE_INIT_SUBMODULE":           9,
+		"STEP_TYPE_PULL_LFS_OBJECTS":         10,
+	}
+)
+
+func (x StepType) Enum() *StepType {
+	p := new(StepType)
+	*p = x
+	return p
+}
+
+func (x StepType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (StepType) Descriptor() protoreflect.EnumDescriptor {
+	return file_agent_proto_enumTypes[4].Descriptor()
+}
+
+func (StepType) Type() protoreflect.EnumType {
+	return &file_agent_proto_enumTypes[4]
+}
+
+func (x StepType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use StepType.Descriptor instead.
+func (StepType) EnumDescriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{4}
+}
+
+type StepStatus int32
+
+const (
+	StepStatus_STEP_STATUS_UNSPECIFIED StepStatus = 0
+	StepStatus_STEP_STATUS_STARTED     StepStatus = 1
+	StepStatus_STEP_STATUS_FINISHED    StepStatus = 2
+	StepStatus_STEP_STATUS_FAILED      StepStatus = 3
+	// Sent for the hooks that were not run because the hooks they
+	// depend on have failed (or because another hook has failed in
+	// "fail fast" mode). The reason is set in "error_message".
+	StepStatus_STEP_STATUS_SKIPPED StepStatus = 4
+)
+
+// Enum value maps for StepStatus.
+var (
+	StepStatus_name = map[int32]string{
+		0: "STEP_STATUS_UNSPECIFIED",
+		1: "STEP_STATUS_STARTED",
+		2: "STEP_STATUS_FINISHED",
+		3: "STEP_STATUS_FAILED",
+		4: "STEP_STATUS_SKIPPED",
+	}
+	StepStatus_value = map[string]int32{
+		"STEP_STATUS_UNSPECIFIED": 0,
+		"STEP_STATUS_STARTED":     1,
+		"STEP_STATUS_FINISHED":    2,
+		"STEP_STATUS_FAILED":      3,
+		"STEP_STATUS_SKIPPED":     4,
+	}
+)
+
+func (x StepStatus) Enum() *StepStatus {
+	p := new(StepStatus)
+	*p = x
+	return p
+}
+
+func (x StepStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (StepStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_agent_proto_enumTypes[5].Descriptor()
+}
+
+func (StepStatus) Type() protoreflect.EnumType {
+	return &file_agent_proto_enumTypes[5]
+}
+
+func (x StepStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use StepStatus.Descriptor instead.
+func (StepStatus) EnumDescriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{5}
+}
+
+type ErrorCode int32
+
+const (
+	ErrorCode_ERROR_CODE_UNSPECIFIED            ErrorCode = 0
+	ErrorCode_ERROR_CODE_INTERNAL               ErrorCode = 1
+	ErrorCode_ERROR_CODE_CANCELED               ErrorCode = 2
+	ErrorCode_ERROR_CODE_CLONE_FAILED           ErrorCode = 3
+	ErrorCode_ERROR_CODE_INVALID_DOCKERFILE     ErrorCode = 4
+	ErrorCode_ERROR_CODE_IMAGE_BUILD_FAILED     ErrorCode = 5
+	ErrorCode_ERROR_CODE_CONTAINER_START_FAILED ErrorCode = 6
+	ErrorCode_ERROR_CODE_HOOK_FAILED            ErrorCode = 7
+	// Multiple repositories would be cloned in the same directory
+	ErrorCode_ERROR_CODE_WORKSPACE_LAYOUT_CONFLICT ErrorCode = 8
+)
+
+// Enum value maps for ErrorCode.
+var (
+	ErrorCode_name = map[int32]string{
+		0: "ERROR_CODE_UNSPECIFIED",
+		1: "ERROR_CODE_INTERNAL",
+		2: "ERROR_CODE_CANCELED",
+		3: "ERROR_CODE_CLONE_FAILED",
+		4: "ERROR_CODE_INVALID_DOCKERFILE",
+		5: "ERROR_CODE_IMAGE_BUILD_FAILED",
+		6: "ERROR_CODE_CONTAINER_START_FAILED",
+		7: "ERROR_CODE_HOOK_FAILED",
+		8: "ERROR_CODE_WORKSPACE_LAYOUT_CONFLICT",
+	}
+	ErrorCode_value = map[string]int32{
+		"ERROR_CODE_UNSPECIFIED":               0,
+		"ERROR_CODE_INTERNAL":                  1,
+		"ERROR_CODE_CANCELED":                  2,
+		"ERROR_CODE_CLONE_FAILED":              3,
+		"ERROR_CODE_INVALID_DOCKERFILE":        4,
+		"ERROR_CODE_IMAGE_BUILD_FAILED":        5,
+		"ERROR_CODE_CONTAINER_START_FAILED":    6,
+		"ERROR_CODE_HOOK_FAILED":               7,
+		"ERROR_CODE_WORKSPACE_LAYOUT_CONFLICT": 8,
+	}
+)
+
+func (x ErrorCode) Enum() *ErrorCode {
+	p := new(ErrorCode)
+	*p = x
+	return p
+}
+
+func (x ErrorCode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ErrorCode) Descriptor() protoreflect.EnumDescriptor {
+	return file_agent_proto_enumTypes[6].Descriptor()
+}
+
+func (ErrorCode) Type() protoreflect.EnumType {
+	return &file_agent_proto_enumTypes[6]
+}
+
+func (x ErrorCode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ErrorCode.Descriptor instead.
+func (ErrorCode) EnumDescriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{6}
+}
+
+type Operation struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id         string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Type       string `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	StartedAt  string `protobuf:"bytes,3,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	DevEnvName string `protobuf:"bytes,4,opt,name=dev_env_name,json=devEnvName,proto3" json:"dev_env_name,omitempty"`
+}
+
+func (x *Operation) Reset() {
+	*x = Operation{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agent_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Operation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Operation) ProtoMessage() {}
+
+func (x *Operation) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Operation.ProtoReflect.Descriptor instead.
+func (*Operation) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Operation) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Operation) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Operation) GetStartedAt() string {
+	if x != nil {
+		return x.StartedAt
+	}
+	return ""
+}
+
+func (x *Operation) GetDevEnvName() string {
+	if x != nil {
+		return x.DevEnvName
+	}
+	return ""
+}
+
 type InitInstanceRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	DevEnvNameSlug  string `protobuf:"bytes,1,opt,name=dev_env_name_slug,json=devEnvNameSlug,proto3" json:"dev_env_name_slug,omitempty"`
-	GithubUserEmail string `protobuf:"bytes,2,opt,name=github_user_email,json=githubUserEmail,proto3" json:"github_user_email,omitempty"`
-	UserFullName    string `protobuf:"bytes,3,opt,name=user_full_name,json=userFullName,proto3" json:"user_full_name,omitempty"`
+	DevEnvNameSlug  string                  `protobuf:"bytes,1,opt,name=dev_env_name_slug,json=devEnvNameSlug,proto3" json:"dev_env_name_slug,omitempty"`
+	GithubUserEmail string                  `protobuf:"bytes,2,opt,name=github_user_email,json=githubUserEmail,proto3" json:"github_user_email,omitempty"`
+	UserFullName    string                  `protobuf:"bytes,3,opt,name=user_full_name,json=userFullName,proto3" json:"user_full_name,omitempty"`
+	OnConflict      OperationConflictPolicy `protobuf:"varint,4,opt,name=on_conflict,json=onConflict,proto3,enum=agent.OperationConflictPolicy" json:"on_conflict,omitempty"`
+	// The operation is canceled when its client cancels the call or
+	// disconnects unless this is set (its replies may then be
+	// followed using "WatchOperation" after a disconnection)
+	ContinueOnDisconnect bool `protobuf:"varint,5,opt,name=continue_on_disconnect,json=continueOnDisconnect,proto3" json:"continue_on_disconnect,omitempty"`
 }
 
 func (x *InitInstanceRequest) Reset() {
 	*x = InitInstanceRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_agent_proto_msgTypes[0]
+		mi := &file_agent_proto_msgTypes[1]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -47,7 +513,7 @@ func (x *InitInstanceRequest) String() string {
 func (*InitInstanceRequest) ProtoMessage() {}
 
 func (x *InitInstanceRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_agent_proto_msgTypes[0]
+	mi := &file_agent_proto_msgTypes[1]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -60,7 +526,7 @@ func (x *InitInstanceRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use InitInstanceRequest.ProtoReflect.Descriptor instead.
 func (*InitInstanceRequest) Descriptor() ([]byte, []int) {
-	return file_agent_proto_rawDescGZIP(), []int{0}
+	return file_agent_proto_rawDescGZIP(), []int{1}
 }
 
 func (x *InitInstanceRequest) GetDevEnvNameSlug() string {
@@ -84,6 +550,20 @@ func (x *InitInstanceRequest) GetUserFullName() string {
 	return ""
 }
 
+func (x *InitInstanceRequest) GetOnConflict() OperationConflictPolicy {
+	if x != nil {
+		return x.OnConflict
+	}
+	return OperationConflictPolicy_OPERATION_CONFLICT_POLICY_REJECT
+}
+
+func (x *InitInstanceRequest) GetContinueOnDisconnect() bool {
+	if x != nil {
+		return x.ContinueOnDisconnect
+	}
+	return false
+}
+
 type InitInstanceReply struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -98,7 +578,7 @@ type InitInstanceReply struct {
 func (x *InitInstanceReply) Reset() {
 	*x = InitInstanceReply{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_agent_proto_msgTypes[1]
+		mi := &file_agent_proto_msgTypes[2]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -111,7 +591,7 @@ func (x *InitInstanceReply) String() string {
 func (*InitInstanceReply) ProtoMessage() {}
 
 func (x *InitInstanceReply) ProtoReflect() protoreflect.Message {
-	mi := &file_agent_proto_msgTypes[1]
+	mi := &file_agent_proto_msgTypes[2]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -124,7 +604,7 @@ func (x *InitInstanceReply) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use InitInstanceReply.ProtoReflect.Descriptor instead.
 func (*InitInstanceReply) Descriptor() ([]byte, []int) {
-	return file_agent_proto_rawDescGZIP(), []int{1}
+	return file_agent_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *InitInstanceReply) GetLogLineHeader() string {
@@ -160,16 +640,64 @@ type BuildAndStartDevEnvRequest struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	DevEnvRepoOwner     string `protobuf:"bytes,1,opt,name=dev_env_repo_owner,json=devEnvRepoOwner,proto3" json:"dev_env_repo_owner,omitempty"`
-	DevEnvRepoName      string `protobuf:"bytes,2,opt,name=dev_env_repo_name,json=devEnvRepoName,proto3" json:"dev_env_repo_name,omitempty"`
-	UserConfigRepoOwner string `protobuf:"bytes,3,opt,name=user_config_repo_owner,json=userConfigRepoOwner,proto3" json:"user_config_repo_owner,omitempty"`
-	UserConfigRepoName  string `protobuf:"bytes,4,opt,name=user_config_repo_name,json=userConfigRepoName,proto3" json:"user_config_repo_name,omitempty"`
+	DevEnvRepoOwner     string                  `protobuf:"bytes,1,opt,name=dev_env_repo_owner,json=devEnvRepoOwner,proto3" json:"dev_env_repo_owner,omitempty"`
+	DevEnvRepoName      string                  `protobuf:"bytes,2,opt,name=dev_env_repo_name,json=devEnvRepoName,proto3" json:"dev_env_repo_name,omitempty"`
+	UserConfigRepoOwner string                  `protobuf:"bytes,3,opt,name=user_config_repo_owner,json=userConfigRepoOwner,proto3" json:"user_config_repo_owner,omitempty"`
+	UserConfigRepoName  string                  `protobuf:"bytes,4,opt,name=user_config_repo_name,json=userConfigRepoName,proto3" json:"user_config_repo_name,omitempty"`
+	OnConflict          OperationConflictPolicy `protobuf:"varint,5,opt,name=on_conflict,json=onConflict,proto3,enum=agent.OperationConflictPolicy" json:"on_conflict,omitempty"`
+	WorkspaceSyncMode   WorkspaceSyncMode       `protobuf:"varint,6,opt,name=workspace_sync_mode,json=workspaceSyncMode,proto3,enum=agent.WorkspaceSyncMode" json:"workspace_sync_mode,omitempty"`
+	ArchiveRemovedRepos bool                    `protobuf:"varint,7,opt,name=archive_removed_repos,json=archiveRemovedRepos,proto3" json:"archive_removed_repos,omitempty"`
+	// The default development environment is used when empty
+	DevEnvName string `protobuf:"bytes,8,opt,name=dev_env_name,json=devEnvName,proto3" json:"dev_env_name,omitempty"`
+	// Git providers: "github" (default when empty), "gitlab",
+	// "ssh://user@host[:port]", "https://host" or "file:///base/dir".
+	// Repositories in the "dev_env.Dockerfile" labels default to
+	// the provider of the development environment repository.
+	DevEnvRepoGitProvider     string `protobuf:"bytes,9,opt,name=dev_env_repo_git_provider,json=devEnvRepoGitProvider,proto3" json:"dev_env_repo_git_provider,omitempty"`
+	UserConfigRepoGitProvider string `protobuf:"bytes,10,opt,name=user_config_repo_git_provider,json=userConfigRepoGitProvider,proto3" json:"user_config_repo_git_provider,omitempty"`
+	// Branches, tags or commit SHAs (the default branch is used when empty).
+	// Repositories in the "dev_env.Dockerfile" labels may be
+	// pinned using the "owner/name@ref" syntax.
+	DevEnvRepoRef     string `protobuf:"bytes,11,opt,name=dev_env_repo_ref,json=devEnvRepoRef,proto3" json:"dev_env_repo_ref,omitempty"`
+	UserConfigRepoRef string `protobuf:"bytes,12,opt,name=user_config_repo_ref,json=userConfigRepoRef,proto3" json:"user_config_repo_ref,omitempty"`
+	// Used for all the repositories unless overridden
+	// in "repositories_clone_options" (full clones when not set)
+	DefaultCloneOptions      *CloneOptions             `protobuf:"bytes,13,opt,name=default_clone_options,json=defaultCloneOptions,proto3" json:"default_clone_options,omitempty"`
+	RepositoriesCloneOptions []*RepositoryCloneOptions `protobuf:"bytes,14,rep,name=repositories_clone_options,json=repositoriesCloneOptions,proto3" json:"repositories_clone_options,omitempty"`
+	// Repositories may also be cloned in explicit paths (relative
+	// to the workspace) using the "owner/name=path" syntax in labels
+	WorkspaceLayout WorkspaceLayout `protobuf:"varint,15,opt,name=workspace_layout,json=workspaceLayout,proto3,enum=agent.WorkspaceLayout" json:"workspace_layout,omitempty"`
+	// Used for clones, image builds and container starts
+	// (the defaults are used for the fields not set)
+	RetryPolicy *RetryPolicy `protobuf:"bytes,16,opt,name=retry_policy,json=retryPolicy,proto3" json:"retry_policy,omitempty"`
+	// Max number of repository hooks run concurrently (defaults to 4).
+	// Repositories may declare the ones whose hooks must be run first
+	// using the "sh.recode.hooks.depends_on" label.
+	HooksParallelism   int32             `protobuf:"varint,17,opt,name=hooks_parallelism,json=hooksParallelism,proto3" json:"hooks_parallelism,omitempty"`
+	HooksFailurePolicy HookFailurePolicy `protobuf:"varint,18,opt,name=hooks_failure_policy,json=hooksFailurePolicy,proto3,enum=agent.HookFailurePolicy" json:"hooks_failure_policy,omitempty"`
+	// Hooks are considered non-critical: the development environment is
+	// left started when hooks fail (the failing steps are reported in
+	// the result). Implies "HOOK_FAILURE_POLICY_CONTINUE".
+	ContinueOnHookError bool               `protobuf:"varint,19,opt,name=continue_on_hook_error,json=continueOnHookError,proto3" json:"continue_on_hook_error,omitempty"`
+	ImageBuildOptions   *ImageBuildOptions `protobuf:"bytes,20,opt,name=image_build_options,json=imageBuildOptions,proto3" json:"image_build_options,omitempty"`
+	// Exposed to BuildKit builds as secret mounts (eg: "RUN
+	// --mount=type=secret,id=npm_token"). Override the secrets stored
+	// on the instance. Never journaled and redacted from the replies.
+	BuildSecrets []*BuildSecret `protobuf:"bytes,21,rep,name=build_secrets,json=buildSecrets,proto3" json:"build_secrets,omitempty"`
+	// Override the build args set in the "sh.recode.build_args" label of
+	// the user config Dockerfile and in the "build_args.env" files.
+	// Args not declared in the built Dockerfile are ignored.
+	BuildArgs map[string]string `protobuf:"bytes,22,rep,name=build_args,json=buildArgs,proto3" json:"build_args,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// The operation is canceled when its client cancels the call or
+	// disconnects unless this is set (its replies may then be
+	// followed using "WatchOperation" after a disconnection)
+	ContinueOnDisconnect bool `protobuf:"varint,23,opt,name=continue_on_disconnect,json=continueOnDisconnect,proto3" json:"continue_on_disconnect,omitempty"`
 }
 
 func (x *BuildAndStartDevEnvRequest) Reset() {
 	*x = BuildAndStartDevEnvRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_agent_proto_msgTypes[2]
+		mi := &file_agent_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -182,7 +710,7 @@ func (x *BuildAndStartDevEnvRequest) String() string {
 func (*BuildAndStartDevEnvRequest) ProtoMessage() {}
 
 func (x *BuildAndStartDevEnvRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_agent_proto_msgTypes[2]
+	mi := &file_agent_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -195,7 +723,7 @@ func (x *BuildAndStartDevEnvRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BuildAndStartDevEnvRequest.ProtoReflect.Descriptor instead.
 func (*BuildAndStartDevEnvRequest) Descriptor() ([]byte, []int) {
-	return file_agent_proto_rawDescGZIP(), []int{2}
+	return file_agent_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *BuildAndStartDevEnvRequest) GetDevEnvRepoOwner() string {
@@ -226,169 +754,3473 @@ func (x *BuildAndStartDevEnvRequest) GetUserConfigRepoName() string {
 	return ""
 }
 
-type BuildAndStartDevEnvReply struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
+func (x *BuildAndStartDevEnvRequest) GetOnConflict() OperationConflictPolicy {
+	if x != nil {
+		return x.OnConflict
+	}
+	return OperationConflictPolicy_OPERATION_CONFLICT_POLICY_REJECT
+}
 
-	LogLineHeader string `protobuf:"bytes,1,opt,name=log_line_header,json=logLineHeader,proto3" json:"log_line_header,omitempty"`
-	LogLine       string `protobuf:"bytes,2,opt,name=log_line,json=logLine,proto3" json:"log_line,omitempty"`
+func (x *BuildAndStartDevEnvRequest) GetWorkspaceSyncMode() WorkspaceSyncMode {
+	if x != nil {
+		return x.WorkspaceSyncMode
+	}
+	return WorkspaceSyncMode_WORKSPACE_SYNC_MODE_RESET
 }
 
-func (x *BuildAndStartDevEnvReply) Reset() {
-	*x = BuildAndStartDevEnvReply{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_agent_proto_msgTypes[3]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
+func (x *BuildAndStartDevEnvRequest) GetArchiveRemovedRepos() bool {
+	if x != nil {
+		return x.ArchiveRemovedRepos
 	}
+	return false
 }
 
-func (x *BuildAndStartDevEnvReply) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *BuildAndStartDevEnvRequest) GetDevEnvName() string {
+	if x != nil {
+		return x.DevEnvName
+	}
+	return ""
 }
 
-func (*BuildAndStartDevEnvReply) ProtoMessage() {}
+func (x *BuildAndStartDevEnvRequest) GetDevEnvRepoGitProvider() string {
+	if x != nil {
+		return x.DevEnvRepoGitProvider
+	}
+	return ""
+}
+
+func (x *BuildAndStartDevEnvRequest) GetUserConfigRepoGitProvider() string {
+	if x != nil {
+		return x.UserConfigRepoGitProvider
+	}
+	return ""
+}
+
+func (x *BuildAndStartDevEnvRequest) GetDevEnvRepoRef() string {
+	if x != nil {
+		return x.DevEnvRepoRef
+	}
+	return ""
+}
+
+func (x *BuildAndStartDevEnvRequest) GetUserConfigRepoRef() string {
+	if x != nil {
+		return x.UserConfigRepoRef
+	}
+	return ""
+}
+
+func (x *BuildAndStartDevEnvRequest) GetDefaultCloneOptions() *CloneOptions {
+	if x != nil {
+		return x.DefaultCloneOptions
+	}
+	return nil
+}
+
+func (x *BuildAndStartDevEnvRequest) GetRepositoriesCloneOptions() []*RepositoryCloneOptions {
+	if x != nil {
+		return x.RepositoriesCloneOptions
+	}
+	return nil
+}
+
+func (x *BuildAndStartDevEnvRequest) GetWorkspaceLayout() WorkspaceLayout {
+	if x != nil {
+		return x.WorkspaceLayout
+	}
+	return WorkspaceLayout_WORKSPACE_LAYOUT_NAME
+}
+
+func (x *BuildAndStartDevEnvRequest) GetRetryPolicy() *RetryPolicy {
+	if x != nil {
+		return x.RetryPolicy
+	}
+	return nil
+}
+
+func (x *BuildAndStartDevEnvRequest) GetHooksParallelism() int32 {
+	if x != nil {
+		return x.HooksParallelism
+	}
+	return 0
+}
+
+func (x *BuildAndStartDevEnvRequest) GetHooksFailurePolicy() HookFailurePolicy {
+	if x != nil {
+		return x.HooksFailurePolicy
+	}
+	return HookFailurePolicy_HOOK_FAILURE_POLICY_FAIL_FAST
+}
+
+func (x *BuildAndStartDevEnvRequest) GetContinueOnHookError() bool {
+	if x != nil {
+		return x.ContinueOnHookError
+	}
+	return false
+}
+
+func (x *BuildAndStartDevEnvRequest) GetImageBuildOptions() *ImageBuildOptions {
+	if x != nil {
+		return x.ImageBuildOptions
+	}
+	return nil
+}
+
+func (x *BuildAndStartDevEnvRequest) GetBuildSecrets() []*BuildSecret {
+	if x != nil {
+		return x.BuildSecrets
+	}
+	return nil
+}
+
+func (x *BuildAndStartDevEnvRequest) GetBuildArgs() map[string]string {
+	if x != nil {
+		return x.BuildArgs
+	}
+	return nil
+}
+
+func (x *BuildAndStartDevEnvRequest) GetContinueOnDisconnect() bool {
+	if x != nil {
+		return x.ContinueOnDisconnect
+	}
+	return false
+}
+
+type BuildSecret struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Letters, digits, "_", "." and "-"
+	Id    string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Value string `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (x *BuildSecret) Reset() {
+	*x = BuildSecret{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agent_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BuildSecret) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BuildSecret) ProtoMessage() {}
+
+func (x *BuildSecret) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BuildSecret.ProtoReflect.Descriptor instead.
+func (*BuildSecret) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *BuildSecret) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *BuildSecret) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+type ImageBuildOptions struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Builds through BuildKit (enables "RUN --mount=type=cache"
+	// and reuses the inline cache of the previous image)
+	UseBuildkit bool `protobuf:"varint,1,opt,name=use_buildkit,json=useBuildkit,proto3" json:"use_buildkit,omitempty"`
+	// The dangling images and build cache not used during
+	// the last N builds of the instance are removed (defaults to 5)
+	CacheKeepBuilds int32 `protobuf:"varint,2,opt,name=cache_keep_builds,json=cacheKeepBuilds,proto3" json:"cache_keep_builds,omitempty"`
+	// The images of the last N successful builds of the development
+	// environment are kept to let users roll back (defaults to 3)
+	KeepImages int32 `protobuf:"varint,3,opt,name=keep_images,json=keepImages,proto3" json:"keep_images,omitempty"`
+}
+
+func (x *ImageBuildOptions) Reset() {
+	*x = ImageBuildOptions{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agent_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ImageBuildOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImageBuildOptions) ProtoMessage() {}
+
+func (x *ImageBuildOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImageBuildOptions.ProtoReflect.Descriptor instead.
+func (*ImageBuildOptions) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ImageBuildOptions) GetUseBuildkit() bool {
+	if x != nil {
+		return x.UseBuildkit
+	}
+	return false
+}
+
+func (x *ImageBuildOptions) GetCacheKeepBuilds() int32 {
+	if x != nil {
+		return x.CacheKeepBuilds
+	}
+	return 0
+}
+
+func (x *ImageBuildOptions) GetKeepImages() int32 {
+	if x != nil {
+		return x.KeepImages
+	}
+	return 0
+}
+
+type CloneOptions struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Shallow clones when greater than zero
+	Depth int32 `protobuf:"varint,1,opt,name=depth,proto3" json:"depth,omitempty"`
+	// Partial clones (eg: "blob:none", "blob:limit=1m" or "tree:0")
+	Filter string `protobuf:"bytes,2,opt,name=filter,proto3" json:"filter,omitempty"`
+	// Cone mode sparse checkout. The ".recode"
+	// directory is always checked out.
+	SparseCheckoutPaths []string `protobuf:"bytes,3,rep,name=sparse_checkout_paths,json=sparseCheckoutPaths,proto3" json:"sparse_checkout_paths,omitempty"`
+	// Submodules are initialized recursively and
+	// LFS objects are pulled unless skipped
+	SkipSubmodules bool `protobuf:"varint,4,opt,name=skip_submodules,json=skipSubmodules,proto3" json:"skip_submodules,omitempty"`
+	SkipLfs        bool `protobuf:"varint,5,opt,name=skip_lfs,json=skipLfs,proto3" json:"skip_lfs,omitempty"`
+}
+
+func (x *CloneOptions) Reset() {
+	*x = CloneOptions{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agent_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CloneOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CloneOptions) ProtoMessage() {}
+
+func (x *CloneOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CloneOptions.ProtoReflect.Descriptor instead.
+func (*CloneOptions) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CloneOptions) GetDepth() int32 {
+	if x != nil {
+		return x.Depth
+	}
+	return 0
+}
+
+func (x *CloneOptions) GetFilter() string {
+	if x != nil {
+		return x.Filter
+	}
+	return ""
+}
+
+func (x *CloneOptions) GetSparseCheckoutPaths() []string {
+	if x != nil {
+		return x.SparseCheckoutPaths
+	}
+	return nil
+}
+
+func (x *CloneOptions) GetSkipSubmodules() bool {
+	if x != nil {
+		return x.SkipSubmodules
+	}
+	return false
+}
+
+func (x *CloneOptions) GetSkipLfs() bool {
+	if x != nil {
+		return x.SkipLfs
+	}
+	return false
+}
+
+type RepositoryCloneOptions struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// "owner/name"
+	Repository string        `protobuf:"bytes,1,opt,name=repository,proto3" json:"repository,omitempty"`
+	Options    *CloneOptions `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
+}
+
+func (x *RepositoryCloneOptions) Reset() {
+	*x = RepositoryCloneOptions{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agent_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RepositoryCloneOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RepositoryCloneOptions) ProtoMessage() {}
+
+func (x *RepositoryCloneOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RepositoryCloneOptions.ProtoReflect.Descriptor instead.
+func (*RepositoryCloneOptions) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *RepositoryCloneOptions) GetRepository() string {
+	if x != nil {
+		return x.Repository
+	}
+	return ""
+}
+
+func (x *RepositoryCloneOptions) GetOptions() *CloneOptions {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+// Only transient failures (network errors, SSH keys not yet
+// propagated, registry 5xx...) are retried with exponential backoff
+type RetryPolicy struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Including the first attempt (1 disables retries)
+	MaxAttempts       int32 `protobuf:"varint,1,opt,name=max_attempts,json=maxAttempts,proto3" json:"max_attempts,omitempty"`
+	InitialIntervalMs int64 `protobuf:"varint,2,opt,name=initial_interval_ms,json=initialIntervalMs,proto3" json:"initial_interval_ms,omitempty"`
+	MaxIntervalMs     int64 `protobuf:"varint,3,opt,name=max_interval_ms,json=maxIntervalMs,proto3" json:"max_interval_ms,omitempty"`
+	// No retry is attempted once elapsed
+	MaxElapsedTimeMs int64 `protobuf:"varint,4,opt,name=max_elapsed_time_ms,json=maxElapsedTimeMs,proto3" json:"max_elapsed_time_ms,omitempty"`
+}
+
+func (x *RetryPolicy) Reset() {
+	*x = RetryPolicy{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agent_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RetryPolicy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RetryPolicy) ProtoMessage() {}
+
+func (x *RetryPolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RetryPolicy.ProtoReflect.Descriptor instead.
+func (*RetryPolicy) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *RetryPolicy) GetMaxAttempts() int32 {
+	if x != nil {
+		return x.MaxAttempts
+	}
+	return 0
+}
+
+func (x *RetryPolicy) GetInitialIntervalMs() int64 {
+	if x != nil {
+		return x.InitialIntervalMs
+	}
+	return 0
+}
+
+func (x *RetryPolicy) GetMaxIntervalMs() int64 {
+	if x != nil {
+		return x.MaxIntervalMs
+	}
+	return 0
+}
+
+func (x *RetryPolicy) GetMaxElapsedTimeMs() int64 {
+	if x != nil {
+		return x.MaxElapsedTimeMs
+	}
+	return 0
+}
+
+type BuildAndStartDevEnvReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	LogLineHeader       string                     `protobuf:"bytes,1,opt,name=log_line_header,json=logLineHeader,proto3" json:"log_line_header,omitempty"`
+	LogLine             string                     `protobuf:"bytes,2,opt,name=log_line,json=logLine,proto3" json:"log_line,omitempty"`
+	StepEvent           *StepEvent                 `protobuf:"bytes,3,opt,name=step_event,json=stepEvent,proto3" json:"step_event,omitempty"`
+	DockerBuildProgress *DockerBuildProgress       `protobuf:"bytes,4,opt,name=docker_build_progress,json=dockerBuildProgress,proto3" json:"docker_build_progress,omitempty"`
+	Result              *BuildAndStartDevEnvResult `protobuf:"bytes,5,opt,name=result,proto3" json:"result,omitempty"`
+	ResolvedRepository  *ResolvedRepository        `protobuf:"bytes,6,opt,name=resolved_repository,json=resolvedRepository,proto3" json:"resolved_repository,omitempty"`
+	GitCloneProgress    *GitCloneProgress          `protobuf:"bytes,7,opt,name=git_clone_progress,json=gitCloneProgress,proto3" json:"git_clone_progress,omitempty"`
+	GitCloneRetry       *GitCloneRetry             `protobuf:"bytes,8,opt,name=git_clone_retry,json=gitCloneRetry,proto3" json:"git_clone_retry,omitempty"`
+	DockerBuildContext  *DockerBuildContext        `protobuf:"bytes,9,opt,name=docker_build_context,json=dockerBuildContext,proto3" json:"docker_build_context,omitempty"`
+}
+
+func (x *BuildAndStartDevEnvReply) Reset() {
+	*x = BuildAndStartDevEnvReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agent_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BuildAndStartDevEnvReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BuildAndStartDevEnvReply) ProtoMessage() {}
 
 func (x *BuildAndStartDevEnvReply) ProtoReflect() protoreflect.Message {
-	mi := &file_agent_proto_msgTypes[3]
+	mi := &file_agent_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BuildAndStartDevEnvReply.ProtoReflect.Descriptor instead.
+func (*BuildAndStartDevEnvReply) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *BuildAndStartDevEnvReply) GetLogLineHeader() string {
+	if x != nil {
+		return x.LogLineHeader
+	}
+	return ""
+}
+
+func (x *BuildAndStartDevEnvReply) GetLogLine() string {
+	if x != nil {
+		return x.LogLine
+	}
+	return ""
+}
+
+func (x *BuildAndStartDevEnvReply) GetStepEvent() *StepEvent {
+	if x != nil {
+		return x.StepEvent
+	}
+	return nil
+}
+
+func (x *BuildAndStartDevEnvReply) GetDockerBuildProgress() *DockerBuildProgress {
+	if x != nil {
+		return x.DockerBuildProgress
+	}
+	return nil
+}
+
+func (x *BuildAndStartDevEnvReply) GetResult() *BuildAndStartDevEnvResult {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *BuildAndStartDevEnvReply) GetResolvedRepository() *ResolvedRepository {
+	if x != nil {
+		return x.ResolvedRepository
+	}
+	return nil
+}
+
+func (x *BuildAndStartDevEnvReply) GetGitCloneProgress() *GitCloneProgress {
+	if x != nil {
+		return x.GitCloneProgress
+	}
+	return nil
+}
+
+func (x *BuildAndStartDevEnvReply) GetGitCloneRetry() *GitCloneRetry {
+	if x != nil {
+		return x.GitCloneRetry
+	}
+	return nil
+}
+
+func (x *BuildAndStartDevEnvReply) GetDockerBuildContext() *DockerBuildContext {
+	if x != nil {
+		return x.DockerBuildContext
+	}
+	return nil
+}
+
+// Throttled (sent at most every 500ms per clone unless the phase changes)
+type GitCloneProgress struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	StepId     string `protobuf:"bytes,1,opt,name=step_id,json=stepId,proto3" json:"step_id,omitempty"`
+	Repository string `protobuf:"bytes,2,opt,name=repository,proto3" json:"repository,omitempty"`
+	// Eg: "Receiving objects" or "Resolving deltas"
+	Phase   string `protobuf:"bytes,3,opt,name=phase,proto3" json:"phase,omitempty"`
+	Percent int32  `protobuf:"varint,4,opt,name=percent,proto3" json:"percent,omitempty"`
+	Current int64  `protobuf:"varint,5,opt,name=current,proto3" json:"current,omitempty"`
+	Total   int64  `protobuf:"varint,6,opt,name=total,proto3" json:"total,omitempty"`
+	// Set only during the "Receiving objects" phase
+	ReceivedBytes int64 `protobuf:"varint,7,opt,name=received_bytes,json=receivedBytes,proto3" json:"received_bytes,omitempty"`
+}
+
+func (x *GitCloneProgress) Reset() {
+	*x = GitCloneProgress{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agent_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GitCloneProgress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GitCloneProgress) ProtoMessage() {}
+
+func (x *GitCloneProgress) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GitCloneProgress.ProtoReflect.Descriptor instead.
+func (*GitCloneProgress) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GitCloneProgress) GetStepId() string {
+	if x != nil {
+		return x.StepId
+	}
+	return ""
+}
+
+func (x *GitCloneProgress) GetRepository() string {
+	if x != nil {
+		return x.Repository
+	}
+	return ""
+}
+
+func (x *GitCloneProgress) GetPhase() string {
+	if x != nil {
+		return x.Phase
+	}
+	return ""
+}
+
+func (x *GitCloneProgress) GetPercent() int32 {
+	if x != nil {
+		return x.Percent
+	}
+	return 0
+}
+
+func (x *GitCloneProgress) GetCurrent() int64 {
+	if x != nil {
+		return x.Current
+	}
+	return 0
+}
+
+func (x *GitCloneProgress) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *GitCloneProgress) GetReceivedBytes() int64 {
+	if x != nil {
+		return x.ReceivedBytes
+	}
+	return 0
+}
+
+// Sent when a failed clone is retried
+type GitCloneRetry struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	StepId       string `protobuf:"bytes,1,opt,name=step_id,json=stepId,proto3" json:"step_id,omitempty"`
+	Repository   string `protobuf:"bytes,2,opt,name=repository,proto3" json:"repository,omitempty"`
+	Attempt      int32  `protobuf:"varint,3,opt,name=attempt,proto3" json:"attempt,omitempty"`
+	MaxAttempts  int32  `protobuf:"varint,4,opt,name=max_attempts,json=maxAttempts,proto3" json:"max_attempts,omitempty"`
+	ErrorMessage string `protobuf:"bytes,5,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+}
+
+func (x *GitCloneRetry) Reset() {
+	*x = GitCloneRetry{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agent_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GitCloneRetry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GitCloneRetry) ProtoMessage() {}
+
+func (x *GitCloneRetry) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GitCloneRetry.ProtoReflect.Descriptor instead.
+func (*GitCloneRetry) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GitCloneRetry) GetStepId() string {
+	if x != nil {
+		return x.StepId
+	}
+	return ""
+}
+
+func (x *GitCloneRetry) GetRepository() string {
+	if x != nil {
+		return x.Repository
+	}
+	return ""
+}
+
+func (x *GitCloneRetry) GetAttempt() int32 {
+	if x != nil {
+		return x.Attempt
+	}
+	return 0
+}
+
+func (x *GitCloneRetry) GetMaxAttempts() int32 {
+	if x != nil {
+		return x.MaxAttempts
+	}
+	return 0
+}
+
+func (x *GitCloneRetry) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+// Sent once a repository is cloned (or synced)
+type ResolvedRepository struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Repository string `protobuf:"bytes,1,opt,name=repository,proto3" json:"repository,omitempty"`
+	Ref        string `protobuf:"bytes,2,opt,name=ref,proto3" json:"ref,omitempty"`
+	CommitSha  string `protobuf:"bytes,3,opt,name=commit_sha,json=commitSha,proto3" json:"commit_sha,omitempty"`
+}
+
+func (x *ResolvedRepository) Reset() {
+	*x = ResolvedRepository{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agent_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ResolvedRepository) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolvedRepository) ProtoMessage() {}
+
+func (x *ResolvedRepository) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolvedRepository.ProtoReflect.Descriptor instead.
+func (*ResolvedRepository) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ResolvedRepository) GetRepository() string {
+	if x != nil {
+		return x.Repository
+	}
+	return ""
+}
+
+func (x *ResolvedRepository) GetRef() string {
+	if x != nil {
+		return x.Ref
+	}
+	return ""
+}
+
+func (x *ResolvedRepository) GetCommitSha() string {
+	if x != nil {
+		return x.CommitSha
+	}
+	return ""
+}
+
+type StepEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	StepId       string     `protobuf:"bytes,1,opt,name=step_id,json=stepId,proto3" json:"step_id,omitempty"`
+	StepType     StepType   `protobuf:"varint,2,opt,name=step_type,json=stepType,proto3,enum=agent.StepType" json:"step_type,omitempty"`
+	Status       StepStatus `protobuf:"varint,3,opt,name=status,proto3,enum=agent.StepStatus" json:"status,omitempty"`
+	Description  string     `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	Repository   string     `protobuf:"bytes,5,opt,name=repository,proto3" json:"repository,omitempty"`
+	DurationMs   int64      `protobuf:"varint,6,opt,name=duration_ms,json=durationMs,proto3" json:"duration_ms,omitempty"`
+	ErrorCode    ErrorCode  `protobuf:"varint,7,opt,name=error_code,json=errorCode,proto3,enum=agent.ErrorCode" json:"error_code,omitempty"`
+	ErrorMessage string     `protobuf:"bytes,8,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+}
+
+func (x *StepEvent) Reset() {
+	*x = StepEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agent_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StepEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StepEvent) ProtoMessage() {}
+
+func (x *StepEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StepEvent.ProtoReflect.Descriptor instead.
+func (*StepEvent) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *StepEvent) GetStepId() string {
+	if x != nil {
+		return x.StepId
+	}
+	return ""
+}
+
+func (x *StepEvent) GetStepType() StepType {
+	if x != nil {
+		return x.StepType
+	}
+	return StepType_STEP_TYPE_UNSPECIFIED
+}
+
+func (x *StepEvent) GetStatus() StepStatus {
+	if x != nil {
+		return x.Status
+	}
+	return StepStatus_STEP_STATUS_UNSPECIFIED
+}
+
+func (x *StepEvent) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *StepEvent) GetRepository() string {
+	if x != nil {
+		return x.Repository
+	}
+	return ""
+}
+
+func (x *StepEvent) GetDurationMs() int64 {
+	if x != nil {
+		return x.DurationMs
+	}
+	return 0
+}
+
+func (x *StepEvent) GetErrorCode() ErrorCode {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ErrorCode_ERROR_CODE_UNSPECIFIED
+}
+
+func (x *StepEvent) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+type DockerBuildProgress struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	StepId      string `protobuf:"bytes,1,opt,name=step_id,json=stepId,proto3" json:"step_id,omitempty"`
+	CurrentStep int32  `protobuf:"varint,2,opt,name=current_step,json=currentStep,proto3" json:"current_step,omitempty"`
+	TotalSteps  int32  `protobuf:"varint,3,opt,name=total_steps,json=totalSteps,proto3" json:"total_steps,omitempty"`
+	Instruction string `protobuf:"bytes,4,opt,name=instruction,proto3" json:"instruction,omitempty"`
+}
+
+func (x *DockerBuildProgress) Reset() {
+	*x = DockerBuildProgress{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agent_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DockerBuildProgress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DockerBuildProgress) ProtoMessage() {}
+
+func (x *DockerBuildProgress) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DockerBuildProgress.ProtoReflect.Descriptor instead.
+func (*DockerBuildProgress) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *DockerBuildProgress) GetStepId() string {
+	if x != nil {
+		return x.StepId
+	}
+	return ""
+}
+
+func (x *DockerBuildProgress) GetCurrentStep() int32 {
+	if x != nil {
+		return x.CurrentStep
+	}
+	return 0
+}
+
+func (x *DockerBuildProgress) GetTotalSteps() int32 {
+	if x != nil {
+		return x.TotalSteps
+	}
+	return 0
+}
+
+func (x *DockerBuildProgress) GetInstruction() string {
+	if x != nil {
+		return x.Instruction
+	}
+	return ""
+}
+
+// Sent before each image build (the files excluded
+// by the ".dockerignore" file are not counted)
+type DockerBuildContext struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	StepId          string `protobuf:"bytes,1,opt,name=step_id,json=stepId,proto3" json:"step_id,omitempty"`
+	SizeBytes       int64  `protobuf:"varint,2,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"`
+	HasDockerignore bool   `protobuf:"varint,3,opt,name=has_dockerignore,json=hasDockerignore,proto3" json:"has_dockerignore,omitempty"`
+}
+
+func (x *DockerBuildContext) Reset() {
+	*x = DockerBuildContext{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agent_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DockerBuildContext) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DockerBuildContext) ProtoMessage() {}
+
+func (x *DockerBuildContext) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DockerBuildContext.ProtoReflect.Descriptor instead.
+func (*DockerBuildContext) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *DockerBuildContext) GetStepId() string {
+	if x != nil {
+		return x.StepId
+	}
+	return ""
+}
+
+func (x *DockerBuildContext) GetSizeBytes() int64 {
+	if x != nil {
+		return x.SizeBytes
+	}
+	return 0
+}
+
+func (x *DockerBuildContext) GetHasDockerignore() bool {
+	if x != nil {
+		return x.HasDockerignore
+	}
+	return false
+}
+
+type BuildAndStartDevEnvResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Also true when hooks have failed if
+	// "continue_on_hook_error" was set
+	Success      bool      `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	ErrorCode    ErrorCode `protobuf:"varint,2,opt,name=error_code,json=errorCode,proto3,enum=agent.ErrorCode" json:"error_code,omitempty"`
+	ErrorMessage string    `protobuf:"bytes,3,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	DurationMs   int64     `protobuf:"varint,4,opt,name=duration_ms,json=durationMs,proto3" json:"duration_ms,omitempty"`
+	// Last event of each step (finished, failed or skipped) in start order
+	Steps []*StepEvent `protobuf:"bytes,5,rep,name=steps,proto3" json:"steps,omitempty"`
+	// True once the container is started (even if hooks have failed)
+	DevEnvStarted bool `protobuf:"varint,6,opt,name=dev_env_started,json=devEnvStarted,proto3" json:"dev_env_started,omitempty"`
+	// Human-readable summary of the failed and skipped
+	// steps (empty when all the steps have finished)
+	Summary string `protobuf:"bytes,7,opt,name=summary,proto3" json:"summary,omitempty"`
+}
+
+func (x *BuildAndStartDevEnvResult) Reset() {
+	*x = BuildAndStartDevEnvResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agent_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BuildAndStartDevEnvResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BuildAndStartDevEnvResult) ProtoMessage() {}
+
+func (x *BuildAndStartDevEnvResult) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BuildAndStartDevEnvResult.ProtoReflect.Descriptor instead.
+func (*BuildAndStartDevEnvResult) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *BuildAndStartDevEnvResult) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *BuildAndStartDevEnvResult) GetErrorCode() ErrorCode {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ErrorCode_ERROR_CODE_UNSPECIFIED
+}
+
+func (x *BuildAndStartDevEnvResult) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+func (x *BuildAndStartDevEnvResult) GetDurationMs() int64 {
+	if x != nil {
+		return x.DurationMs
+	}
+	return 0
+}
+
+func (x *BuildAndStartDevEnvResult) GetSteps() []*StepEvent {
+	if x != nil {
+		return x.Steps
+	}
+	return nil
+}
+
+func (x *BuildAndStartDevEnvResult) GetDevEnvStarted() bool {
+	if x != nil {
+		return x.DevEnvStarted
+	}
+	return false
+}
+
+func (x *BuildAndStartDevEnvResult) GetSummary() string {
+	if x != nil {
+		return x.Summary
+	}
+	return ""
+}
+
+type StopDevEnvRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	StopTimeoutSeconds *uint32                 `protobuf:"varint,1,opt,name=stop_timeout_seconds,json=stopTimeoutSeconds,proto3,oneof" json:"stop_timeout_seconds,omitempty"`
+	OnConflict         OperationConflictPolicy `protobuf:"varint,2,opt,name=on_conflict,json=onConflict,proto3,enum=agent.OperationConflictPolicy" json:"on_conflict,omitempty"`
+	DevEnvName         string                  `protobuf:"bytes,3,opt,name=dev_env_name,json=devEnvName,proto3" json:"dev_env_name,omitempty"`
+	// The operation is canceled when its client cancels the call or
+	// disconnects unless this is set (its replies may then be
+	// followed using "WatchOperation" after a disconnection)
+	ContinueOnDisconnect bool `protobuf:"varint,4,opt,name=continue_on_disconnect,json=continueOnDisconnect,proto3" json:"continue_on_disconnect,omitempty"`
+}
+
+func (x *StopDevEnvRequest) Reset() {
+	*x = StopDevEnvRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agent_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StopDevEnvRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StopDevEnvRequest) ProtoMessage() {}
+
+func (x *StopDevEnvRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StopDevEnvRequest.ProtoReflect.Descriptor instead.
+func (*StopDevEnvRequest) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *StopDevEnvRequest) GetStopTimeoutSeconds() uint32 {
+	if x != nil && x.StopTimeoutSeconds != nil {
+		return *x.StopTimeoutSeconds
+	}
+	return 0
+}
+
+func (x *StopDevEnvRequest) GetOnConflict() OperationConflictPolicy {
+	if x != nil {
+		return x.OnConflict
+	}
+	return OperationConflictPolicy_OPERATION_CONFLICT_POLICY_REJECT
+}
+
+func (x *StopDevEnvRequest) GetDevEnvName() string {
+	if x != nil {
+		return x.DevEnvName
+	}
+	return ""
+}
+
+func (x *StopDevEnvRequest) GetContinueOnDisconnect() bool {
+	if x != nil {
+		return x.ContinueOnDisconnect
+	}
+	return false
+}
+
+type StopDevEnvReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	LogLineHeader string `protobuf:"bytes,1,opt,name=log_line_header,json=logLineHeader,proto3" json:"log_line_header,omitempty"`
+	LogLine       string `protobuf:"bytes,2,opt,name=log_line,json=logLine,proto3" json:"log_line,omitempty"`
+}
+
+func (x *StopDevEnvReply) Reset() {
+	*x = StopDevEnvReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agent_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StopDevEnvReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StopDevEnvReply) ProtoMessage() {}
+
+func (x *StopDevEnvReply) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StopDevEnvReply.ProtoReflect.Descriptor instead.
+func (*StopDevEnvReply) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *StopDevEnvReply) GetLogLineHeader() string {
+	if x != nil {
+		return x.LogLineHeader
+	}
+	return ""
+}
+
+func (x *StopDevEnvReply) GetLogLine() string {
+	if x != nil {
+		return x.LogLine
+	}
+	return ""
+}
+
+type GetDevEnvStatusRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DevEnvName string `protobuf:"bytes,1,opt,name=dev_env_name,json=devEnvName,proto3" json:"dev_env_name,omitempty"`
+}
+
+func (x *GetDevEnvStatusRequest) Reset() {
+	*x = GetDevEnvStatusRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agent_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetDevEnvStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDevEnvStatusRequest) ProtoMessage() {}
+
+func (x *GetDevEnvStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDevEnvStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetDevEnvStatusRequest) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *GetDevEnvStatusRequest) GetDevEnvName() string {
+	if x != nil {
+		return x.DevEnvName
+	}
+	return ""
+}
+
+type GetDevEnvStatusReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ContainerState   string                    `protobuf:"bytes,1,opt,name=container_state,json=containerState,proto3" json:"container_state,omitempty"`
+	ImageId          string                    `protobuf:"bytes,2,opt,name=image_id,json=imageId,proto3" json:"image_id,omitempty"`
+	ImageBuiltAt     string                    `protobuf:"bytes,3,opt,name=image_built_at,json=imageBuiltAt,proto3" json:"image_built_at,omitempty"`
+	Repositories     []*DevEnvRepositoryStatus `protobuf:"bytes,4,rep,name=repositories,proto3" json:"repositories,omitempty"`
+	AgentVersion     string                    `protobuf:"bytes,5,opt,name=agent_version,json=agentVersion,proto3" json:"agent_version,omitempty"`
+	CurrentOperation *Operation                `protobuf:"bytes,6,opt,name=current_operation,json=currentOperation,proto3" json:"current_operation,omitempty"`
+	DevEnvName       string                    `protobuf:"bytes,7,opt,name=dev_env_name,json=devEnvName,proto3" json:"dev_env_name,omitempty"`
+	// Empty if the image was built before images were tagged
+	ImageTag string `protobuf:"bytes,8,opt,name=image_tag,json=imageTag,proto3" json:"image_tag,omitempty"`
+	// The images that may be passed to "RollbackDevEnv" (most recent first)
+	Images []*DevEnvImage `protobuf:"bytes,9,rep,name=images,proto3" json:"images,omitempty"`
+}
+
+func (x *GetDevEnvStatusReply) Reset() {
+	*x = GetDevEnvStatusReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agent_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetDevEnvStatusReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDevEnvStatusReply) ProtoMessage() {}
+
+func (x *GetDevEnvStatusReply) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDevEnvStatusReply.ProtoReflect.Descriptor instead.
+func (*GetDevEnvStatusReply) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *GetDevEnvStatusReply) GetContainerState() string {
+	if x != nil {
+		return x.ContainerState
+	}
+	return ""
+}
+
+func (x *GetDevEnvStatusReply) GetImageId() string {
+	if x != nil {
+		return x.ImageId
+	}
+	return ""
+}
+
+func (x *GetDevEnvStatusReply) GetImageBuiltAt() string {
+	if x != nil {
+		return x.ImageBuiltAt
+	}
+	return ""
+}
+
+func (x *GetDevEnvStatusReply) GetRepositories() []*DevEnvRepositoryStatus {
+	if x != nil {
+		return x.Repositories
+	}
+	return nil
+}
+
+func (x *GetDevEnvStatusReply) GetAgentVersion() string {
+	if x != nil {
+		return x.AgentVersion
+	}
+	return ""
+}
+
+func (x *GetDevEnvStatusReply) GetCurrentOperation() *Operation {
+	if x != nil {
+		return x.CurrentOperation
+	}
+	return nil
+}
+
+func (x *GetDevEnvStatusReply) GetDevEnvName() string {
+	if x != nil {
+		return x.DevEnvName
+	}
+	return ""
+}
+
+func (x *GetDevEnvStatusReply) GetImageTag() string {
+	if x != nil {
+		return x.ImageTag
+	}
+	return ""
+}
+
+func (x *GetDevEnvStatusReply) GetImages() []*DevEnvImage {
+	if x != nil {
+		return x.Images
+	}
+	return nil
+}
+
+// The image of a successful build of a development environment.
+// Tagged "<dev env repo commit>-<image ID>" (eg: "3f2a1bc-9e8d7c6b5a4f").
+type DevEnvImage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Tag                 string `protobuf:"bytes,1,opt,name=tag,proto3" json:"tag,omitempty"`
+	ImageId             string `protobuf:"bytes,2,opt,name=image_id,json=imageId,proto3" json:"image_id,omitempty"`
+	BuiltAt             string `protobuf:"bytes,3,opt,name=built_at,json=builtAt,proto3" json:"built_at,omitempty"`
+	DevEnvRepoCommitSha string `protobuf:"bytes,4,opt,name=dev_env_repo_commit_sha,json=devEnvRepoCommitSha,proto3" json:"dev_env_repo_commit_sha,omitempty"`
+}
+
+func (x *DevEnvImage) Reset() {
+	*x = DevEnvImage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agent_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DevEnvImage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DevEnvImage) ProtoMessage() {}
+
+func (x *DevEnvImage) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DevEnvImage.ProtoReflect.Descriptor instead.
+func (*DevEnvImage) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *DevEnvImage) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+func (x *DevEnvImage) GetImageId() string {
+	if x != nil {
+		return x.ImageId
+	}
+	return ""
+}
+
+func (x *DevEnvImage) GetBuiltAt() string {
+	if x != nil {
+		return x.BuiltAt
+	}
+	return ""
+}
+
+func (x *DevEnvImage) GetDevEnvRepoCommitSha() string {
+	if x != nil {
+		return x.DevEnvRepoCommitSha
+	}
+	return ""
+}
+
+type ListDevEnvsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ListDevEnvsRequest) Reset() {
+	*x = ListDevEnvsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agent_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListDevEnvsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDevEnvsRequest) ProtoMessage() {}
+
+func (x *ListDevEnvsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDevEnvsRequest.ProtoReflect.Descriptor instead.
+func (*ListDevEnvsRequest) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{22}
+}
+
+type ListDevEnvsReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DevEnvs []*DevEnvSummary `protobuf:"bytes,1,rep,name=dev_envs,json=devEnvs,proto3" json:"dev_envs,omitempty"`
+}
+
+func (x *ListDevEnvsReply) Reset() {
+	*x = ListDevEnvsReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agent_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListDevEnvsReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDevEnvsReply) ProtoMessage() {}
+
+func (x *ListDevEnvsReply) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDevEnvsReply.ProtoReflect.Descriptor instead.
+func (*ListDevEnvsReply) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ListDevEnvsReply) GetDevEnvs() []*DevEnvSummary {
+	if x != nil {
+		return x.DevEnvs
+	}
+	return nil
+}
+
+type DevEnvSummary struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name             string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	ContainerState   string `protobuf:"bytes,2,opt,name=container_state,json=containerState,proto3" json:"container_state,omitempty"`
+	ImageId          string `protobuf:"bytes,3,opt,name=image_id,json=imageId,proto3" json:"image_id,omitempty"`
+	WorkspaceDirPath string `protobuf:"bytes,4,opt,name=workspace_dir_path,json=workspaceDirPath,proto3" json:"workspace_dir_path,omitempty"`
+}
+
+func (x *DevEnvSummary) Reset() {
+	*x = DevEnvSummary{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agent_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DevEnvSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DevEnvSummary) ProtoMessage() {}
+
+func (x *DevEnvSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DevEnvSummary.ProtoReflect.Descriptor instead.
+func (*DevEnvSummary) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *DevEnvSummary) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *DevEnvSummary) GetContainerState() string {
+	if x != nil {
+		return x.ContainerState
+	}
+	return ""
+}
+
+func (x *DevEnvSummary) GetImageId() string {
+	if x != nil {
+		return x.ImageId
+	}
+	return ""
+}
+
+func (x *DevEnvSummary) GetWorkspaceDirPath() string {
+	if x != nil {
+		return x.WorkspaceDirPath
+	}
+	return ""
+}
+
+type DevEnvRepositoryStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Owner        string                        `protobuf:"bytes,1,opt,name=owner,proto3" json:"owner,omitempty"`
+	Name         string                        `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	RootDirPath  string                        `protobuf:"bytes,3,opt,name=root_dir_path,json=rootDirPath,proto3" json:"root_dir_path,omitempty"`
+	IsDevEnvRepo bool                          `protobuf:"varint,4,opt,name=is_dev_env_repo,json=isDevEnvRepo,proto3" json:"is_dev_env_repo,omitempty"`
+	Hooks        []*DevEnvRepositoryHookStatus `protobuf:"bytes,5,rep,name=hooks,proto3" json:"hooks,omitempty"`
+	Ref          string                        `protobuf:"bytes,6,opt,name=ref,proto3" json:"ref,omitempty"`
+	CommitSha    string                        `protobuf:"bytes,7,opt,name=commit_sha,json=commitSha,proto3" json:"commit_sha,omitempty"`
+}
+
+func (x *DevEnvRepositoryStatus) Reset() {
+	*x = DevEnvRepositoryStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agent_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DevEnvRepositoryStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DevEnvRepositoryStatus) ProtoMessage() {}
+
+func (x *DevEnvRepositoryStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DevEnvRepositoryStatus.ProtoReflect.Descriptor instead.
+func (*DevEnvRepositoryStatus) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *DevEnvRepositoryStatus) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+func (x *DevEnvRepositoryStatus) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *DevEnvRepositoryStatus) GetRootDirPath() string {
+	if x != nil {
+		return x.RootDirPath
+	}
+	return ""
+}
+
+func (x *DevEnvRepositoryStatus) GetIsDevEnvRepo() bool {
+	if x != nil {
+		return x.IsDevEnvRepo
+	}
+	return false
+}
+
+func (x *DevEnvRepositoryStatus) GetHooks() []*DevEnvRepositoryHookStatus {
+	if x != nil {
+		return x.Hooks
+	}
+	return nil
+}
+
+func (x *DevEnvRepositoryStatus) GetRef() string {
+	if x != nil {
+		return x.Ref
+	}
+	return ""
+}
+
+func (x *DevEnvRepositoryStatus) GetCommitSha() string {
+	if x != nil {
+		return x.CommitSha
+	}
+	return ""
+}
+
+type DevEnvRepositoryHookStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ScriptFilePath string `protobuf:"bytes,1,opt,name=script_file_path,json=scriptFilePath,proto3" json:"script_file_path,omitempty"`
+	LastExitCode   *int32 `protobuf:"varint,2,opt,name=last_exit_code,json=lastExitCode,proto3,oneof" json:"last_exit_code,omitempty"`
+	// "init", "start", "attach" or "stop"
+	Type           string `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	TimeoutSeconds int64  `protobuf:"varint,4,opt,name=timeout_seconds,json=timeoutSeconds,proto3" json:"timeout_seconds,omitempty"`
+	// Not set if the hook was never run
+	LastRun *HookRunResult `protobuf:"bytes,5,opt,name=last_run,json=lastRun,proto3" json:"last_run,omitempty"`
+}
+
+func (x *DevEnvRepositoryHookStatus) Reset() {
+	*x = DevEnvRepositoryHookStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agent_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DevEnvRepositoryHookStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DevEnvRepositoryHookStatus) ProtoMessage() {}
+
+func (x *DevEnvRepositoryHookStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DevEnvRepositoryHookStatus.ProtoReflect.Descriptor instead.
+func (*DevEnvRepositoryHookStatus) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *DevEnvRepositoryHookStatus) GetScriptFilePath() string {
+	if x != nil {
+		return x.ScriptFilePath
+	}
+	return ""
+}
+
+func (x *DevEnvRepositoryHookStatus) GetLastExitCode() int32 {
+	if x != nil && x.LastExitCode != nil {
+		return *x.LastExitCode
+	}
+	return 0
+}
+
+func (x *DevEnvRepositoryHookStatus) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *DevEnvRepositoryHookStatus) GetTimeoutSeconds() int64 {
+	if x != nil {
+		return x.TimeoutSeconds
+	}
+	return 0
+}
+
+func (x *DevEnvRepositoryHookStatus) GetLastRun() *HookRunResult {
+	if x != nil {
+		return x.LastRun
+	}
+	return nil
+}
+
+type HookRunResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ExitCode int32 `protobuf:"varint,1,opt,name=exit_code,json=exitCode,proto3" json:"exit_code,omitempty"`
+	TimedOut bool  `protobuf:"varint,2,opt,name=timed_out,json=timedOut,proto3" json:"timed_out,omitempty"`
+	// RFC 3339
+	StartedAt  string `protobuf:"bytes,3,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	DurationMs int64  `protobuf:"varint,4,opt,name=duration_ms,json=durationMs,proto3" json:"duration_ms,omitempty"`
+	// The last 4KB of the output
+	OutputTail string `protobuf:"bytes,5,opt,name=output_tail,json=outputTail,proto3" json:"output_tail,omitempty"`
+}
+
+func (x *HookRunResult) Reset() {
+	*x = HookRunResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agent_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *HookRunResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HookRunResult) ProtoMessage() {}
+
+func (x *HookRunResult) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HookRunResult.ProtoReflect.Descriptor instead.
+func (*HookRunResult) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *HookRunResult) GetExitCode() int32 {
+	if x != nil {
+		return x.ExitCode
+	}
+	return 0
+}
+
+func (x *HookRunResult) GetTimedOut() bool {
+	if x != nil {
+		return x.TimedOut
+	}
+	return false
+}
+
+func (x *HookRunResult) GetStartedAt() string {
+	if x != nil {
+		return x.StartedAt
+	}
+	return ""
+}
+
+func (x *HookRunResult) GetDurationMs() int64 {
+	if x != nil {
+		return x.DurationMs
+	}
+	return 0
+}
+
+func (x *HookRunResult) GetOutputTail() string {
+	if x != nil {
+		return x.OutputTail
+	}
+	return ""
+}
+
+type WatchOperationRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	OperationId string `protobuf:"bytes,1,opt,name=operation_id,json=operationId,proto3" json:"operation_id,omitempty"`
+}
+
+func (x *WatchOperationRequest) Reset() {
+	*x = WatchOperationRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agent_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WatchOperationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchOperationRequest) ProtoMessage() {}
+
+func (x *WatchOperationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchOperationRequest.ProtoReflect.Descriptor instead.
+func (*WatchOperationRequest) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *WatchOperationRequest) GetOperationId() string {
+	if x != nil {
+		return x.OperationId
+	}
+	return ""
+}
+
+type WatchOperationReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Reply:
+	//	*WatchOperationReply_InitInstanceReply
+	//	*WatchOperationReply_BuildAndStartDevEnvReply
+	//	*WatchOperationReply_StopDevEnvReply
+	//	*WatchOperationReply_RollbackDevEnvReply
+	Reply isWatchOperationReply_Reply `protobuf_oneof:"reply"`
+}
+
+func (x *WatchOperationReply) Reset() {
+	*x = WatchOperationReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agent_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WatchOperationReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchOperationReply) ProtoMessage() {}
+
+func (x *WatchOperationReply) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchOperationReply.ProtoReflect.Descriptor instead.
+func (*WatchOperationReply) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{29}
+}
+
+func (m *WatchOperationReply) GetReply() isWatchOperationReply_Reply {
+	if m != nil {
+		return m.Reply
+	}
+	return nil
+}
+
+func (x *WatchOperationReply) GetInitInstanceReply() *InitInstanceReply {
+	if x, ok := x.GetReply().(*WatchOperationReply_InitInstanceReply); ok {
+		return x.InitInstanceReply
+	}
+	return nil
+}
+
+func (x *WatchOperationReply) GetBuildAndStartDevEnvReply() *BuildAndStartDevEnvReply {
+	if x, ok := x.GetReply().(*WatchOperationReply_BuildAndStartDevEnvReply); ok {
+		return x.BuildAndStartDevEnvReply
+	}
+	return nil
+}
+
+func (x *WatchOperationReply) GetStopDevEnvReply() *StopDevEnvReply {
+	if x, ok := x.GetReply().(*WatchOperationReply_StopDevEnvReply); ok {
+		return x.StopDevEnvReply
+	}
+	return nil
+}
+
+func (x *WatchOperationReply) GetRollbackDevEnvReply() *RollbackDevEnvReply {
+	if x, ok := x.GetReply().(*WatchOperationReply_RollbackDevEnvReply); ok {
+		return x.RollbackDevEnvReply
+	}
+	return nil
+}
+
+type isWatchOperationReply_Reply interface {
+	isWatchOperationReply_Reply()
+}
+
+type WatchOperationReply_InitInstanceReply struct {
+	InitInstanceReply *InitInstanceReply `protobuf:"bytes,1,opt,name=init_instance_reply,json=initInstanceReply,proto3,oneof"`
+}
+
+type WatchOperationReply_BuildAndStartDevEnvReply struct {
+	BuildAndStartDevEnvReply *BuildAndStartDevEnvReply `protobuf:"bytes,2,opt,name=build_and_start_dev_env_reply,json=buildAndStartDevEnvReply,proto3,oneof"`
+}
+
+type WatchOperationReply_StopDevEnvReply struct {
+	StopDevEnvReply *StopDevEnvReply `protobuf:"bytes,3,opt,name=stop_dev_env_reply,json=stopDevEnvReply,proto3,oneof"`
+}
+
+type WatchOperationReply_RollbackDevEnvReply struct {
+	RollbackDevEnvReply *RollbackDevEnvReply `protobuf:"bytes,4,opt,name=rollback_dev_env_reply,json=rollbackDevEnvReply,proto3,oneof"`
+}
+
+func (*WatchOperationReply_InitInstanceReply) isWatchOperationReply_Reply() {}
+
+func (*WatchOperationReply_BuildAndStartDevEnvReply) isWatchOperationReply_Reply() {}
+
+func (*WatchOperationReply_StopDevEnvReply) isWatchOperationReply_Reply() {}
+
+func (*WatchOperationReply_RollbackDevEnvReply) isWatchOperationReply_Reply() {}
+
+type SnapshotWorkspaceRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	OnConflict           OperationConflictPolicy                `protobuf:"varint,1,opt,name=on_conflict,json=onConflict,proto3,enum=agent.OperationConflictPolicy" json:"on_conflict,omitempty"`
+	RepositoriesExcludes []*SnapshotWorkspaceRepositoryExcludes `protobuf:"bytes,2,rep,name=repositories_excludes,json=repositoriesExcludes,proto3" json:"repositories_excludes,omitempty"`
+	DevEnvName           string                                 `protobuf:"bytes,3,opt,name=dev_env_name,json=devEnvName,proto3" json:"dev_env_name,omitempty"`
+}
+
+func (x *SnapshotWorkspaceRequest) Reset() {
+	*x = SnapshotWorkspaceRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agent_proto_msgTypes[30]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SnapshotWorkspaceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SnapshotWorkspaceRequest) ProtoMessage() {}
+
+func (x *SnapshotWorkspaceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[30]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SnapshotWorkspaceRequest.ProtoReflect.Descriptor instead.
+func (*SnapshotWorkspaceRequest) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *SnapshotWorkspaceRequest) GetOnConflict() OperationConflictPolicy {
+	if x != nil {
+		return x.OnConflict
+	}
+	return OperationConflictPolicy_OPERATION_CONFLICT_POLICY_REJECT
+}
+
+func (x *SnapshotWorkspaceRequest) GetRepositoriesExcludes() []*SnapshotWorkspaceRepositoryExcludes {
+	if x != nil {
+		return x.RepositoriesExcludes
+	}
+	return nil
+}
+
+func (x *SnapshotWorkspaceRequest) GetDevEnvName() string {
+	if x != nil {
+		return x.DevEnvName
+	}
+	return ""
+}
+
+type SnapshotWorkspaceRepositoryExcludes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// "owner/name"
+	Repository string `protobuf:"bytes,1,opt,name=repository,proto3" json:"repository,omitempty"`
+	// Matched against the paths relative to the repository root dir using
+	// the ".dockerignore" syntax (eg: "**/*.log", "build/*.o", "!build/keep.o").
+	// Patterns without "/" match at any depth (eg: "node_modules")
+	Patterns []string `protobuf:"bytes,2,rep,name=patterns,proto3" json:"patterns,omitempty"`
+}
+
+func (x *SnapshotWorkspaceRepositoryExcludes) Reset() {
+	*x = SnapshotWorkspaceRepositoryExcludes{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agent_proto_msgTypes[31]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SnapshotWorkspaceRepositoryExcludes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SnapshotWorkspaceRepositoryExcludes) ProtoMessage() {}
+
+func (x *SnapshotWorkspaceRepositoryExcludes) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[31]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SnapshotWorkspaceRepositoryExcludes.ProtoReflect.Descriptor instead.
+func (*SnapshotWorkspaceRepositoryExcludes) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *SnapshotWorkspaceRepositoryExcludes) GetRepository() string {
+	if x != nil {
+		return x.Repository
+	}
+	return ""
+}
+
+func (x *SnapshotWorkspaceRepositoryExcludes) GetPatterns() []string {
+	if x != nil {
+		return x.Patterns
+	}
+	return nil
+}
+
+type SnapshotWorkspaceReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Chunk []byte `protobuf:"bytes,1,opt,name=chunk,proto3" json:"chunk,omitempty"`
+	// SHA-256 of the whole archive. Set in the last reply.
+	Checksum string `protobuf:"bytes,2,opt,name=checksum,proto3" json:"checksum,omitempty"`
+}
+
+func (x *SnapshotWorkspaceReply) Reset() {
+	*x = SnapshotWorkspaceReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agent_proto_msgTypes[32]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SnapshotWorkspaceReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SnapshotWorkspaceReply) ProtoMessage() {}
+
+func (x *SnapshotWorkspaceReply) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[32]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SnapshotWorkspaceReply.ProtoReflect.Descriptor instead.
+func (*SnapshotWorkspaceReply) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *SnapshotWorkspaceReply) GetChunk() []byte {
+	if x != nil {
+		return x.Chunk
+	}
+	return nil
+}
+
+func (x *SnapshotWorkspaceReply) GetChecksum() string {
+	if x != nil {
+		return x.Checksum
+	}
+	return ""
+}
+
+type RestoreWorkspaceRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// "on_conflict", "checksum" and "dev_env_name"
+	// are read from the first request
+	OnConflict OperationConflictPolicy `protobuf:"varint,1,opt,name=on_conflict,json=onConflict,proto3,enum=agent.OperationConflictPolicy" json:"on_conflict,omitempty"`
+	Checksum   string                  `protobuf:"bytes,2,opt,name=checksum,proto3" json:"checksum,omitempty"`
+	Chunk      []byte                  `protobuf:"bytes,3,opt,name=chunk,proto3" json:"chunk,omitempty"`
+	DevEnvName string                  `protobuf:"bytes,4,opt,name=dev_env_name,json=devEnvName,proto3" json:"dev_env_name,omitempty"`
+}
+
+func (x *RestoreWorkspaceRequest) Reset() {
+	*x = RestoreWorkspaceRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agent_proto_msgTypes[33]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RestoreWorkspaceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreWorkspaceRequest) ProtoMessage() {}
+
+func (x *RestoreWorkspaceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[33]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreWorkspaceRequest.ProtoReflect.Descriptor instead.
+func (*RestoreWorkspaceRequest) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *RestoreWorkspaceRequest) GetOnConflict() OperationConflictPolicy {
+	if x != nil {
+		return x.OnConflict
+	}
+	return OperationConflictPolicy_OPERATION_CONFLICT_POLICY_REJECT
+}
+
+func (x *RestoreWorkspaceRequest) GetChecksum() string {
+	if x != nil {
+		return x.Checksum
+	}
+	return ""
+}
+
+func (x *RestoreWorkspaceRequest) GetChunk() []byte {
+	if x != nil {
+		return x.Chunk
+	}
+	return nil
+}
+
+func (x *RestoreWorkspaceRequest) GetDevEnvName() string {
+	if x != nil {
+		return x.DevEnvName
+	}
+	return ""
+}
+
+type RestoreWorkspaceReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RestoredBytes int64 `protobuf:"varint,1,opt,name=restored_bytes,json=restoredBytes,proto3" json:"restored_bytes,omitempty"`
+}
+
+func (x *RestoreWorkspaceReply) Reset() {
+	*x = RestoreWorkspaceReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agent_proto_msgTypes[34]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RestoreWorkspaceReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreWorkspaceReply) ProtoMessage() {}
+
+func (x *RestoreWorkspaceReply) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[34]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreWorkspaceReply.ProtoReflect.Descriptor instead.
+func (*RestoreWorkspaceReply) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *RestoreWorkspaceReply) GetRestoredBytes() int64 {
+	if x != nil {
+		return x.RestoredBytes
+	}
+	return 0
+}
+
+type RollbackDevEnvRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DevEnvName string `protobuf:"bytes,1,opt,name=dev_env_name,json=devEnvName,proto3" json:"dev_env_name,omitempty"`
+	// Tag of the image to roll back to (see "GetDevEnvStatusReply.images").
+	// Defaults to the image built before the current one.
+	ImageTag   string                  `protobuf:"bytes,2,opt,name=image_tag,json=imageTag,proto3" json:"image_tag,omitempty"`
+	OnConflict OperationConflictPolicy `protobuf:"varint,3,opt,name=on_conflict,json=onConflict,proto3,enum=agent.OperationConflictPolicy" json:"on_conflict,omitempty"`
+	// The operation is canceled when its client cancels the call or
+	// disconnects unless this is set (its replies may then be
+	// followed using "WatchOperation" after a disconnection)
+	ContinueOnDisconnect bool `protobuf:"varint,4,opt,name=continue_on_disconnect,json=continueOnDisconnect,proto3" json:"continue_on_disconnect,omitempty"`
+}
+
+func (x *RollbackDevEnvRequest) Reset() {
+	*x = RollbackDevEnvRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agent_proto_msgTypes[35]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RollbackDevEnvRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RollbackDevEnvRequest) ProtoMessage() {}
+
+func (x *RollbackDevEnvRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[35]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RollbackDevEnvRequest.ProtoReflect.Descriptor instead.
+func (*RollbackDevEnvRequest) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *RollbackDevEnvRequest) GetDevEnvName() string {
+	if x != nil {
+		return x.DevEnvName
+	}
+	return ""
+}
+
+func (x *RollbackDevEnvRequest) GetImageTag() string {
+	if x != nil {
+		return x.ImageTag
+	}
+	return ""
+}
+
+func (x *RollbackDevEnvRequest) GetOnConflict() OperationConflictPolicy {
+	if x != nil {
+		return x.OnConflict
+	}
+	return OperationConflictPolicy_OPERATION_CONFLICT_POLICY_REJECT
+}
+
+func (x *RollbackDevEnvRequest) GetContinueOnDisconnect() bool {
+	if x != nil {
+		return x.ContinueOnDisconnect
+	}
+	return false
+}
+
+type RollbackDevEnvReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	LogLineHeader string `protobuf:"bytes,1,opt,name=log_line_header,json=logLineHeader,proto3" json:"log_line_header,omitempty"`
+	LogLine       string `protobuf:"bytes,2,opt,name=log_line,json=logLine,proto3" json:"log_line,omitempty"`
+	// Sent once the container is recreated from the image
+	Image *DevEnvImage `protobuf:"bytes,3,opt,name=image,proto3" json:"image,omitempty"`
+}
+
+func (x *RollbackDevEnvReply) Reset() {
+	*x = RollbackDevEnvReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agent_proto_msgTypes[36]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RollbackDevEnvReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RollbackDevEnvReply) ProtoMessage() {}
+
+func (x *RollbackDevEnvReply) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[36]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
 		}
-		return ms
-	}
-	return mi.MessageOf(x)
-}
-
-// Deprecated: Use BuildAndStartDevEnvReply.ProtoReflect.Descriptor instead.
-func (*BuildAndStartDevEnvReply) Descriptor() ([]byte, []int) {
-	return file_agent_proto_rawDescGZIP(), []int{3}
-}
-
-func (x *BuildAndStartDevEnvReply) GetLogLineHeader() string {
-	if x != nil {
-		return x.LogLineHeader
-	}
-	return ""
-}
-
-func (x *BuildAndStartDevEnvReply) GetLogLine() string {
-	if x != nil {
-		return x.LogLine
-	}
-	return ""
-}
-
-var File_agent_proto protoreflect.FileDescriptor
-
-var file_agent_proto_rawDesc = []byte{
-	0x0a, 0x0b, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05, 0x61,
-	0x67, 0x65, 0x6e, 0x74, 0x22, 0x92, 0x01, 0x0a, 0x13, 0x49, 0x6e, 0x69, 0x74, 0x49, 0x6e, 0x73,
-	0x74, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x29, 0x0a, 0x11,
-	0x64, 0x65, 0x76, 0x5f, 0x65, 0x6e, 0x76, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x5f, 0x73, 0x6c, 0x75,
-	0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x64, 0x65, 0x76, 0x45, 0x6e, 0x76, 0x4e,
-	0x61, 0x6d, 0x65, 0x53, 0x6c, 0x75, 0x67, 0x12, 0x2a, 0x0a, 0x11, 0x67, 0x69, 0x74, 0x68, 0x75,
-	0x62, 0x5f, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x0f, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x55, 0x73, 0x65, 0x72, 0x45, 0x6d,
-	0x61, 0x69, 0x6c, 0x12, 0x24, 0x0a, 0x0e, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x66, 0x75, 0x6c, 0x6c,
-	0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x75, 0x73, 0x65,
-	0x72, 0x46, 0x75, 0x6c, 0x6c, 0x4e, 0x61, 0x6d, 0x65, 0x22, 0xa8, 0x02, 0x0a, 0x11, 0x49, 0x6e,
-	0x69, 0x74, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12,
-	0x26, 0x0a, 0x0f, 0x6c, 0x6f, 0x67, 0x5f, 0x6c, 0x69, 0x6e, 0x65, 0x5f, 0x68, 0x65, 0x61, 0x64,
-	0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x6c, 0x6f, 0x67, 0x4c, 0x69, 0x6e,
-	0x65, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x19, 0x0a, 0x08, 0x6c, 0x6f, 0x67, 0x5f, 0x6c,
-	0x69, 0x6e, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6c, 0x6f, 0x67, 0x4c, 0x69,
-	0x6e, 0x65, 0x12, 0x45, 0x0a, 0x1d, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x5f, 0x73, 0x73, 0x68,
-	0x5f, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x63, 0x6f, 0x6e, 0x74,
-	0x65, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x19, 0x67, 0x69, 0x74,
-	0x68, 0x75, 0x62, 0x53, 0x73, 0x68, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x4b, 0x65, 0x79, 0x43,
-	0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x88, 0x01, 0x01, 0x12, 0x45, 0x0a, 0x1d, 0x67, 0x69, 0x74,
-	0x68, 0x75, 0x62, 0x5f, 0x67, 0x70, 0x67, 0x5f, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x5f, 0x6b,
-	0x65, 0x79, 0x5f, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
-	0x48, 0x01, 0x52, 0x19, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x47, 0x70, 0x67, 0x50, 0x75, 0x62,
-	0x6c, 0x69, 0x63, 0x4b, 0x65, 0x79, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x88, 0x01, 0x01,
-	0x42, 0x20, 0x0a, 0x1e, 0x5f, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x5f, 0x73, 0x73, 0x68, 0x5f,
-	0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x63, 0x6f, 0x6e, 0x74, 0x65,
-	0x6e, 0x74, 0x42, 0x20, 0x0a, 0x1e, 0x5f, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x5f, 0x67, 0x70,
-	0x67, 0x5f, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x63, 0x6f, 0x6e,
-	0x74, 0x65, 0x6e, 0x74, 0x22, 0xdc, 0x01, 0x0a, 0x1a, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x41, 0x6e,
-	0x64, 0x53, 0x74, 0x61, 0x72, 0x74, 0x44, 0x65, 0x76, 0x45, 0x6e, 0x76, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x12, 0x2b, 0x0a, 0x12, 0x64, 0x65, 0x76, 0x5f, 0x65, 0x6e, 0x76, 0x5f, 0x72,
-	0x65, 0x70, 0x6f, 0x5f, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x0f, 0x64, 0x65, 0x76, 0x45, 0x6e, 0x76, 0x52, 0x65, 0x70, 0x6f, 0x4f, 0x77, 0x6e, 0x65, 0x72,
-	0x12, 0x29, 0x0a, 0x11, 0x64, 0x65, 0x76, 0x5f, 0x65, 0x6e, 0x76, 0x5f, 0x72, 0x65, 0x70, 0x6f,
-	0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x64, 0x65, 0x76,
-	0x45, 0x6e, 0x76, 0x52, 0x65, 0x70, 0x6f, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x33, 0x0a, 0x16, 0x75,
-	0x73, 0x65, 0x72, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x5f, 0x72, 0x65, 0x70, 0x6f, 0x5f,
-	0x6f, 0x77, 0x6e, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x13, 0x75, 0x73, 0x65,
-	0x72, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x70, 0x6f, 0x4f, 0x77, 0x6e, 0x65, 0x72,
-	0x12, 0x31, 0x0a, 0x15, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x5f,
-	0x72, 0x65, 0x70, 0x6f, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x12, 0x75, 0x73, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x70, 0x6f, 0x4e,
-	0x61, 0x6d, 0x65, 0x22, 0x5d, 0x0a, 0x18, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x41, 0x6e, 0x64, 0x53,
-	0x74, 0x61, 0x72, 0x74, 0x44, 0x65, 0x76, 0x45, 0x6e, 0x76, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12,
-	0x26, 0x0a, 0x0f, 0x6c, 0x6f, 0x67, 0x5f, 0x6c, 0x69, 0x6e, 0x65, 0x5f, 0x68, 0x65, 0x61, 0x64,
-	0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x6c, 0x6f, 0x67, 0x4c, 0x69, 0x6e,
-	0x65, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x19, 0x0a, 0x08, 0x6c, 0x6f, 0x67, 0x5f, 0x6c,
-	0x69, 0x6e, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6c, 0x6f, 0x67, 0x4c, 0x69,
-	0x6e, 0x65, 0x32, 0xb0, 0x01, 0x0a, 0x05, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x12, 0x48, 0x0a, 0x0c,
-	0x49, 0x6e, 0x69, 0x74, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x1a, 0x2e, 0x61,
-	0x67, 0x65, 0x6e, 0x74, 0x2e, 0x49, 0x6e, 0x69, 0x74, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63,
-	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74,
-	0x2e, 0x49, 0x6e, 0x69, 0x74, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x70,
-	0x6c, 0x79, 0x22, 0x00, 0x30, 0x01, 0x12, 0x5d, 0x0a, 0x13, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x41,
-	0x6e, 0x64, 0x53, 0x74, 0x61, 0x72, 0x74, 0x44, 0x65, 0x76, 0x45, 0x6e, 0x76, 0x12, 0x21, 0x2e,
-	0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x41, 0x6e, 0x64, 0x53, 0x74,
-	0x61, 0x72, 0x74, 0x44, 0x65, 0x76, 0x45, 0x6e, 0x76, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x1a, 0x1f, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x41, 0x6e,
-	0x64, 0x53, 0x74, 0x61, 0x72, 0x74, 0x44, 0x65, 0x76, 0x45, 0x6e, 0x76, 0x52, 0x65, 0x70, 0x6c,
-	0x79, 0x22, 0x00, 0x30, 0x01, 0x42, 0x22, 0x5a, 0x20, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e,
-	0x63, 0x6f, 0x6d, 0x2f, 0x72, 0x65, 0x63, 0x6f, 0x64, 0x65, 0x2d, 0x73, 0x68, 0x2f, 0x61, 0x67,
-	0x65, 0x6e, 0x74, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x33,
-}
-
-var (
-	file_agent_proto_rawDescOnce sync.Once
-	file_agent_proto_rawDescData = file_agent_proto_rawDesc
-)
-
-func file_agent_proto_rawDescGZIP() []byte {
-	file_agent_proto_rawDescOnce.Do(func() {
-		file_agent_proto_rawDescData = protoimpl.X.CompressGZIP(file_agent_proto_rawDescData)
-	})
-	return file_agent_proto_rawDescData
-}
-
-var file_agent_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
-var file_agent_proto_goTypes = []interface{}{
-	(*InitInstanceRequest)(nil),        // 0: agent.InitInstanceRequest
-	(*InitInstanceReply)(nil),          // 1: agent.InitInstanceReply
-	(*BuildAndStartDevEnvRequest)(nil), // 2: agent.BuildAndStartDevEnvRequest
-	(*BuildAndStartDevEnvReply)(nil),   // 3: agent.BuildAndStartDevEnvReply
-}
-var file_agent_proto_depIdxs = []int32{
-	0, // 0: agent.Agent.InitInstance:input_type -> agent.InitInstanceRequest
-	2, // 1: agent.Agent.BuildAndStartDevEnv:input_type -> agent.BuildAndStartDevEnvRequest
-	1, // 2: agent.Agent.InitInstance:output_type -> agent.InitInstanceReply
-	3, // 3: agent.Agent.BuildAndStartDevEnv:output_type -> agent.BuildAndStartDevEnvReply
-	2, // [2:4] is the sub-list for method output_type
-	0, // [0:2] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
-}
-
-func init() { file_agent_proto_init() }
-func file_agent_proto_init() {
-	if File_agent_proto != nil {
-		return
-	}
-	if !protoimpl.UnsafeEnabled {
-		file_agent_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*InitInstanceRequest); i {
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RollbackDevEnvReply.ProtoReflect.Descriptor instead.
+func (*RollbackDevEnvReply) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *RollbackDevEnvReply) GetLogLineHeader() string {
+	if x != nil {
+		return x.LogLineHeader
+	}
+	return ""
+}
+
+func (x *RollbackDevEnvReply) GetLogLine() string {
+	if x != nil {
+		return x.LogLine
+	}
+	return ""
+}
+
+func (x *RollbackDevEnvReply) GetImage() *DevEnvImage {
+	if x != nil {
+		return x.Image
+	}
+	return nil
+}
+
+var File_agent_proto protoreflect.FileDescriptor
+
+var file_agent_proto_rawDesc = []byte{
+	0x0a, 0x0b, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05, 0x61,
+	0x67, 0x65, 0x6e, 0x74, 0x22, 0x70, 0x0a, 0x09, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69,
+	0x64, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x72, 0x74, 0x65, 0x64,
+	0x5f, 0x61, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x74, 0x61, 0x72, 0x74,
+	0x65, 0x64, 0x41, 0x74, 0x12, 0x20, 0x0a, 0x0c, 0x64, 0x65, 0x76, 0x5f, 0x65, 0x6e, 0x76, 0x5f,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x64, 0x65, 0x76, 0x45,
+	0x6e, 0x76, 0x4e, 0x61, 0x6d, 0x65, 0x22, 0x89, 0x02, 0x0a, 0x13, 0x49, 0x6e, 0x69, 0x74, 0x49,
+	0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x29,
+	0x0a, 0x11, 0x64, 0x65, 0x76, 0x5f, 0x65, 0x6e, 0x76, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x5f, 0x73,
+	0x6c, 0x75, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x64, 0x65, 0x76, 0x45, 0x6e,
+	0x76, 0x4e, 0x61, 0x6d, 0x65, 0x53, 0x6c, 0x75, 0x67, 0x12, 0x2a, 0x0a, 0x11, 0x67, 0x69, 0x74,
+	0x68, 0x75, 0x62, 0x5f, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x55, 0x73, 0x65, 0x72,
+	0x45, 0x6d, 0x61, 0x69, 0x6c, 0x12, 0x24, 0x0a, 0x0e, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x66, 0x75,
+	0x6c, 0x6c, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x75,
+	0x73, 0x65, 0x72, 0x46, 0x75, 0x6c, 0x6c, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x3f, 0x0a, 0x0b, 0x6f,
+	0x6e, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x6c, 0x69, 0x63, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x1e, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x66, 0x6c, 0x69, 0x63, 0x74, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79,
+	0x52, 0x0a, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x66, 0x6c, 0x69, 0x63, 0x74, 0x12, 0x34, 0x0a, 0x16,
+	0x63, 0x6f, 0x6e, 0x74, 0x69, 0x6e, 0x75, 0x65, 0x5f, 0x6f, 0x6e, 0x5f, 0x64, 0x69, 0x73, 0x63,
+	0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x14, 0x63, 0x6f,
+	0x6e, 0x74, 0x69, 0x6e, 0x75, 0x65, 0x4f, 0x6e, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x6e, 0x6e, 0x65,
+	0x63, 0x74, 0x22, 0xa8, 0x02, 0x0a, 0x11, 0x49, 0x6e, 0x69, 0x74, 0x49, 0x6e, 0x73, 0x74, 0x61,
+	0x6e, 0x63, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x26, 0x0a, 0x0f, 0x6c, 0x6f, 0x67, 0x5f,
+	0x6c, 0x69, 0x6e, 0x65, 0x5f, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0d, 0x6c, 0x6f, 0x67, 0x4c, 0x69, 0x6e, 0x65, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72,
+	0x12, 0x19, 0x0a, 0x08, 0x6c, 0x6f, 0x67, 0x5f, 0x6c, 0x69, 0x6e, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x07, 0x6c, 0x6f, 0x67, 0x4c, 0x69, 0x6e, 0x65, 0x12, 0x45, 0x0a, 0x1d, 0x67,
+	0x69, 0x74, 0x68, 0x75, 0x62, 0x5f, 0x73, 0x73, 0x68, 0x5f, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63,
+	0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x48, 0x00, 0x52, 0x19, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x53, 0x73, 0x68, 0x50,
+	0x75, 0x62, 0x6c, 0x69, 0x63, 0x4b, 0x65, 0x79, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x88,
+	0x01, 0x01, 0x12, 0x45, 0x0a, 0x1d, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x5f, 0x67, 0x70, 0x67,
+	0x5f, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x63, 0x6f, 0x6e, 0x74,
+	0x65, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x19, 0x67, 0x69, 0x74,
+	0x68, 0x75, 0x62, 0x47, 0x70, 0x67, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x4b, 0x65, 0x79, 0x43,
+	0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x88, 0x01, 0x01, 0x42, 0x20, 0x0a, 0x1e, 0x5f, 0x67, 0x69,
+	0x74, 0x68, 0x75, 0x62, 0x5f, 0x73, 0x73, 0x68, 0x5f, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x5f,
+	0x6b, 0x65, 0x79, 0x5f, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x42, 0x20, 0x0a, 0x1e, 0x5f,
+	0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x5f, 0x67, 0x70, 0x67, 0x5f, 0x70, 0x75, 0x62, 0x6c, 0x69,
+	0x63, 0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x22, 0xa9, 0x0b,
+	0x0a, 0x1a, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x41, 0x6e, 0x64, 0x53, 0x74, 0x61, 0x72, 0x74, 0x44,
+	0x65, 0x76, 0x45, 0x6e, 0x76, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2b, 0x0a, 0x12,
+	0x64, 0x65, 0x76, 0x5f, 0x65, 0x6e, 0x76, 0x5f, 0x72, 0x65, 0x70, 0x6f, 0x5f, 0x6f, 0x77, 0x6e,
+	0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x64, 0x65, 0x76, 0x45, 0x6e, 0x76,
+	0x52, 0x65, 0x70, 0x6f, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x12, 0x29, 0x0a, 0x11, 0x64, 0x65, 0x76,
+	0x5f, 0x65, 0x6e, 0x76, 0x5f, 0x72, 0x65, 0x70, 0x6f, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x64, 0x65, 0x76, 0x45, 0x6e, 0x76, 0x52, 0x65, 0x70, 0x6f,
+	0x4e, 0x61, 0x6d, 0x65, 0x12, 0x33, 0x0a, 0x16, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x63, 0x6f, 0x6e,
+	0x66, 0x69, 0x67, 0x5f, 0x72, 0x65, 0x70, 0x6f, 0x5f, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x13, 0x75, 0x73, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x52, 0x65, 0x70, 0x6f, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x12, 0x31, 0x0a, 0x15, 0x75, 0x73, 0x65,
+	0x72, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x5f, 0x72, 0x65, 0x70, 0x6f, 0x5f, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x12, 0x75, 0x73, 0x65, 0x72, 0x43, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x70, 0x6f, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x3f, 0x0a, 0x0b,
+	0x6f, 0x6e, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x6c, 0x69, 0x63, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x1e, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x66, 0x6c, 0x69, 0x63, 0x74, 0x50, 0x6f, 0x6c, 0x69, 0x63,
+	0x79, 0x52, 0x0a, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x66, 0x6c, 0x69, 0x63, 0x74, 0x12, 0x48, 0x0a,
+	0x13, 0x77, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x5f, 0x73, 0x79, 0x6e, 0x63, 0x5f,
+	0x6d, 0x6f, 0x64, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x18, 0x2e, 0x61, 0x67, 0x65,
+	0x6e, 0x74, 0x2e, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x53, 0x79, 0x6e, 0x63,
+	0x4d, 0x6f, 0x64, 0x65, 0x52, 0x11, 0x77, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x53,
+	0x79, 0x6e, 0x63, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x32, 0x0a, 0x15, 0x61, 0x72, 0x63, 0x68, 0x69,
+	0x76, 0x65, 0x5f, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x5f, 0x72, 0x65, 0x70, 0x6f, 0x73,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x13, 0x61, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x52,
+	0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x52, 0x65, 0x70, 0x6f, 0x73, 0x12, 0x20, 0x0a, 0x0c, 0x64,
+	0x65, 0x76, 0x5f, 0x65, 0x6e, 0x76, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0a, 0x64, 0x65, 0x76, 0x45, 0x6e, 0x76, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x38, 0x0a,
+	0x19, 0x64, 0x65, 0x76, 0x5f, 0x65, 0x6e, 0x76, 0x5f, 0x72, 0x65, 0x70, 0x6f, 0x5f, 0x67, 0x69,
+	0x74, 0x5f, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x15, 0x64, 0x65, 0x76, 0x45, 0x6e, 0x76, 0x52, 0x65, 0x70, 0x6f, 0x47, 0x69, 0x74, 0x50,
+	0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x12, 0x40, 0x0a, 0x1d, 0x75, 0x73, 0x65, 0x72, 0x5f,
+	0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x5f, 0x72, 0x65, 0x70, 0x6f, 0x5f, 0x67, 0x69, 0x74, 0x5f,
+	0x70, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x19,
+	0x75, 0x73, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x70, 0x6f, 0x47, 0x69,
+	0x74, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x12, 0x27, 0x0a, 0x10, 0x64, 0x65, 0x76,
+	0x5f, 0x65, 0x6e, 0x76, 0x5f, 0x72, 0x65, 0x70, 0x6f, 0x5f, 0x72, 0x65, 0x66, 0x18, 0x0b, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0d, 0x64, 0x65, 0x76, 0x45, 0x6e, 0x76, 0x52, 0x65, 0x70, 0x6f, 0x52,
+	0x65, 0x66, 0x12, 0x2f, 0x0a, 0x14, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69,
+	0x67, 0x5f, 0x72, 0x65, 0x70, 0x6f, 0x5f, 0x72, 0x65, 0x66, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x11, 0x75, 0x73, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x70, 0x6f,
+	0x52, 0x65, 0x66, 0x12, 0x47, 0x0a, 0x15, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x5f, 0x63,
+	0x6c, 0x6f, 0x6e, 0x65, 0x5f, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x0d, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x13, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x43, 0x6c, 0x6f, 0x6e, 0x65,
+	0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x13, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74,
+	0x43, 0x6c, 0x6f, 0x6e, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x5b, 0x0a, 0x1a,
+	0x72, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x5f, 0x63, 0x6c, 0x6f,
+	0x6e, 0x65, 0x5f, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x0e, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x1d, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x52, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74,
+	0x6f, 0x72, 0x79, 0x43, 0x6c, 0x6f, 0x6e, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52,
+	0x18, 0x72, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x43, 0x6c, 0x6f,
+	0x6e, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x41, 0x0a, 0x10, 0x77, 0x6f, 0x72,
+	0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x5f, 0x6c, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x18, 0x0f, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x16, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x57, 0x6f, 0x72, 0x6b,
+	0x73, 0x70, 0x61, 0x63, 0x65, 0x4c, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x52, 0x0f, 0x77, 0x6f, 0x72,
+	0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x4c, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x12, 0x35, 0x0a, 0x0c,
+	0x72, 0x65, 0x74, 0x72, 0x79, 0x5f, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x18, 0x10, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x12, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x52, 0x65, 0x74, 0x72, 0x79,
+	0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x52, 0x0b, 0x72, 0x65, 0x74, 0x72, 0x79, 0x50, 0x6f, 0x6c,
+	0x69, 0x63, 0x79, 0x12, 0x2b, 0x0a, 0x11, 0x68, 0x6f, 0x6f, 0x6b, 0x73, 0x5f, 0x70, 0x61, 0x72,
+	0x61, 0x6c, 0x6c, 0x65, 0x6c, 0x69, 0x73, 0x6d, 0x18, 0x11, 0x20, 0x01, 0x28, 0x05, 0x52, 0x10,
+	0x68, 0x6f, 0x6f, 0x6b, 0x73, 0x50, 0x61, 0x72, 0x61, 0x6c, 0x6c, 0x65, 0x6c, 0x69, 0x73, 0x6d,
+	0x12, 0x4a, 0x0a, 0x14, 0x68, 0x6f, 0x6f, 0x6b, 0x73, 0x5f, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72,
+	0x65, 0x5f, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x18, 0x12, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x18,
+	0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x48, 0x6f, 0x6f, 0x6b, 0x46, 0x61, 0x69, 0x6c, 0x75,
+	0x72, 0x65, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x52, 0x12, 0x68, 0x6f, 0x6f, 0x6b, 0x73, 0x46,
+	0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x33, 0x0a, 0x16,
+	0x63, 0x6f, 0x6e, 0x74, 0x69, 0x6e, 0x75, 0x65, 0x5f, 0x6f, 0x6e, 0x5f, 0x68, 0x6f, 0x6f, 0x6b,
+	0x5f, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x13, 0x20, 0x01, 0x28, 0x08, 0x52, 0x13, 0x63, 0x6f,
+	0x6e, 0x74, 0x69, 0x6e, 0x75, 0x65, 0x4f, 0x6e, 0x48, 0x6f, 0x6f, 0x6b, 0x45, 0x72, 0x72, 0x6f,
+	0x72, 0x12, 0x48, 0x0a, 0x13, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x5f, 0x62, 0x75, 0x69, 0x6c, 0x64,
+	0x5f, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x14, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18,
+	0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x42, 0x75, 0x69, 0x6c,
+	0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x11, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x42,
+	0x75, 0x69, 0x6c, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x37, 0x0a, 0x0d, 0x62,
+	0x75, 0x69, 0x6c, 0x64, 0x5f, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x73, 0x18, 0x15, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x12, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x42, 0x75, 0x69, 0x6c, 0x64,
+	0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x52, 0x0c, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x53, 0x65, 0x63,
+	0x72, 0x65, 0x74, 0x73, 0x12, 0x4f, 0x0a, 0x0a, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x5f, 0x61, 0x72,
+	0x67, 0x73, 0x18, 0x16, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x30, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74,
+	0x2e, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x41, 0x6e, 0x64, 0x53, 0x74, 0x61, 0x72, 0x74, 0x44, 0x65,
+	0x76, 0x45, 0x6e, 0x76, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x42, 0x75, 0x69, 0x6c,
+	0x64, 0x41, 0x72, 0x67, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x09, 0x62, 0x75, 0x69, 0x6c,
+	0x64, 0x41, 0x72, 0x67, 0x73, 0x12, 0x34, 0x0a, 0x16, 0x63, 0x6f, 0x6e, 0x74, 0x69, 0x6e, 0x75,
+	0x65, 0x5f, 0x6f, 0x6e, 0x5f, 0x64, 0x69, 0x73, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x18,
+	0x17, 0x20, 0x01, 0x28, 0x08, 0x52, 0x14, 0x63, 0x6f, 0x6e, 0x74, 0x69, 0x6e, 0x75, 0x65, 0x4f,
+	0x6e, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x1a, 0x3c, 0x0a, 0x0e, 0x42,
+	0x75, 0x69, 0x6c, 0x64, 0x41, 0x72, 0x67, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a,
+	0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12,
+	0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x33, 0x0a, 0x0b, 0x42, 0x75, 0x69,
+	0x6c, 0x64, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0x83,
+	0x01, 0x0a, 0x11, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x4f, 0x70, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x75, 0x73, 0x65, 0x5f, 0x62, 0x75, 0x69, 0x6c,
+	0x64, 0x6b, 0x69, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x75, 0x73, 0x65, 0x42,
+	0x75, 0x69, 0x6c, 0x64, 0x6b, 0x69, 0x74, 0x12, 0x2a, 0x0a, 0x11, 0x63, 0x61, 0x63, 0x68, 0x65,
+	0x5f, 0x6b, 0x65, 0x65, 0x70, 0x5f, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x73, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x0f, 0x63, 0x61, 0x63, 0x68, 0x65, 0x4b, 0x65, 0x65, 0x70, 0x42, 0x75, 0x69,
+	0x6c, 0x64, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x6b, 0x65, 0x65, 0x70, 0x5f, 0x69, 0x6d, 0x61, 0x67,
+	0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x6b, 0x65, 0x65, 0x70, 0x49, 0x6d,
+	0x61, 0x67, 0x65, 0x73, 0x22, 0xb4, 0x01, 0x0a, 0x0c, 0x43, 0x6c, 0x6f, 0x6e, 0x65, 0x4f, 0x70,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x64, 0x65, 0x70, 0x74, 0x68, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x64, 0x65, 0x70, 0x74, 0x68, 0x12, 0x16, 0x0a, 0x06, 0x66,
+	0x69, 0x6c, 0x74, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x66, 0x69, 0x6c,
+	0x74, 0x65, 0x72, 0x12, 0x32, 0x0a, 0x15, 0x73, 0x70, 0x61, 0x72, 0x73, 0x65, 0x5f, 0x63, 0x68,
+	0x65, 0x63, 0x6b, 0x6f, 0x75, 0x74, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x73, 0x18, 0x03, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x13, 0x73, 0x70, 0x61, 0x72, 0x73, 0x65, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x6f,
+	0x75, 0x74, 0x50, 0x61, 0x74, 0x68, 0x73, 0x12, 0x27, 0x0a, 0x0f, 0x73, 0x6b, 0x69, 0x70, 0x5f,
+	0x73, 0x75, 0x62, 0x6d, 0x6f, 0x64, 0x75, 0x6c, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x0e, 0x73, 0x6b, 0x69, 0x70, 0x53, 0x75, 0x62, 0x6d, 0x6f, 0x64, 0x75, 0x6c, 0x65, 0x73,
+	0x12, 0x19, 0x0a, 0x08, 0x73, 0x6b, 0x69, 0x70, 0x5f, 0x6c, 0x66, 0x73, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x07, 0x73, 0x6b, 0x69, 0x70, 0x4c, 0x66, 0x73, 0x22, 0x67, 0x0a, 0x16, 0x52,
+	0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x43, 0x6c, 0x6f, 0x6e, 0x65, 0x4f, 0x70,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x72, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74,
+	0x6f, 0x72, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x72, 0x65, 0x70, 0x6f, 0x73,
+	0x69, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x2d, 0x0a, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x43,
+	0x6c, 0x6f, 0x6e, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x07, 0x6f, 0x70, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x22, 0xb7, 0x01, 0x0a, 0x0b, 0x52, 0x65, 0x74, 0x72, 0x79, 0x50, 0x6f,
+	0x6c, 0x69, 0x63, 0x79, 0x12, 0x21, 0x0a, 0x0c, 0x6d, 0x61, 0x78, 0x5f, 0x61, 0x74, 0x74, 0x65,
+	0x6d, 0x70, 0x74, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0b, 0x6d, 0x61, 0x78, 0x41,
+	0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x73, 0x12, 0x2e, 0x0a, 0x13, 0x69, 0x6e, 0x69, 0x74, 0x69,
+	0x61, 0x6c, 0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x5f, 0x6d, 0x73, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x11, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x49, 0x6e, 0x74,
+	0x65, 0x72, 0x76, 0x61, 0x6c, 0x4d, 0x73, 0x12, 0x26, 0x0a, 0x0f, 0x6d, 0x61, 0x78, 0x5f, 0x69,
+	0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x5f, 0x6d, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x0d, 0x6d, 0x61, 0x78, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x4d, 0x73, 0x12,
+	0x2d, 0x0a, 0x13, 0x6d, 0x61, 0x78, 0x5f, 0x65, 0x6c, 0x61, 0x70, 0x73, 0x65, 0x64, 0x5f, 0x74,
+	0x69, 0x6d, 0x65, 0x5f, 0x6d, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x10, 0x6d, 0x61,
+	0x78, 0x45, 0x6c, 0x61, 0x70, 0x73, 0x65, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x4d, 0x73, 0x22, 0xb6,
+	0x04, 0x0a, 0x18, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x41, 0x6e, 0x64, 0x53, 0x74, 0x61, 0x72, 0x74,
+	0x44, 0x65, 0x76, 0x45, 0x6e, 0x76, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x26, 0x0a, 0x0f, 0x6c,
+	0x6f, 0x67, 0x5f, 0x6c, 0x69, 0x6e, 0x65, 0x5f, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x6c, 0x6f, 0x67, 0x4c, 0x69, 0x6e, 0x65, 0x48, 0x65, 0x61,
+	0x64, 0x65, 0x72, 0x12, 0x19, 0x0a, 0x08, 0x6c, 0x6f, 0x67, 0x5f, 0x6c, 0x69, 0x6e, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6c, 0x6f, 0x67, 0x4c, 0x69, 0x6e, 0x65, 0x12, 0x2f,
+	0x0a, 0x0a, 0x73, 0x74, 0x65, 0x70, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x10, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x53, 0x74, 0x65, 0x70, 0x45,
+	0x76, 0x65, 0x6e, 0x74, 0x52, 0x09, 0x73, 0x74, 0x65, 0x70, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12,
+	0x4e, 0x0a, 0x15, 0x64, 0x6f, 0x63, 0x6b, 0x65, 0x72, 0x5f, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x5f,
+	0x70, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
+	0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x44, 0x6f, 0x63, 0x6b, 0x65, 0x72, 0x42, 0x75, 0x69,
+	0x6c, 0x64, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x52, 0x13, 0x64, 0x6f, 0x63, 0x6b,
+	0x65, 0x72, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x12,
+	0x38, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x20, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x41, 0x6e, 0x64,
+	0x53, 0x74, 0x61, 0x72, 0x74, 0x44, 0x65, 0x76, 0x45, 0x6e, 0x76, 0x52, 0x65, 0x73, 0x75, 0x6c,
+	0x74, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x4a, 0x0a, 0x13, 0x72, 0x65, 0x73,
+	0x6f, 0x6c, 0x76, 0x65, 0x64, 0x5f, 0x72, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x52,
+	0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x64, 0x52, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72,
+	0x79, 0x52, 0x12, 0x72, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x64, 0x52, 0x65, 0x70, 0x6f, 0x73,
+	0x69, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x45, 0x0a, 0x12, 0x67, 0x69, 0x74, 0x5f, 0x63, 0x6c, 0x6f,
+	0x6e, 0x65, 0x5f, 0x70, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x17, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x47, 0x69, 0x74, 0x43, 0x6c, 0x6f,
+	0x6e, 0x65, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x52, 0x10, 0x67, 0x69, 0x74, 0x43,
+	0x6c, 0x6f, 0x6e, 0x65, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x12, 0x3c, 0x0a, 0x0f,
+	0x67, 0x69, 0x74, 0x5f, 0x63, 0x6c, 0x6f, 0x6e, 0x65, 0x5f, 0x72, 0x65, 0x74, 0x72, 0x79, 0x18,
+	0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x47, 0x69,
+	0x74, 0x43, 0x6c, 0x6f, 0x6e, 0x65, 0x52, 0x65, 0x74, 0x72, 0x79, 0x52, 0x0d, 0x67, 0x69, 0x74,
+	0x43, 0x6c, 0x6f, 0x6e, 0x65, 0x52, 0x65, 0x74, 0x72, 0x79, 0x12, 0x4b, 0x0a, 0x14, 0x64, 0x6f,
+	0x63, 0x6b, 0x65, 0x72, 0x5f, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x5f, 0x63, 0x6f, 0x6e, 0x74, 0x65,
+	0x78, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74,
+	0x2e, 0x44, 0x6f, 0x63, 0x6b, 0x65, 0x72, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x43, 0x6f, 0x6e, 0x74,
+	0x65, 0x78, 0x74, 0x52, 0x12, 0x64, 0x6f, 0x63, 0x6b, 0x65, 0x72, 0x42, 0x75, 0x69, 0x6c, 0x64,
+	0x43, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x22, 0xd2, 0x01, 0x0a, 0x10, 0x47, 0x69, 0x74, 0x43,
+	0x6c, 0x6f, 0x6e, 0x65, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x12, 0x17, 0x0a, 0x07,
+	0x73, 0x74, 0x65, 0x70, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73,
+	0x74, 0x65, 0x70, 0x49, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x72, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74,
+	0x6f, 0x72, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x72, 0x65, 0x70, 0x6f, 0x73,
+	0x69, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x68, 0x61, 0x73, 0x65, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x70, 0x68, 0x61, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x70,
+	0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x70, 0x65,
+	0x72, 0x63, 0x65, 0x6e, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x12,
+	0x14, 0x0a, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05,
+	0x74, 0x6f, 0x74, 0x61, 0x6c, 0x12, 0x25, 0x0a, 0x0e, 0x72, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65,
+	0x64, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x72,
+	0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x42, 0x79, 0x74, 0x65, 0x73, 0x22, 0xaa, 0x01, 0x0a,
+	0x0d, 0x47, 0x69, 0x74, 0x43, 0x6c, 0x6f, 0x6e, 0x65, 0x52, 0x65, 0x74, 0x72, 0x79, 0x12, 0x17,
+	0x0a, 0x07, 0x73, 0x74, 0x65, 0x70, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x73, 0x74, 0x65, 0x70, 0x49, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x72, 0x65, 0x70, 0x6f, 0x73,
+	0x69, 0x74, 0x6f, 0x72, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x72, 0x65, 0x70,
+	0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x74, 0x74, 0x65, 0x6d,
+	0x70, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x61, 0x74, 0x74, 0x65, 0x6d, 0x70,
+	0x74, 0x12, 0x21, 0x0a, 0x0c, 0x6d, 0x61, 0x78, 0x5f, 0x61, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74,
+	0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0b, 0x6d, 0x61, 0x78, 0x41, 0x74, 0x74, 0x65,
+	0x6d, 0x70, 0x74, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x6d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x65, 0x72, 0x72,
+	0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x65, 0x0a, 0x12, 0x52, 0x65, 0x73,
+	0x6f, 0x6c, 0x76, 0x65, 0x64, 0x52, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x12,
+	0x1e, 0x0a, 0x0a, 0x72, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0a, 0x72, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x12,
+	0x10, 0x0a, 0x03, 0x72, 0x65, 0x66, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x72, 0x65,
+	0x66, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x5f, 0x73, 0x68, 0x61, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x53, 0x68, 0x61,
+	0x22, 0xb6, 0x02, 0x0a, 0x09, 0x53, 0x74, 0x65, 0x70, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x17,
+	0x0a, 0x07, 0x73, 0x74, 0x65, 0x70, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x73, 0x74, 0x65, 0x70, 0x49, 0x64, 0x12, 0x2c, 0x0a, 0x09, 0x73, 0x74, 0x65, 0x70, 0x5f,
+	0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0f, 0x2e, 0x61, 0x67, 0x65,
+	0x6e, 0x74, 0x2e, 0x53, 0x74, 0x65, 0x70, 0x54, 0x79, 0x70, 0x65, 0x52, 0x08, 0x73, 0x74, 0x65,
+	0x70, 0x54, 0x79, 0x70, 0x65, 0x12, 0x29, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x11, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x53, 0x74,
+	0x65, 0x70, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69,
+	0x6f, 0x6e, 0x12, 0x1e, 0x0a, 0x0a, 0x72, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x72, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f,
+	0x72, 0x79, 0x12, 0x1f, 0x0a, 0x0b, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x6d,
+	0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x4d, 0x73, 0x12, 0x2f, 0x0a, 0x0a, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x63, 0x6f, 0x64,
+	0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x10, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e,
+	0x45, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x52, 0x09, 0x65, 0x72, 0x72, 0x6f, 0x72,
+	0x43, 0x6f, 0x64, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x6d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x65, 0x72, 0x72,
+	0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x94, 0x01, 0x0a, 0x13, 0x44, 0x6f,
+	0x63, 0x6b, 0x65, 0x72, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73,
+	0x73, 0x12, 0x17, 0x0a, 0x07, 0x73, 0x74, 0x65, 0x70, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x65, 0x70, 0x49, 0x64, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x75,
+	0x72, 0x72, 0x65, 0x6e, 0x74, 0x5f, 0x73, 0x74, 0x65, 0x70, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x0b, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x53, 0x74, 0x65, 0x70, 0x12, 0x1f, 0x0a,
+	0x0b, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x73, 0x74, 0x65, 0x70, 0x73, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x0a, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x53, 0x74, 0x65, 0x70, 0x73, 0x12, 0x20,
+	0x0a, 0x0b, 0x69, 0x6e, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0b, 0x69, 0x6e, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x22, 0x77, 0x0a, 0x12, 0x44, 0x6f, 0x63, 0x6b, 0x65, 0x72, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x43,
+	0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x73, 0x74, 0x65, 0x70, 0x5f, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x65, 0x70, 0x49, 0x64, 0x12,
+	0x1d, 0x0a, 0x0a, 0x73, 0x69, 0x7a, 0x65, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x09, 0x73, 0x69, 0x7a, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x29,
+	0x0a, 0x10, 0x68, 0x61, 0x73, 0x5f, 0x64, 0x6f, 0x63, 0x6b, 0x65, 0x72, 0x69, 0x67, 0x6e, 0x6f,
+	0x72, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0f, 0x68, 0x61, 0x73, 0x44, 0x6f, 0x63,
+	0x6b, 0x65, 0x72, 0x69, 0x67, 0x6e, 0x6f, 0x72, 0x65, 0x22, 0x96, 0x02, 0x0a, 0x19, 0x42, 0x75,
+	0x69, 0x6c, 0x64, 0x41, 0x6e, 0x64, 0x53, 0x74, 0x61, 0x72, 0x74, 0x44, 0x65, 0x76, 0x45, 0x6e,
+	0x76, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65,
+	0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73,
+	0x73, 0x12, 0x2f, 0x0a, 0x0a, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x10, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x45, 0x72,
+	0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x52, 0x09, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f,
+	0x64, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x6d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x65, 0x72, 0x72, 0x6f, 0x72,
+	0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x64, 0x75, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x5f, 0x6d, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x64, 0x75,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x73, 0x12, 0x26, 0x0a, 0x05, 0x73, 0x74, 0x65, 0x70,
+	0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e,
+	0x53, 0x74, 0x65, 0x70, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x05, 0x73, 0x74, 0x65, 0x70, 0x73,
+	0x12, 0x26, 0x0a, 0x0f, 0x64, 0x65, 0x76, 0x5f, 0x65, 0x6e, 0x76, 0x5f, 0x73, 0x74, 0x61, 0x72,
+	0x74, 0x65, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d, 0x64, 0x65, 0x76, 0x45, 0x6e,
+	0x76, 0x53, 0x74, 0x61, 0x72, 0x74, 0x65, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x75, 0x6d, 0x6d,
+	0x61, 0x72, 0x79, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73, 0x75, 0x6d, 0x6d, 0x61,
+	0x72, 0x79, 0x22, 0xfc, 0x01, 0x0a, 0x11, 0x53, 0x74, 0x6f, 0x70, 0x44, 0x65, 0x76, 0x45, 0x6e,
+	0x76, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x35, 0x0a, 0x14, 0x73, 0x74, 0x6f, 0x70,
+	0x5f, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x5f, 0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x00, 0x52, 0x12, 0x73, 0x74, 0x6f, 0x70, 0x54, 0x69,
+	0x6d, 0x65, 0x6f, 0x75, 0x74, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x88, 0x01, 0x01, 0x12,
+	0x3f, 0x0a, 0x0b, 0x6f, 0x6e, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x6c, 0x69, 0x63, 0x74, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x1e, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x4f, 0x70, 0x65,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x66, 0x6c, 0x69, 0x63, 0x74, 0x50, 0x6f,
+	0x6c, 0x69, 0x63, 0x79, 0x52, 0x0a, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x66, 0x6c, 0x69, 0x63, 0x74,
+	0x12, 0x20, 0x0a, 0x0c, 0x64, 0x65, 0x76, 0x5f, 0x65, 0x6e, 0x76, 0x5f, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x64, 0x65, 0x76, 0x45, 0x6e, 0x76, 0x4e, 0x61,
+	0x6d, 0x65, 0x12, 0x34, 0x0a, 0x16, 0x63, 0x6f, 0x6e, 0x74, 0x69, 0x6e, 0x75, 0x65, 0x5f, 0x6f,
+	0x6e, 0x5f, 0x64, 0x69, 0x73, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x14, 0x63, 0x6f, 0x6e, 0x74, 0x69, 0x6e, 0x75, 0x65, 0x4f, 0x6e, 0x44, 0x69,
+	0x73, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x42, 0x17, 0x0a, 0x15, 0x5f, 0x73, 0x74, 0x6f,
+	0x70, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x5f, 0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64,
+	0x73, 0x22, 0x54, 0x0a, 0x0f, 0x53, 0x74, 0x6f, 0x70, 0x44, 0x65, 0x76, 0x45, 0x6e, 0x76, 0x52,
+	0x65, 0x70, 0x6c, 0x79, 0x12, 0x26, 0x0a, 0x0f, 0x6c, 0x6f, 0x67, 0x5f, 0x6c, 0x69, 0x6e, 0x65,
+	0x5f, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x6c,
+	0x6f, 0x67, 0x4c, 0x69, 0x6e, 0x65, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x19, 0x0a, 0x08,
+	0x6c, 0x6f, 0x67, 0x5f, 0x6c, 0x69, 0x6e, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
+	0x6c, 0x6f, 0x67, 0x4c, 0x69, 0x6e, 0x65, 0x22, 0x3a, 0x0a, 0x16, 0x47, 0x65, 0x74, 0x44, 0x65,
+	0x76, 0x45, 0x6e, 0x76, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x20, 0x0a, 0x0c, 0x64, 0x65, 0x76, 0x5f, 0x65, 0x6e, 0x76, 0x5f, 0x6e, 0x61, 0x6d,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x64, 0x65, 0x76, 0x45, 0x6e, 0x76, 0x4e,
+	0x61, 0x6d, 0x65, 0x22, 0x92, 0x03, 0x0a, 0x14, 0x47, 0x65, 0x74, 0x44, 0x65, 0x76, 0x45, 0x6e,
+	0x76, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x27, 0x0a, 0x0f,
+	0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72,
+	0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x5f, 0x69,
+	0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x49, 0x64,
+	0x12, 0x24, 0x0a, 0x0e, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x5f, 0x62, 0x75, 0x69, 0x6c, 0x74, 0x5f,
+	0x61, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x42,
+	0x75, 0x69, 0x6c, 0x74, 0x41, 0x74, 0x12, 0x41, 0x0a, 0x0c, 0x72, 0x65, 0x70, 0x6f, 0x73, 0x69,
+	0x74, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x61,
+	0x67, 0x65, 0x6e, 0x74, 0x2e, 0x44, 0x65, 0x76, 0x45, 0x6e, 0x76, 0x52, 0x65, 0x70, 0x6f, 0x73,
+	0x69, 0x74, 0x6f, 0x72, 0x79, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x0c, 0x72, 0x65, 0x70,
+	0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x61, 0x67, 0x65,
+	0x6e, 0x74, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0c, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x3d,
+	0x0a, 0x11, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x5f, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x61, 0x67, 0x65, 0x6e,
+	0x74, 0x2e, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x10, 0x63, 0x75, 0x72,
+	0x72, 0x65, 0x6e, 0x74, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x20, 0x0a,
+	0x0c, 0x64, 0x65, 0x76, 0x5f, 0x65, 0x6e, 0x76, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0a, 0x64, 0x65, 0x76, 0x45, 0x6e, 0x76, 0x4e, 0x61, 0x6d, 0x65, 0x12,
+	0x1b, 0x0a, 0x09, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x5f, 0x74, 0x61, 0x67, 0x18, 0x08, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x08, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x54, 0x61, 0x67, 0x12, 0x2a, 0x0a, 0x06,
+	0x69, 0x6d, 0x61, 0x67, 0x65, 0x73, 0x18, 0x09, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x61,
+	0x67, 0x65, 0x6e, 0x74, 0x2e, 0x44, 0x65, 0x76, 0x45, 0x6e, 0x76, 0x49, 0x6d, 0x61, 0x67, 0x65,
+	0x52, 0x06, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x73, 0x22, 0x8b, 0x01, 0x0a, 0x0b, 0x44, 0x65, 0x76,
+	0x45, 0x6e, 0x76, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x74, 0x61, 0x67, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x74, 0x61, 0x67, 0x12, 0x19, 0x0a, 0x08, 0x69, 0x6d,
+	0x61, 0x67, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x69, 0x6d,
+	0x61, 0x67, 0x65, 0x49, 0x64, 0x12, 0x19, 0x0a, 0x08, 0x62, 0x75, 0x69, 0x6c, 0x74, 0x5f, 0x61,
+	0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x62, 0x75, 0x69, 0x6c, 0x74, 0x41, 0x74,
+	0x12, 0x34, 0x0a, 0x17, 0x64, 0x65, 0x76, 0x5f, 0x65, 0x6e, 0x76, 0x5f, 0x72, 0x65, 0x70, 0x6f,
+	0x5f, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x5f, 0x73, 0x68, 0x61, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x13, 0x64, 0x65, 0x76, 0x45, 0x6e, 0x76, 0x52, 0x65, 0x70, 0x6f, 0x43, 0x6f, 0x6d,
+	0x6d, 0x69, 0x74, 0x53, 0x68, 0x61, 0x22, 0x14, 0x0a, 0x12, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x65,
+	0x76, 0x45, 0x6e, 0x76, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x43, 0x0a, 0x10,
+	0x4c, 0x69, 0x73, 0x74, 0x44, 0x65, 0x76, 0x45, 0x6e, 0x76, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79,
+	0x12, 0x2f, 0x0a, 0x08, 0x64, 0x65, 0x76, 0x5f, 0x65, 0x6e, 0x76, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x14, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x44, 0x65, 0x76, 0x45, 0x6e,
+	0x76, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x52, 0x07, 0x64, 0x65, 0x76, 0x45, 0x6e, 0x76,
+	0x73, 0x22, 0x95, 0x01, 0x0a, 0x0d, 0x44, 0x65, 0x76, 0x45, 0x6e, 0x76, 0x53, 0x75, 0x6d, 0x6d,
+	0x61, 0x72, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x27, 0x0a, 0x0f, 0x63, 0x6f, 0x6e, 0x74, 0x61,
+	0x69, 0x6e, 0x65, 0x72, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0e, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x53, 0x74, 0x61, 0x74, 0x65,
+	0x12, 0x19, 0x0a, 0x08, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x07, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x49, 0x64, 0x12, 0x2c, 0x0a, 0x12, 0x77,
+	0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x5f, 0x64, 0x69, 0x72, 0x5f, 0x70, 0x61, 0x74,
+	0x68, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x77, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61,
+	0x63, 0x65, 0x44, 0x69, 0x72, 0x50, 0x61, 0x74, 0x68, 0x22, 0xf7, 0x01, 0x0a, 0x16, 0x44, 0x65,
+	0x76, 0x45, 0x6e, 0x76, 0x52, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x53, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x22,
+	0x0a, 0x0d, 0x72, 0x6f, 0x6f, 0x74, 0x5f, 0x64, 0x69, 0x72, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x72, 0x6f, 0x6f, 0x74, 0x44, 0x69, 0x72, 0x50, 0x61,
+	0x74, 0x68, 0x12, 0x25, 0x0a, 0x0f, 0x69, 0x73, 0x5f, 0x64, 0x65, 0x76, 0x5f, 0x65, 0x6e, 0x76,
+	0x5f, 0x72, 0x65, 0x70, 0x6f, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x69, 0x73, 0x44,
+	0x65, 0x76, 0x45, 0x6e, 0x76, 0x52, 0x65, 0x70, 0x6f, 0x12, 0x37, 0x0a, 0x05, 0x68, 0x6f, 0x6f,
+	0x6b, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74,
+	0x2e, 0x44, 0x65, 0x76, 0x45, 0x6e, 0x76, 0x52, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72,
+	0x79, 0x48, 0x6f, 0x6f, 0x6b, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x05, 0x68, 0x6f, 0x6f,
+	0x6b, 0x73, 0x12, 0x10, 0x0a, 0x03, 0x72, 0x65, 0x66, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x03, 0x72, 0x65, 0x66, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x5f, 0x73,
+	0x68, 0x61, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74,
+	0x53, 0x68, 0x61, 0x22, 0xf2, 0x01, 0x0a, 0x1a, 0x44, 0x65, 0x76, 0x45, 0x6e, 0x76, 0x52, 0x65,
+	0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x48, 0x6f, 0x6f, 0x6b, 0x53, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x12, 0x28, 0x0a, 0x10, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x5f, 0x66, 0x69, 0x6c,
+	0x65, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x73, 0x63,
+	0x72, 0x69, 0x70, 0x74, 0x46, 0x69, 0x6c, 0x65, 0x50, 0x61, 0x74, 0x68, 0x12, 0x29, 0x0a, 0x0e,
+	0x6c, 0x61, 0x73, 0x74, 0x5f, 0x65, 0x78, 0x69, 0x74, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x05, 0x48, 0x00, 0x52, 0x0c, 0x6c, 0x61, 0x73, 0x74, 0x45, 0x78, 0x69, 0x74,
+	0x43, 0x6f, 0x64, 0x65, 0x88, 0x01, 0x01, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x27, 0x0a, 0x0f, 0x74,
+	0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x5f, 0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x0e, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x53, 0x65, 0x63,
+	0x6f, 0x6e, 0x64, 0x73, 0x12, 0x2f, 0x0a, 0x08, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x72, 0x75, 0x6e,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x48,
+	0x6f, 0x6f, 0x6b, 0x52, 0x75, 0x6e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x07, 0x6c, 0x61,
+	0x73, 0x74, 0x52, 0x75, 0x6e, 0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x65,
+	0x78, 0x69, 0x74, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x22, 0xaa, 0x01, 0x0a, 0x0d, 0x48, 0x6f, 0x6f,
+	0x6b, 0x52, 0x75, 0x6e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x65, 0x78,
+	0x69, 0x74, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x65,
+	0x78, 0x69, 0x74, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x64,
+	0x5f, 0x6f, 0x75, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x74, 0x69, 0x6d, 0x65,
+	0x64, 0x4f, 0x75, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x72, 0x74, 0x65, 0x64, 0x5f,
+	0x61, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x74, 0x61, 0x72, 0x74, 0x65,
+	0x64, 0x41, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f,
+	0x6d, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x4d, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x74,
+	0x61, 0x69, 0x6c, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6f, 0x75, 0x74, 0x70, 0x75,
+	0x74, 0x54, 0x61, 0x69, 0x6c, 0x22, 0x3a, 0x0a, 0x15, 0x57, 0x61, 0x74, 0x63, 0x68, 0x4f, 0x70,
+	0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21,
+	0x0a, 0x0c, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x49,
+	0x64, 0x22, 0xe8, 0x02, 0x0a, 0x13, 0x57, 0x61, 0x74, 0x63, 0x68, 0x4f, 0x70, 0x65, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x4a, 0x0a, 0x13, 0x69, 0x6e, 0x69,
+	0x74, 0x5f, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x5f, 0x72, 0x65, 0x70, 0x6c, 0x79,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x49,
+	0x6e, 0x69, 0x74, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x79,
+	0x48, 0x00, 0x52, 0x11, 0x69, 0x6e, 0x69, 0x74, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65,
+	0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x62, 0x0a, 0x1d, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x5f, 0x61,
+	0x6e, 0x64, 0x5f, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x64, 0x65, 0x76, 0x5f, 0x65, 0x6e, 0x76,
+	0x5f, 0x72, 0x65, 0x70, 0x6c, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x61,
+	0x67, 0x65, 0x6e, 0x74, 0x2e, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x41, 0x6e, 0x64, 0x53, 0x74, 0x61,
+	0x72, 0x74, 0x44, 0x65, 0x76, 0x45, 0x6e, 0x76, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x48, 0x00, 0x52,
+	0x18, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x41, 0x6e, 0x64, 0x53, 0x74, 0x61, 0x72, 0x74, 0x44, 0x65,
+	0x76, 0x45, 0x6e, 0x76, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x45, 0x0a, 0x12, 0x73, 0x74, 0x6f,
+	0x70, 0x5f, 0x64, 0x65, 0x76, 0x5f, 0x65, 0x6e, 0x76, 0x5f, 0x72, 0x65, 0x70, 0x6c, 0x79, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x53, 0x74,
+	0x6f, 0x70, 0x44, 0x65, 0x76, 0x45, 0x6e, 0x76, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x48, 0x00, 0x52,
+	0x0f, 0x73, 0x74, 0x6f, 0x70, 0x44, 0x65, 0x76, 0x45, 0x6e, 0x76, 0x52, 0x65, 0x70, 0x6c, 0x79,
+	0x12, 0x51, 0x0a, 0x16, 0x72, 0x6f, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x5f, 0x64, 0x65, 0x76,
+	0x5f, 0x65, 0x6e, 0x76, 0x5f, 0x72, 0x65, 0x70, 0x6c, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1a, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x52, 0x6f, 0x6c, 0x6c, 0x62, 0x61, 0x63,
+	0x6b, 0x44, 0x65, 0x76, 0x45, 0x6e, 0x76, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x48, 0x00, 0x52, 0x13,
+	0x72, 0x6f, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x44, 0x65, 0x76, 0x45, 0x6e, 0x76, 0x52, 0x65,
+	0x70, 0x6c, 0x79, 0x42, 0x07, 0x0a, 0x05, 0x72, 0x65, 0x70, 0x6c, 0x79, 0x22, 0xde, 0x01, 0x0a,
+	0x18, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61,
+	0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x3f, 0x0a, 0x0b, 0x6f, 0x6e, 0x5f,
+	0x63, 0x6f, 0x6e, 0x66, 0x6c, 0x69, 0x63, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1e,
+	0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x43, 0x6f, 0x6e, 0x66, 0x6c, 0x69, 0x63, 0x74, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x52, 0x0a,
+	0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x66, 0x6c, 0x69, 0x63, 0x74, 0x12, 0x5f, 0x0a, 0x15, 0x72, 0x65,
+	0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x5f, 0x65, 0x78, 0x63, 0x6c, 0x75,
+	0x64, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2a, 0x2e, 0x61, 0x67, 0x65, 0x6e,
+	0x74, 0x2e, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70,
+	0x61, 0x63, 0x65, 0x52, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x45, 0x78, 0x63,
+	0x6c, 0x75, 0x64, 0x65, 0x73, 0x52, 0x14, 0x72, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72,
+	0x69, 0x65, 0x73, 0x45, 0x78, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x73, 0x12, 0x20, 0x0a, 0x0c, 0x64,
+	0x65, 0x76, 0x5f, 0x65, 0x6e, 0x76, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0a, 0x64, 0x65, 0x76, 0x45, 0x6e, 0x76, 0x4e, 0x61, 0x6d, 0x65, 0x22, 0x61, 0x0a,
+	0x23, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61,
+	0x63, 0x65, 0x52, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x45, 0x78, 0x63, 0x6c,
+	0x75, 0x64, 0x65, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x72, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f,
+	0x72, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x72, 0x65, 0x70, 0x6f, 0x73, 0x69,
+	0x74, 0x6f, 0x72, 0x79, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x61, 0x74, 0x74, 0x65, 0x72, 0x6e, 0x73,
+	0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x70, 0x61, 0x74, 0x74, 0x65, 0x72, 0x6e, 0x73,
+	0x22, 0x4a, 0x0a, 0x16, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x57, 0x6f, 0x72, 0x6b,
+	0x73, 0x70, 0x61, 0x63, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x68,
+	0x75, 0x6e, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x63, 0x68, 0x75, 0x6e, 0x6b,
+	0x12, 0x1a, 0x0a, 0x08, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x75, 0x6d, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x08, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x75, 0x6d, 0x22, 0xae, 0x01, 0x0a,
+	0x17, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x3f, 0x0a, 0x0b, 0x6f, 0x6e, 0x5f, 0x63,
+	0x6f, 0x6e, 0x66, 0x6c, 0x69, 0x63, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1e, 0x2e,
+	0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x43,
+	0x6f, 0x6e, 0x66, 0x6c, 0x69, 0x63, 0x74, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x52, 0x0a, 0x6f,
+	0x6e, 0x43, 0x6f, 0x6e, 0x66, 0x6c, 0x69, 0x63, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x68, 0x65,
+	0x63, 0x6b, 0x73, 0x75, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x63, 0x68, 0x65,
+	0x63, 0x6b, 0x73, 0x75, 0x6d, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x12, 0x20, 0x0a, 0x0c, 0x64,
+	0x65, 0x76, 0x5f, 0x65, 0x6e, 0x76, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0a, 0x64, 0x65, 0x76, 0x45, 0x6e, 0x76, 0x4e, 0x61, 0x6d, 0x65, 0x22, 0x3e, 0x0a,
+	0x15, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63,
+	0x65, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x25, 0x0a, 0x0e, 0x72, 0x65, 0x73, 0x74, 0x6f, 0x72,
+	0x65, 0x64, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d,
+	0x72, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x42, 0x79, 0x74, 0x65, 0x73, 0x22, 0xcd, 0x01,
+	0x0a, 0x15, 0x52, 0x6f, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x44, 0x65, 0x76, 0x45, 0x6e, 0x76,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x20, 0x0a, 0x0c, 0x64, 0x65, 0x76, 0x5f, 0x65,
+	0x6e, 0x76, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x64,
+	0x65, 0x76, 0x45, 0x6e, 0x76, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x69, 0x6d, 0x61,
+	0x67, 0x65, 0x5f, 0x74, 0x61, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x69, 0x6d,
+	0x61, 0x67, 0x65, 0x54, 0x61, 0x67, 0x12, 0x3f, 0x0a, 0x0b, 0x6f, 0x6e, 0x5f, 0x63, 0x6f, 0x6e,
+	0x66, 0x6c, 0x69, 0x63, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1e, 0x2e, 0x61, 0x67,
+	0x65, 0x6e, 0x74, 0x2e, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e,
+	0x66, 0x6c, 0x69, 0x63, 0x74, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x52, 0x0a, 0x6f, 0x6e, 0x43,
+	0x6f, 0x6e, 0x66, 0x6c, 0x69, 0x63, 0x74, 0x12, 0x34, 0x0a, 0x16, 0x63, 0x6f, 0x6e, 0x74, 0x69,
+	0x6e, 0x75, 0x65, 0x5f, 0x6f, 0x6e, 0x5f, 0x64, 0x69, 0x73, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63,
+	0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x14, 0x63, 0x6f, 0x6e, 0x74, 0x69, 0x6e, 0x75,
+	0x65, 0x4f, 0x6e, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x22, 0x82, 0x01,
+	0x0a, 0x13, 0x52, 0x6f, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x44, 0x65, 0x76, 0x45, 0x6e, 0x76,
+	0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x26, 0x0a, 0x0f, 0x6c, 0x6f, 0x67, 0x5f, 0x6c, 0x69, 0x6e,
+	0x65, 0x5f, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d,
+	0x6c, 0x6f, 0x67, 0x4c, 0x69, 0x6e, 0x65, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x19, 0x0a,
+	0x08, 0x6c, 0x6f, 0x67, 0x5f, 0x6c, 0x69, 0x6e, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x07, 0x6c, 0x6f, 0x67, 0x4c, 0x69, 0x6e, 0x65, 0x12, 0x28, 0x0a, 0x05, 0x69, 0x6d, 0x61, 0x67,
+	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e,
+	0x44, 0x65, 0x76, 0x45, 0x6e, 0x76, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x52, 0x05, 0x69, 0x6d, 0x61,
+	0x67, 0x65, 0x2a, 0x8a, 0x01, 0x0a, 0x17, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x43, 0x6f, 0x6e, 0x66, 0x6c, 0x69, 0x63, 0x74, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x24,
+	0x0a, 0x20, 0x4f, 0x50, 0x45, 0x52, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x43, 0x4f, 0x4e, 0x46,
+	0x4c, 0x49, 0x43, 0x54, 0x5f, 0x50, 0x4f, 0x4c, 0x49, 0x43, 0x59, 0x5f, 0x52, 0x45, 0x4a, 0x45,
+	0x43, 0x54, 0x10, 0x00, 0x12, 0x23, 0x0a, 0x1f, 0x4f, 0x50, 0x45, 0x52, 0x41, 0x54, 0x49, 0x4f,
+	0x4e, 0x5f, 0x43, 0x4f, 0x4e, 0x46, 0x4c, 0x49, 0x43, 0x54, 0x5f, 0x50, 0x4f, 0x4c, 0x49, 0x43,
+	0x59, 0x5f, 0x51, 0x55, 0x45, 0x55, 0x45, 0x10, 0x01, 0x12, 0x24, 0x0a, 0x20, 0x4f, 0x50, 0x45,
+	0x52, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x43, 0x4f, 0x4e, 0x46, 0x4c, 0x49, 0x43, 0x54, 0x5f,
+	0x50, 0x4f, 0x4c, 0x49, 0x43, 0x59, 0x5f, 0x41, 0x54, 0x54, 0x41, 0x43, 0x48, 0x10, 0x02, 0x2a,
+	0x58, 0x0a, 0x11, 0x48, 0x6f, 0x6f, 0x6b, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x50, 0x6f,
+	0x6c, 0x69, 0x63, 0x79, 0x12, 0x21, 0x0a, 0x1d, 0x48, 0x4f, 0x4f, 0x4b, 0x5f, 0x46, 0x41, 0x49,
+	0x4c, 0x55, 0x52, 0x45, 0x5f, 0x50, 0x4f, 0x4c, 0x49, 0x43, 0x59, 0x5f, 0x46, 0x41, 0x49, 0x4c,
+	0x5f, 0x46, 0x41, 0x53, 0x54, 0x10, 0x00, 0x12, 0x20, 0x0a, 0x1c, 0x48, 0x4f, 0x4f, 0x4b, 0x5f,
+	0x46, 0x41, 0x49, 0x4c, 0x55, 0x52, 0x45, 0x5f, 0x50, 0x4f, 0x4c, 0x49, 0x43, 0x59, 0x5f, 0x43,
+	0x4f, 0x4e, 0x54, 0x49, 0x4e, 0x55, 0x45, 0x10, 0x01, 0x2a, 0x51, 0x0a, 0x0f, 0x57, 0x6f, 0x72,
+	0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x4c, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x12, 0x19, 0x0a, 0x15,
+	0x57, 0x4f, 0x52, 0x4b, 0x53, 0x50, 0x41, 0x43, 0x45, 0x5f, 0x4c, 0x41, 0x59, 0x4f, 0x55, 0x54,
+	0x5f, 0x4e, 0x41, 0x4d, 0x45, 0x10, 0x00, 0x12, 0x23, 0x0a, 0x1f, 0x57, 0x4f, 0x52, 0x4b, 0x53,
+	0x50, 0x41, 0x43, 0x45, 0x5f, 0x4c, 0x41, 0x59, 0x4f, 0x55, 0x54, 0x5f, 0x4f, 0x57, 0x4e, 0x45,
+	0x52, 0x5f, 0x41, 0x4e, 0x44, 0x5f, 0x4e, 0x41, 0x4d, 0x45, 0x10, 0x01, 0x2a, 0x57, 0x0a, 0x11,
+	0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x53, 0x79, 0x6e, 0x63, 0x4d, 0x6f, 0x64,
+	0x65, 0x12, 0x1d, 0x0a, 0x19, 0x57, 0x4f, 0x52, 0x4b, 0x53, 0x50, 0x41, 0x43, 0x45, 0x5f, 0x53,
+	0x59, 0x4e, 0x43, 0x5f, 0x4d, 0x4f, 0x44, 0x45, 0x5f, 0x52, 0x45, 0x53, 0x45, 0x54, 0x10, 0x00,
+	0x12, 0x23, 0x0a, 0x1f, 0x57, 0x4f, 0x52, 0x4b, 0x53, 0x50, 0x41, 0x43, 0x45, 0x5f, 0x53, 0x59,
+	0x4e, 0x43, 0x5f, 0x4d, 0x4f, 0x44, 0x45, 0x5f, 0x49, 0x4e, 0x43, 0x52, 0x45, 0x4d, 0x45, 0x4e,
+	0x54, 0x41, 0x4c, 0x10, 0x01, 0x2a, 0xe4, 0x02, 0x0a, 0x08, 0x53, 0x74, 0x65, 0x70, 0x54, 0x79,
+	0x70, 0x65, 0x12, 0x19, 0x0a, 0x15, 0x53, 0x54, 0x45, 0x50, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f,
+	0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x24, 0x0a,
+	0x20, 0x53, 0x54, 0x45, 0x50, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x43, 0x4c, 0x4f, 0x4e, 0x45,
+	0x5f, 0x55, 0x53, 0x45, 0x52, 0x5f, 0x43, 0x4f, 0x4e, 0x46, 0x49, 0x47, 0x5f, 0x52, 0x45, 0x50,
+	0x4f, 0x10, 0x01, 0x12, 0x20, 0x0a, 0x1c, 0x53, 0x54, 0x45, 0x50, 0x5f, 0x54, 0x59, 0x50, 0x45,
+	0x5f, 0x43, 0x4c, 0x4f, 0x4e, 0x45, 0x5f, 0x44, 0x45, 0x56, 0x5f, 0x45, 0x4e, 0x56, 0x5f, 0x52,
+	0x45, 0x50, 0x4f, 0x10, 0x02, 0x12, 0x18, 0x0a, 0x14, 0x53, 0x54, 0x45, 0x50, 0x5f, 0x54, 0x59,
+	0x50, 0x45, 0x5f, 0x43, 0x4c, 0x4f, 0x4e, 0x45, 0x5f, 0x52, 0x45, 0x50, 0x4f, 0x10, 0x03, 0x12,
+	0x25, 0x0a, 0x21, 0x53, 0x54, 0x45, 0x50, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x42, 0x55, 0x49,
+	0x4c, 0x44, 0x5f, 0x55, 0x53, 0x45, 0x52, 0x5f, 0x43, 0x4f, 0x4e, 0x46, 0x49, 0x47, 0x5f, 0x49,
+	0x4d, 0x41, 0x47, 0x45, 0x10, 0x04, 0x12, 0x26, 0x0a, 0x22, 0x53, 0x54, 0x45, 0x50, 0x5f, 0x54,
+	0x59, 0x50, 0x45, 0x5f, 0x42, 0x55, 0x49, 0x4c, 0x44, 0x5f, 0x44, 0x45, 0x56, 0x5f, 0x45, 0x4e,
+	0x56, 0x5f, 0x52, 0x45, 0x50, 0x4f, 0x5f, 0x49, 0x4d, 0x41, 0x47, 0x45, 0x10, 0x05, 0x12, 0x1d,
+	0x0a, 0x19, 0x53, 0x54, 0x45, 0x50, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x53, 0x54, 0x41, 0x52,
+	0x54, 0x5f, 0x43, 0x4f, 0x4e, 0x54, 0x41, 0x49, 0x4e, 0x45, 0x52, 0x10, 0x06, 0x12, 0x16, 0x0a,
+	0x12, 0x53, 0x54, 0x45, 0x50, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x52, 0x55, 0x4e, 0x5f, 0x48,
+	0x4f, 0x4f, 0x4b, 0x10, 0x07, 0x12, 0x17, 0x0a, 0x13, 0x53, 0x54, 0x45, 0x50, 0x5f, 0x54, 0x59,
+	0x50, 0x45, 0x5f, 0x53, 0x59, 0x4e, 0x43, 0x5f, 0x52, 0x45, 0x50, 0x4f, 0x10, 0x08, 0x12, 0x1c,
+	0x0a, 0x18, 0x53, 0x54, 0x45, 0x50, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x49, 0x4e, 0x49, 0x54,
+	0x5f, 0x53, 0x55, 0x42, 0x4d, 0x4f, 0x44, 0x55, 0x4c, 0x45, 0x10, 0x09, 0x12, 0x1e, 0x0a, 0x1a,
+	0x53, 0x54, 0x45, 0x50, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x50, 0x55, 0x4c, 0x4c, 0x5f, 0x4c,
+	0x46, 0x53, 0x5f, 0x4f, 0x42, 0x4a, 0x45, 0x43, 0x54, 0x53, 0x10, 0x0a, 0x2a, 0x8d, 0x01, 0x0a,
+	0x0a, 0x53, 0x74, 0x65, 0x70, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1b, 0x0a, 0x17, 0x53,
+	0x54, 0x45, 0x50, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45,
+	0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x17, 0x0a, 0x13, 0x53, 0x54, 0x45, 0x50,
+	0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x53, 0x54, 0x41, 0x52, 0x54, 0x45, 0x44, 0x10,
+	0x01, 0x12, 0x18, 0x0a, 0x14, 0x53, 0x54, 0x45, 0x50, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53,
+	0x5f, 0x46, 0x49, 0x4e, 0x49, 0x53, 0x48, 0x45, 0x44, 0x10, 0x02, 0x12, 0x16, 0x0a, 0x12, 0x53,
+	0x54, 0x45, 0x50, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x46, 0x41, 0x49, 0x4c, 0x45,
+	0x44, 0x10, 0x03, 0x12, 0x17, 0x0a, 0x13, 0x53, 0x54, 0x45, 0x50, 0x5f, 0x53, 0x54, 0x41, 0x54,
+	0x55, 0x53, 0x5f, 0x53, 0x4b, 0x49, 0x50, 0x50, 0x45, 0x44, 0x10, 0x04, 0x2a, 0xa9, 0x02, 0x0a,
+	0x09, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x1a, 0x0a, 0x16, 0x45, 0x52,
+	0x52, 0x4f, 0x52, 0x5f, 0x43, 0x4f, 0x44, 0x45, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49,
+	0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x17, 0x0a, 0x13, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x5f,
+	0x43, 0x4f, 0x44, 0x45, 0x5f, 0x49, 0x4e, 0x54, 0x45, 0x52, 0x4e, 0x41, 0x4c, 0x10, 0x01, 0x12,
+	0x17, 0x0a, 0x13, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x5f, 0x43, 0x4f, 0x44, 0x45, 0x5f, 0x43, 0x41,
+	0x4e, 0x43, 0x45, 0x4c, 0x45, 0x44, 0x10, 0x02, 0x12, 0x1b, 0x0a, 0x17, 0x45, 0x52, 0x52, 0x4f,
+	0x52, 0x5f, 0x43, 0x4f, 0x44, 0x45, 0x5f, 0x43, 0x4c, 0x4f, 0x4e, 0x45, 0x5f, 0x46, 0x41, 0x49,
+	0x4c, 0x45, 0x44, 0x10, 0x03, 0x12, 0x21, 0x0a, 0x1d, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x5f, 0x43,
+	0x4f, 0x44, 0x45, 0x5f, 0x49, 0x4e, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x5f, 0x44, 0x4f, 0x43, 0x4b,
+	0x45, 0x52, 0x46, 0x49, 0x4c, 0x45, 0x10, 0x04, 0x12, 0x21, 0x0a, 0x1d, 0x45, 0x52, 0x52, 0x4f,
+	0x52, 0x5f, 0x43, 0x4f, 0x44, 0x45, 0x5f, 0x49, 0x4d, 0x41, 0x47, 0x45, 0x5f, 0x42, 0x55, 0x49,
+	0x4c, 0x44, 0x5f, 0x46, 0x41, 0x49, 0x4c, 0x45, 0x44, 0x10, 0x05, 0x12, 0x25, 0x0a, 0x21, 0x45,
+	0x52, 0x52, 0x4f, 0x52, 0x5f, 0x43, 0x4f, 0x44, 0x45, 0x5f, 0x43, 0x4f, 0x4e, 0x54, 0x41, 0x49,
+	0x4e, 0x45, 0x52, 0x5f, 0x53, 0x54, 0x41, 0x52, 0x54, 0x5f, 0x46, 0x41, 0x49, 0x4c, 0x45, 0x44,
+	0x10, 0x06, 0x12, 0x1a, 0x0a, 0x16, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x5f, 0x43, 0x4f, 0x44, 0x45,
+	0x5f, 0x48, 0x4f, 0x4f, 0x4b, 0x5f, 0x46, 0x41, 0x49, 0x4c, 0x45, 0x44, 0x10, 0x07, 0x12, 0x28,
+	0x0a, 0x24, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x5f, 0x43, 0x4f, 0x44, 0x45, 0x5f, 0x57, 0x4f, 0x52,
+	0x4b, 0x53, 0x50, 0x41, 0x43, 0x45, 0x5f, 0x4c, 0x41, 0x59, 0x4f, 0x55, 0x54, 0x5f, 0x43, 0x4f,
+	0x4e, 0x46, 0x4c, 0x49, 0x43, 0x54, 0x10, 0x08, 0x32, 0xd9, 0x05, 0x0a, 0x05, 0x41, 0x67, 0x65,
+	0x6e, 0x74, 0x12, 0x48, 0x0a, 0x0c, 0x49, 0x6e, 0x69, 0x74, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e,
+	0x63, 0x65, 0x12, 0x1a, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x49, 0x6e, 0x69, 0x74, 0x49,
+	0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18,
+	0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x49, 0x6e, 0x69, 0x74, 0x49, 0x6e, 0x73, 0x74, 0x61,
+	0x6e, 0x63, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x30, 0x01, 0x12, 0x5d, 0x0a, 0x13,
+	0x42, 0x75, 0x69, 0x6c, 0x64, 0x41, 0x6e, 0x64, 0x53, 0x74, 0x61, 0x72, 0x74, 0x44, 0x65, 0x76,
+	0x45, 0x6e, 0x76, 0x12, 0x21, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x42, 0x75, 0x69, 0x6c,
+	0x64, 0x41, 0x6e, 0x64, 0x53, 0x74, 0x61, 0x72, 0x74, 0x44, 0x65, 0x76, 0x45, 0x6e, 0x76, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x42,
+	0x75, 0x69, 0x6c, 0x64, 0x41, 0x6e, 0x64, 0x53, 0x74, 0x61, 0x72, 0x74, 0x44, 0x65, 0x76, 0x45,
+	0x6e, 0x76, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x30, 0x01, 0x12, 0x42, 0x0a, 0x0a, 0x53,
+	0x74, 0x6f, 0x70, 0x44, 0x65, 0x76, 0x45, 0x6e, 0x76, 0x12, 0x18, 0x2e, 0x61, 0x67, 0x65, 0x6e,
+	0x74, 0x2e, 0x53, 0x74, 0x6f, 0x70, 0x44, 0x65, 0x76, 0x45, 0x6e, 0x76, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x53, 0x74, 0x6f, 0x70,
+	0x44, 0x65, 0x76, 0x45, 0x6e, 0x76, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x30, 0x01, 0x12,
+	0x4f, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x44, 0x65, 0x76, 0x45, 0x6e, 0x76, 0x53, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x12, 0x1d, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x47, 0x65, 0x74, 0x44, 0x65,
+	0x76, 0x45, 0x6e, 0x76, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x1b, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x47, 0x65, 0x74, 0x44, 0x65, 0x76,
+	0x45, 0x6e, 0x76, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00,
+	0x12, 0x43, 0x0a, 0x0b, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x65, 0x76, 0x45, 0x6e, 0x76, 0x73, 0x12,
+	0x19, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x65, 0x76, 0x45,
+	0x6e, 0x76, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x61, 0x67, 0x65,
+	0x6e, 0x74, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x65, 0x76, 0x45, 0x6e, 0x76, 0x73, 0x52, 0x65,
+	0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x4e, 0x0a, 0x0e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x4f, 0x70,
+	0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1c, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e,
+	0x57, 0x61, 0x74, 0x63, 0x68, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x57, 0x61,
+	0x74, 0x63, 0x68, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x70, 0x6c,
+	0x79, 0x22, 0x00, 0x30, 0x01, 0x12, 0x57, 0x0a, 0x11, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f,
+	0x74, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x12, 0x1f, 0x2e, 0x61, 0x67, 0x65,
+	0x6e, 0x74, 0x2e, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x57, 0x6f, 0x72, 0x6b, 0x73,
+	0x70, 0x61, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x61, 0x67,
+	0x65, 0x6e, 0x74, 0x2e, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x57, 0x6f, 0x72, 0x6b,
+	0x73, 0x70, 0x61, 0x63, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x30, 0x01, 0x12, 0x54,
+	0x0a, 0x10, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61,
+	0x63, 0x65, 0x12, 0x1e, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x52, 0x65, 0x73, 0x74, 0x6f,
+	0x72, 0x65, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x52, 0x65, 0x73, 0x74, 0x6f,
+	0x72, 0x65, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x79,
+	0x22, 0x00, 0x28, 0x01, 0x12, 0x4e, 0x0a, 0x0e, 0x52, 0x6f, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b,
+	0x44, 0x65, 0x76, 0x45, 0x6e, 0x76, 0x12, 0x1c, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x52,
+	0x6f, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x44, 0x65, 0x76, 0x45, 0x6e, 0x76, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x52, 0x6f, 0x6c,
+	0x6c, 0x62, 0x61, 0x63, 0x6b, 0x44, 0x65, 0x76, 0x45, 0x6e, 0x76, 0x52, 0x65, 0x70, 0x6c, 0x79,
+	0x22, 0x00, 0x30, 0x01, 0x42, 0x22, 0x5a, 0x20, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63,
+	0x6f, 0x6d, 0x2f, 0x72, 0x65, 0x63, 0x6f, 0x64, 0x65, 0x2d, 0x73, 0x68, 0x2f, 0x61, 0x67, 0x65,
+	0x6e, 0x74, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_agent_proto_rawDescOnce sync.Once
+	file_agent_proto_rawDescData = file_agent_proto_rawDesc
+)
+
+func file_agent_proto_rawDescGZIP() []byte {
+	file_agent_proto_rawDescOnce.Do(func() {
+		file_agent_proto_rawDescData = protoimpl.X.CompressGZIP(file_agent_proto_rawDescData)
+	})
+	return file_agent_proto_rawDescData
+}
+
+var file_agent_proto_enumTypes = make([]protoimpl.EnumInfo, 7)
+var file_agent_proto_msgTypes = make([]protoimpl.MessageInfo, 38)
+var file_agent_proto_goTypes = []interface{}{
+	(OperationConflictPolicy)(0),                // 0: agent.OperationConflictPolicy
+	(HookFailurePolicy)(0),                      // 1: agent.HookFailurePolicy
+	(WorkspaceLayout)(0),                        // 2: agent.WorkspaceLayout
+	(WorkspaceSyncMode)(0),                      // 3: agent.WorkspaceSyncMode
+	(StepType)(0),                               // 4: agent.StepType
+	(StepStatus)(0),                             // 5: agent.StepStatus
+	(ErrorCode)(0),                              // 6: agent.ErrorCode
+	(*Operation)(nil),                           // 7: agent.Operation
+	(*InitInstanceRequest)(nil),                 // 8: agent.InitInstanceRequest
+	(*InitInstanceReply)(nil),                   // 9: agent.InitInstanceReply
+	(*BuildAndStartDevEnvRequest)(nil),          // 10: agent.BuildAndStartDevEnvRequest
+	(*BuildSecret)(nil),                         // 11: agent.BuildSecret
+	(*ImageBuildOptions)(nil),                   // 12: agent.ImageBuildOptions
+	(*CloneOptions)(nil),                        // 13: agent.CloneOptions
+	(*RepositoryCloneOptions)(nil),              // 14: agent.RepositoryCloneOptions
+	(*RetryPolicy)(nil),                         // 15: agent.RetryPolicy
+	(*BuildAndStartDevEnvReply)(nil),            // 16: agent.BuildAndStartDevEnvReply
+	(*GitCloneProgress)(nil),                    // 17: agent.GitCloneProgress
+	(*GitCloneRetry)(nil),                       // 18: agent.GitCloneRetry
+	(*ResolvedRepository)(nil),                  // 19: agent.ResolvedRepository
+	(*StepEvent)(nil),                           // 20: agent.StepEvent
+	(*DockerBuildProgress)(nil),                 // 21: agent.DockerBuildProgress
+	(*DockerBuildContext)(nil),                  // 22: agent.DockerBuildContext
+	(*BuildAndStartDevEnvResult)(nil),           // 23: agent.BuildAndStartDevEnvResult
+	(*StopDevEnvRequest)(nil),                   // 24: agent.StopDevEnvRequest
+	(*StopDevEnvReply)(nil),                     // 25: agent.StopDevEnvReply
+	(*GetDevEnvStatusRequest)(nil),              // 26: agent.GetDevEnvStatusRequest
+	(*GetDevEnvStatusReply)(nil),                // 27: agent.GetDevEnvStatusReply
+	(*DevEnvImage)(nil),                         // 28: agent.DevEnvImage
+	(*ListDevEnvsRequest)(nil),                  // 29: agent.ListDevEnvsRequest
+	(*ListDevEnvsReply)(nil),                    // 30: agent.ListDevEnvsReply
+	(*DevEnvSummary)(nil),                       // 31: agent.DevEnvSummary
+	(*DevEnvRepositoryStatus)(nil),              // 32: agent.DevEnvRepositoryStatus
+	(*DevEnvRepositoryHookStatus)(nil),          // 33: agent.DevEnvRepositoryHookStatus
+	(*HookRunResult)(nil),                       // 34: agent.HookRunResult
+	(*WatchOperationRequest)(nil),               // 35: agent.WatchOperationRequest
+	(*WatchOperationReply)(nil),                 // 36: agent.WatchOperationReply
+	(*SnapshotWorkspaceRequest)(nil),            // 37: agent.SnapshotWorkspaceRequest
+	(*SnapshotWorkspaceRepositoryExcludes)(nil), // 38: agent.SnapshotWorkspaceRepositoryExcludes
+	(*SnapshotWorkspaceReply)(nil),              // 39: agent.SnapshotWorkspaceReply
+	(*RestoreWorkspaceRequest)(nil),             // 40: agent.RestoreWorkspaceRequest
+	(*RestoreWorkspaceReply)(nil),               // 41: agent.RestoreWorkspaceReply
+	(*RollbackDevEnvRequest)(nil),               // 42: agent.RollbackDevEnvRequest
+	(*RollbackDevEnvReply)(nil),                 // 43: agent.RollbackDevEnvReply
+	nil,                                         // 44: agent.BuildAndStartDevEnvRequest.BuildArgsEntry
+}
+var file_agent_proto_depIdxs = []int32{
+	0,  // 0: agent.InitInstanceRequest.on_conflict:type_name -> agent.OperationConflictPolicy
+	0,  // 1: agent.BuildAndStartDevEnvRequest.on_conflict:type_name -> agent.OperationConflictPolicy
+	3,  // 2: agent.BuildAndStartDevEnvRequest.workspace_sync_mode:type_name -> agent.WorkspaceSyncMode
+	13, // 3: agent.BuildAndStartDevEnvRequest.default_clone_options:type_name -> agent.CloneOptions
+	14, // 4: agent.BuildAndStartDevEnvRequest.repositories_clone_options:type_name -> agent.RepositoryCloneOptions
+	2,  // 5: agent.BuildAndStartDevEnvRequest.workspace_layout:type_name -> agent.WorkspaceLayout
+	15, // 6: agent.BuildAndStartDevEnvRequest.retry_policy:type_name -> agent.RetryPolicy
+	1,  // 7: agent.BuildAndStartDevEnvRequest.hooks_failure_policy:type_name -> agent.HookFailurePolicy
+	12, // 8: agent.BuildAndStartDevEnvRequest.image_build_options:type_name -> agent.ImageBuildOptions
+	11, // 9: agent.BuildAndStartDevEnvRequest.build_secrets:type_name -> agent.BuildSecret
+	44, // 10: agent.BuildAndStartDevEnvRequest.build_args:type_name -> agent.BuildAndStartDevEnvRequest.BuildArgsEntry
+	13, // 11: agent.RepositoryCloneOptions.options:type_name -> agent.CloneOptions
+	20, // 12: agent.BuildAndStartDevEnvReply.step_event:type_name -> agent.StepEvent
+	21, // 13: agent.BuildAndStartDevEnvReply.docker_build_progress:type_name -> agent.DockerBuildProgress
+	23, // 14: agent.BuildAndStartDevEnvReply.result:type_name -> agent.BuildAndStartDevEnvResult
+	19, // 15: agent.BuildAndStartDevEnvReply.resolved_repository:type_name -> agent.ResolvedRepository
+	17, // 16: agent.BuildAndStartDevEnvReply.git_clone_progress:type_name -> agent.GitCloneProgress
+	18, // 17: agent.BuildAndStartDevEnvReply.git_clone_retry:type_name -> agent.GitCloneRetry
+	22, // 18: agent.BuildAndStartDevEnvReply.docker_build_context:type_name -> agent.DockerBuildContext
+	4,  // 19: agent.StepEvent.step_type:type_name -> agent.StepType
+	5,  // 20: agent.StepEvent.status:type_name -> agent.StepStatus
+	6,  // 21: agent.StepEvent.error_code:type_name -> agent.ErrorCode
+	6,  // 22: agent.BuildAndStartDevEnvResult.error_code:type_name -> agent.ErrorCode
+	20, // 23: agent.BuildAndStartDevEnvResult.steps:type_name -> agent.StepEvent
+	0,  // 24: agent.StopDevEnvRequest.on_conflict:type_name -> agent.OperationConflictPolicy
+	32, // 25: agent.GetDevEnvStatusReply.repositories:type_name -> agent.DevEnvRepositoryStatus
+	7,  // 26: agent.GetDevEnvStatusReply.current_operation:type_name -> agent.Operation
+	28, // 27: agent.GetDevEnvStatusReply.images:type_name -> agent.DevEnvImage
+	31, // 28: agent.ListDevEnvsReply.dev_envs:type_name -> agent.DevEnvSummary
+	33, // 29: agent.DevEnvRepositoryStatus.hooks:type_name -> agent.DevEnvRepositoryHookStatus
+	34, // 30: agent.DevEnvRepositoryHookStatus.last_run:type_name -> agent.HookRunResult
+	9,  // 31: agent.WatchOperationReply.init_instance_reply:type_name -> agent.InitInstanceReply
+	16, // 32: agent.WatchOperationReply.build_and_start_dev_env_reply:type_name -> agent.BuildAndStartDevEnvReply
+	25, // 33: agent.WatchOperationReply.stop_dev_env_reply:type_name -> agent.StopDevEnvReply
+	43, // 34: agent.WatchOperationReply.rollback_dev_env_reply:type_name -> agent.RollbackDevEnvReply
+	0,  // 35: agent.SnapshotWorkspaceRequest.on_conflict:type_name -> agent.OperationConflictPolicy
+	38, // 36: agent.SnapshotWorkspaceRequest.repositories_excludes:type_name -> agent.SnapshotWorkspaceRepositoryExcludes
+	0,  // 37: agent.RestoreWorkspaceRequest.on_conflict:type_name -> agent.OperationConflictPolicy
+	0,  // 38: agent.RollbackDevEnvRequest.on_conflict:type_name -> agent.OperationConflictPolicy
+	28, // 39: agent.RollbackDevEnvReply.image:type_name -> agent.DevEnvImage
+	8,  // 40: agent.Agent.InitInstance:input_type -> agent.InitInstanceRequest
+	10, // 41: agent.Agent.BuildAndStartDevEnv:input_type -> agent.BuildAndStartDevEnvRequest
+	24, // 42: agent.Agent.StopDevEnv:input_type -> agent.StopDevEnvRequest
+	26, // 43: agent.Agent.GetDevEnvStatus:input_type -> agent.GetDevEnvStatusRequest
+	29, // 44: agent.Agent.ListDevEnvs:input_type -> agent.ListDevEnvsRequest
+	35, // 45: agent.Agent.WatchOperation:input_type -> agent.WatchOperationRequest
+	37, // 46: agent.Agent.SnapshotWorkspace:input_type -> agent.SnapshotWorkspaceRequest
+	40, // 47: agent.Agent.RestoreWorkspace:input_type -> agent.RestoreWorkspaceRequest
+	42, // 48: agent.Agent.RollbackDevEnv:input_type -> agent.RollbackDevEnvRequest
+	9,  // 49: agent.Agent.InitInstance:output_type -> agent.InitInstanceReply
+	16, // 50: agent.Agent.BuildAndStartDevEnv:output_type -> agent.BuildAndStartDevEnvReply
+	25, // 51: agent.Agent.StopDevEnv:output_type -> agent.StopDevEnvReply
+	27, // 52: agent.Agent.GetDevEnvStatus:output_type -> agent.GetDevEnvStatusReply
+	30, // 53: agent.Agent.ListDevEnvs:output_type -> agent.ListDevEnvsReply
+	36, // 54: agent.Agent.WatchOperation:output_type -> agent.WatchOperationReply
+	39, // 55: agent.Agent.SnapshotWorkspace:output_type -> agent.SnapshotWorkspaceReply
+	41, // 56: agent.Agent.RestoreWorkspace:output_type -> agent.RestoreWorkspaceReply
+	43, // 57: agent.Agent.RollbackDevEnv:output_type -> agent.RollbackDevEnvReply
+	49, // [49:58] is the sub-list for method output_type
+	40, // [40:49] is the sub-list for method input_type
+	40, // [40:40] is the sub-list for extension type_name
+	40, // [40:40] is the sub-list for extension extendee
+	0,  // [0:40] is the sub-list for field type_name
+}
+
+func init() { file_agent_proto_init() }
+func file_agent_proto_init() {
+	if File_agent_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_agent_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Operation); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agent_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*InitInstanceRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agent_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*InitInstanceReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agent_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BuildAndStartDevEnvRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agent_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BuildSecret); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agent_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ImageBuildOptions); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agent_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CloneOptions); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agent_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RepositoryCloneOptions); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agent_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RetryPolicy); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agent_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BuildAndStartDevEnvReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agent_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GitCloneProgress); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agent_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GitCloneRetry); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agent_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResolvedRepository); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agent_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StepEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agent_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DockerBuildProgress); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -399,8 +4231,8 @@ func file_agent_proto_init() {
 				return nil
 			}
 		}
-		file_agent_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*InitInstanceReply); i {
+		file_agent_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DockerBuildContext); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -411,8 +4243,8 @@ func file_agent_proto_init() {
 				return nil
 			}
 		}
-		file_agent_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*BuildAndStartDevEnvRequest); i {
+		file_agent_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BuildAndStartDevEnvResult); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -423,8 +4255,236 @@ func file_agent_proto_init() {
 				return nil
 			}
 		}
-		file_agent_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*BuildAndStartDevEnvReply); i {
+		file_agent_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StopDevEnvRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agent_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StopDevEnvReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agent_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetDevEnvStatusRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agent_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetDevEnvStatusReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agent_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DevEnvImage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agent_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListDevEnvsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agent_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListDevEnvsReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agent_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DevEnvSummary); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agent_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DevEnvRepositoryStatus); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agent_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DevEnvRepositoryHookStatus); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agent_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*HookRunResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agent_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WatchOperationRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agent_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WatchOperationReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agent_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SnapshotWorkspaceRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agent_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SnapshotWorkspaceRepositoryExcludes); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agent_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SnapshotWorkspaceReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agent_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RestoreWorkspaceRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agent_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RestoreWorkspaceReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agent_proto_msgTypes[35].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RollbackDevEnvRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agent_proto_msgTypes[36].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RollbackDevEnvReply); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -436,19 +4496,28 @@ func file_agent_proto_init() {
 			}
 		}
 	}
-	file_agent_proto_msgTypes[1].OneofWrappers = []interface{}{}
+	file_agent_proto_msgTypes[2].OneofWrappers = []interface{}{}
+	file_agent_proto_msgTypes[17].OneofWrappers = []interface{}{}
+	file_agent_proto_msgTypes[26].OneofWrappers = []interface{}{}
+	file_agent_proto_msgTypes[29].OneofWrappers = []interface{}{
+		(*WatchOperationReply_InitInstanceReply)(nil),
+		(*WatchOperationReply_BuildAndStartDevEnvReply)(nil),
+		(*WatchOperationReply_StopDevEnvReply)(nil),
+		(*WatchOperationReply_RollbackDevEnvReply)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_agent_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   4,
+			NumEnums:      7,
+			NumMessages:   38,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_agent_proto_goTypes,
 		DependencyIndexes: file_agent_proto_depIdxs,
+		EnumInfos:         file_agent_proto_enumTypes,
 		MessageInfos:      file_agent_proto_msgTypes,
 	}.Build()
 	File_agent_proto = out.File
@@ -25,6 +25,13 @@ const _ = grpc.SupportPackageIsVersion7
 type AgentClient interface {
 	InitInstance(ctx context.Context, in *InitInstanceRequest, opts ...grpc.CallOption) (Agent_InitInstanceClient, error)
 	BuildAndStartDevEnv(ctx context.Context, in *BuildAndStartDevEnvRequest, opts ...grpc.CallOption) (Agent_BuildAndStartDevEnvClient, error)
+	StopDevEnv(ctx context.Context, in *StopDevEnvRequest, opts ...grpc.CallOption) (Agent_StopDevEnvClient, error)
+	GetDevEnvStatus(ctx context.Context, in *GetDevEnvStatusRequest, opts ...grpc.CallOption) (*GetDevEnvStatusReply, error)
+	ListDevEnvs(ctx context.Context, in *ListDevEnvsRequest, opts ...grpc.CallOption) (*ListDevEnvsReply, error)
+	WatchOperation(ctx context.Context, in *WatchOperationRequest, opts ...grpc.CallOption) (Agent_WatchOperationClient, error)
+	SnapshotWorkspace(ctx context.Context, in *SnapshotWorkspaceRequest, opts ...grpc.CallOption) (Agent_SnapshotWorkspaceClient, error)
+	RestoreWorkspace(ctx context.Context, opts ...grpc.CallOption) (Agent_RestoreWorkspaceClient, error)
+	RollbackDevEnv(ctx context.Context, in *RollbackDevEnvRequest, opts ...grpc.CallOption) (Agent_RollbackDevEnvClient, error)
 }
 
 type agentClient struct {
@@ -99,12 +106,199 @@ func (x *agentBuildAndStartDevEnvClient) Recv() (*BuildAndStartDevEnvReply, erro
 	return m, nil
 }
 
+func (c *agentClient) StopDevEnv(ctx context.Context, in *StopDevEnvRequest, opts ...grpc.CallOption) (Agent_StopDevEnvClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Agent_ServiceDesc.Streams[2], "/agent.Agent/StopDevEnv", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &agentStopDevEnvClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Agent_StopDevEnvClient interface {
+	Recv() (*StopDevEnvReply, error)
+	grpc.ClientStream
+}
+
+type agentStopDevEnvClient struct {
+	grpc.ClientStream
+}
+
+func (x *agentStopDevEnvClient) Recv() (*StopDevEnvReply, error) {
+	m := new(StopDevEnvReply)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *agentClient) GetDevEnvStatus(ctx context.Context, in *GetDevEnvStatusRequest, opts ...grpc.CallOption) (*GetDevEnvStatusReply, error) {
+	out := new(GetDevEnvStatusReply)
+	err := c.cc.Invoke(ctx, "/agent.Agent/GetDevEnvStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentClient) ListDevEnvs(ctx context.Context, in *ListDevEnvsRequest, opts ...grpc.CallOption) (*ListDevEnvsReply, error) {
+	out := new(ListDevEnvsReply)
+	err := c.cc.Invoke(ctx, "/agent.Agent/ListDevEnvs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentClient) WatchOperation(ctx context.Context, in *WatchOperationRequest, opts ...grpc.CallOption) (Agent_WatchOperationClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Agent_ServiceDesc.Streams[3], "/agent.Agent/WatchOperation", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &agentWatchOperationClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Agent_WatchOperationClient interface {
+	Recv() (*WatchOperationReply, error)
+	grpc.ClientStream
+}
+
+type agentWatchOperationClient struct {
+	grpc.ClientStream
+}
+
+func (x *agentWatchOperationClient) Recv() (*WatchOperationReply, error) {
+	m := new(WatchOperationReply)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *agentClient) SnapshotWorkspace(ctx context.Context, in *SnapshotWorkspaceRequest, opts ...grpc.CallOption) (Agent_SnapshotWorkspaceClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Agent_ServiceDesc.Streams[4], "/agent.Agent/SnapshotWorkspace", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &agentSnapshotWorkspaceClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Agent_SnapshotWorkspaceClient interface {
+	Recv() (*SnapshotWorkspaceReply, error)
+	grpc.ClientStream
+}
+
+type agentSnapshotWorkspaceClient struct {
+	grpc.ClientStream
+}
+
+func (x *agentSnapshotWorkspaceClient) Recv() (*SnapshotWorkspaceReply, error) {
+	m := new(SnapshotWorkspaceReply)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *agentClient) RestoreWorkspace(ctx context.Context, opts ...grpc.CallOption) (Agent_RestoreWorkspaceClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Agent_ServiceDesc.Streams[5], "/agent.Agent/RestoreWorkspace", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &agentRestoreWorkspaceClient{stream}
+	return x, nil
+}
+
+type Agent_RestoreWorkspaceClient interface {
+	Send(*RestoreWorkspaceRequest) error
+	CloseAndRecv() (*RestoreWorkspaceReply, error)
+	grpc.ClientStream
+}
+
+type agentRestoreWorkspaceClient struct {
+	grpc.ClientStream
+}
+
+func (x *agentRestoreWorkspaceClient) Send(m *RestoreWorkspaceRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *agentRestoreWorkspaceClient) CloseAndRecv() (*RestoreWorkspaceReply, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(RestoreWorkspaceReply)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *agentClient) RollbackDevEnv(ctx context.Context, in *RollbackDevEnvRequest, opts ...grpc.CallOption) (Agent_RollbackDevEnvClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Agent_ServiceDesc.Streams[6], "/agent.Agent/RollbackDevEnv", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &agentRollbackDevEnvClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Agent_RollbackDevEnvClient interface {
+	Recv() (*RollbackDevEnvReply, error)
+	grpc.ClientStream
+}
+
+type agentRollbackDevEnvClient struct {
+	grpc.ClientStream
+}
+
+func (x *agentRollbackDevEnvClient) Recv() (*RollbackDevEnvReply, error) {
+	m := new(RollbackDevEnvReply)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // AgentServer is the server API for Agent service.
 // All implementations must embed UnimplementedAgentServer
 // for forward compatibility
 type AgentServer interface {
 	InitInstance(*InitInstanceRequest, Agent_InitInstanceServer) error
 	BuildAndStartDevEnv(*BuildAndStartDevEnvRequest, Agent_BuildAndStartDevEnvServer) error
+	StopDevEnv(*StopDevEnvRequest, Agent_StopDevEnvServer) error
+	GetDevEnvStatus(context.Context, *GetDevEnvStatusRequest) (*GetDevEnvStatusReply, error)
+	ListDevEnvs(context.Context, *ListDevEnvsRequest) (*ListDevEnvsReply, error)
+	WatchOperation(*WatchOperationRequest, Agent_WatchOperationServer) error
+	SnapshotWorkspace(*SnapshotWorkspaceRequest, Agent_SnapshotWorkspaceServer) error
+	RestoreWorkspace(Agent_RestoreWorkspaceServer) error
+	RollbackDevEnv(*RollbackDevEnvRequest, Agent_RollbackDevEnvServer) error
 	mustEmbedUnimplementedAgentServer()
 }
 
@@ -118,6 +312,27 @@ func (UnimplementedAgentServer) InitInstance(*InitInstanceRequest, Agent_InitIns
 func (UnimplementedAgentServer) BuildAndStartDevEnv(*BuildAndStartDevEnvRequest, Agent_BuildAndStartDevEnvServer) error {
 	return status.Errorf(codes.Unimplemented, "method BuildAndStartDevEnv not implemented")
 }
+func (UnimplementedAgentServer) StopDevEnv(*StopDevEnvRequest, Agent_StopDevEnvServer) error {
+	return status.Errorf(codes.Unimplemented, "method StopDevEnv not implemented")
+}
+func (UnimplementedAgentServer) GetDevEnvStatus(context.Context, *GetDevEnvStatusRequest) (*GetDevEnvStatusReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDevEnvStatus not implemented")
+}
+func (UnimplementedAgentServer) ListDevEnvs(context.Context, *ListDevEnvsRequest) (*ListDevEnvsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDevEnvs not implemented")
+}
+func (UnimplementedAgentServer) WatchOperation(*WatchOperationRequest, Agent_WatchOperationServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchOperation not implemented")
+}
+func (UnimplementedAgentServer) SnapshotWorkspace(*SnapshotWorkspaceRequest, Agent_SnapshotWorkspaceServer) error {
+	return status.Errorf(codes.Unimplemented, "method SnapshotWorkspace not implemented")
+}
+func (UnimplementedAgentServer) RestoreWorkspace(Agent_RestoreWorkspaceServer) error {
+	return status.Errorf(codes.Unimplemented, "method RestoreWorkspace not implemented")
+}
+func (UnimplementedAgentServer) RollbackDevEnv(*RollbackDevEnvRequest, Agent_RollbackDevEnvServer) error {
+	return status.Errorf(codes.Unimplemented, "method RollbackDevEnv not implemented")
+}
 func (UnimplementedAgentServer) mustEmbedUnimplementedAgentServer() {}
 
 // UnsafeAgentServer may be embedded to opt out of forward compatibility for this service.
@@ -173,13 +388,168 @@ func (x *agentBuildAndStartDevEnvServer) Send(m *BuildAndStartDevEnvReply) error
 	return x.ServerStream.SendMsg(m)
 }
 
+func _Agent_StopDevEnv_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StopDevEnvRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AgentServer).StopDevEnv(m, &agentStopDevEnvServer{stream})
+}
+
+type Agent_StopDevEnvServer interface {
+	Send(*StopDevEnvReply) error
+	grpc.ServerStream
+}
+
+type agentStopDevEnvServer struct {
+	grpc.ServerStream
+}
+
+func (x *agentStopDevEnvServer) Send(m *StopDevEnvReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Agent_GetDevEnvStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDevEnvStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).GetDevEnvStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/agent.Agent/GetDevEnvStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).GetDevEnvStatus(ctx, req.(*GetDevEnvStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Agent_ListDevEnvs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDevEnvsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).ListDevEnvs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/agent.Agent/ListDevEnvs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).ListDevEnvs(ctx, req.(*ListDevEnvsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Agent_WatchOperation_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchOperationRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AgentServer).WatchOperation(m, &agentWatchOperationServer{stream})
+}
+
+type Agent_WatchOperationServer interface {
+	Send(*WatchOperationReply) error
+	grpc.ServerStream
+}
+
+type agentWatchOperationServer struct {
+	grpc.ServerStream
+}
+
+func (x *agentWatchOperationServer) Send(m *WatchOperationReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Agent_SnapshotWorkspace_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SnapshotWorkspaceRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AgentServer).SnapshotWorkspace(m, &agentSnapshotWorkspaceServer{stream})
+}
+
+type Agent_SnapshotWorkspaceServer interface {
+	Send(*SnapshotWorkspaceReply) error
+	grpc.ServerStream
+}
+
+type agentSnapshotWorkspaceServer struct {
+	grpc.ServerStream
+}
+
+func (x *agentSnapshotWorkspaceServer) Send(m *SnapshotWorkspaceReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Agent_RestoreWorkspace_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(AgentServer).RestoreWorkspace(&agentRestoreWorkspaceServer{stream})
+}
+
+type Agent_RestoreWorkspaceServer interface {
+	SendAndClose(*RestoreWorkspaceReply) error
+	Recv() (*RestoreWorkspaceRequest, error)
+	grpc.ServerStream
+}
+
+type agentRestoreWorkspaceServer struct {
+	grpc.ServerStream
+}
+
+func (x *agentRestoreWorkspaceServer) SendAndClose(m *RestoreWorkspaceReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *agentRestoreWorkspaceServer) Recv() (*RestoreWorkspaceRequest, error) {
+	m := new(RestoreWorkspaceRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Agent_RollbackDevEnv_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(RollbackDevEnvRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AgentServer).RollbackDevEnv(m, &agentRollbackDevEnvServer{stream})
+}
+
+type Agent_RollbackDevEnvServer interface {
+	Send(*RollbackDevEnvReply) error
+	grpc.ServerStream
+}
+
+type agentRollbackDevEnvServer struct {
+	grpc.ServerStream
+}
+
+func (x *agentRollbackDevEnvServer) Send(m *RollbackDevEnvReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // Agent_ServiceDesc is the grpc.ServiceDesc for Agent service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
 var Agent_ServiceDesc = grpc.ServiceDesc{
 	ServiceName: "agent.Agent",
 	HandlerType: (*AgentServer)(nil),
-	Methods:     []grpc.MethodDesc{},
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetDevEnvStatus",
+			Handler:    _Agent_GetDevEnvStatus_Handler,
+		},
+		{
+			MethodName: "ListDevEnvs",
+			Handler:    _Agent_ListDevEnvs_Handler,
+		},
+	},
 	Streams: []grpc.StreamDesc{
 		{
 			StreamName:    "InitInstance",
@@ -191,6 +561,31 @@ var Agent_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _Agent_BuildAndStartDevEnv_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "StopDevEnv",
+			Handler:       _Agent_StopDevEnv_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchOperation",
+			Handler:       _Agent_WatchOperation_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SnapshotWorkspace",
+			Handler:       _Agent_SnapshotWorkspace_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "RestoreWorkspace",
+			Handler:       _Agent_RestoreWorkspace_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "RollbackDevEnv",
+			Handler:       _Agent_RollbackDevEnv_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "agent.proto",
 }